@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/importer"
+	"room-reservation/internal/repository"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// seedRoom is a room of a seed file.
+type seedRoom struct {
+	ID string `json:"id"`
+	room.Request
+}
+
+// demoRooms are seeded without a seed file.
+var demoRooms = []seedRoom{
+	{ID: "1", Request: room.Request{Name: "Everest", Building: "HQ", Capacity: 8}},
+	{ID: "2", Request: room.Request{Name: "Kilimanjaro", Building: "HQ", Capacity: 12}},
+	{ID: "3", Request: room.Request{Name: "Elbrus", Building: "Annex", Capacity: 4}},
+}
+
+func seed(ctx context.Context, args []string) error {
+	fs := flags("seed")
+	file := fs.String("file", "", `JSON file with the rooms, like [{"id": "1", "name": "Everest", "building": "HQ", "capacity": 8}], demo rooms by default`)
+	cfg, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	rooms := demoRooms
+
+	if *file != "" {
+		b, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(b, &rooms); err != nil {
+			return fmt.Errorf("invalid seed file: %w", err)
+		}
+	}
+
+	for i, r := range rooms {
+		if r.ID == "" {
+			return fmt.Errorf("room %d: id is required", i+1)
+		}
+
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("room %s: %w", r.ID, err)
+		}
+	}
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	roomRepo := repository.NewRoomRepository(db)
+
+	for _, r := range rooms {
+		data := room.Room{ID: r.ID, Name: r.Name, Building: r.Building, Capacity: r.Capacity}
+		if err := roomRepo.Save(ctx, data); err != nil {
+			return fmt.Errorf("room %s: %w", r.ID, err)
+		}
+	}
+
+	fmt.Printf("%d rooms saved\n", len(rooms))
+
+	return nil
+}
+
+func export(ctx context.Context, args []string) error {
+	fs := flags("export")
+	roomID := fs.String("room", "", "only export reservations of this room, every room by default")
+	format := fs.String("format", "json", "json or ics")
+	all := fs.Bool("all", false, "include cancelled reservations")
+	cfg, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	if *format != "json" && *format != "ics" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	reservationRepo := repository.NewReservationRepository(db)
+	roomRepo := repository.NewRoomRepository(db)
+
+	opts := reservation.ListOptions{IncludeCancelled: *all}
+
+	name := "Reservations"
+	roomIDs := []string{*roomID}
+
+	if *roomID == "" {
+		rooms, err := roomRepo.List(ctx, room.Filter{})
+		if err != nil {
+			return err
+		}
+
+		roomIDs = make([]string, len(rooms))
+		for i, r := range rooms {
+			roomIDs[i] = r.ID
+		}
+	} else if data, err := roomRepo.Get(ctx, *roomID); err == nil {
+		name = data.Name
+	}
+
+	data, err := reservationRepo.ListForRooms(ctx, roomIDs, opts)
+	if err != nil {
+		return err
+	}
+
+	if *format == "ics" {
+		return calendar.New(name, data).Encode(os.Stdout)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(data)
+}
+
+func importCommand(ctx context.Context, args []string) error {
+	fs := flags("import")
+	file := fs.String("file", "", "iCalendar file to import")
+	mappingFile := fs.String("mapping", "", `JSON file mapping event locations to room IDs, like {"rooms": {"Everest": "1"}, "default_room_id": ""}`)
+	tz := fs.String("tz", "UTC", "time zone of event times without one")
+	until := fs.String("until", "", "import occurrences of recurring events until this date (YYYY-MM-DD), a year from now by default")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without creating reservations")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	cfg, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	if *file == "" || *mappingFile == "" {
+		return errors.New("-file and -mapping are required")
+	}
+
+	req := importer.Request{TimeZone: *tz, DryRun: *dryRun}
+
+	b, err := os.ReadFile(*mappingFile)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(b, &req.Mapping); err != nil {
+		return fmt.Errorf("invalid mapping: %w", err)
+	}
+
+	if *until != "" {
+		t, err := time.Parse(time.DateOnly, *until)
+		if err != nil {
+			return fmt.Errorf("invalid -until: %w", err)
+		}
+		req.Until = &t
+	}
+
+	b, err = os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	req.Calendar = string(b)
+
+	if err = req.Validate(); err != nil {
+		return err
+	}
+
+	ctx = audit.WithActor(ctx, audit.Actor{ID: "import"})
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repo := schedule.Reservations(repository.NewReservationRepository(db), repository.NewScheduleRepository(db))
+
+	report, err := importer.New(repo).Import(ctx, strings.NewReader(req.Calendar), req.Options(time.Now()))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tROOM\tSTART\tEND\tUID\tSUMMARY\tREASON")
+	for _, item := range report.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", item.Status, item.RoomID,
+			item.StartTime.Format(time.DateTime), item.EndTime.Format(time.DateTime), item.UID, item.Summary, item.Reason)
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	prefix := ""
+	if report.DryRun {
+		prefix = "dry run: "
+	}
+
+	fmt.Printf("\n%s%d created, %d skipped, %d conflicting, %d failed\n", prefix, report.Created, report.Skipped, report.Conflicts, report.Failed)
+
+	return nil
+}
+
+func purge(ctx context.Context, args []string) error {
+	fs := flags("purge")
+	olderThan := fs.Duration("older-than", 0, "delete reservations cancelled longer ago than this, reservations.cancelled_retention by default")
+	cfg, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	if *olderThan == 0 {
+		*olderThan = cfg.Reservations.CancelledRetention
+	}
+
+	if *olderThan <= 0 {
+		return errors.New("-older-than must be positive, cancelled reservations are kept forever")
+	}
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	n, err := repository.NewReservationRepository(db).Purge(ctx, time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d cancelled reservations purged\n", n)
+
+	return nil
+}
+
+func checkConfig(ctx context.Context, args []string) error {
+	cfg, err := flags("check-config").parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(cfg)
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Ping(ctx); err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	if err := db.CheckSchema(ctx); err != nil && !cfg.Database.AutoMigrate {
+		return err
+	}
+
+	fmt.Println("configuration is valid")
+
+	return nil
+}
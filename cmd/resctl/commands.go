@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"room-reservation/pkg/client"
+	"strconv"
+	"time"
+)
+
+// timeLayout is how times are given and shown, in the local time zone.
+const timeLayout = "2006-01-02 15:04"
+
+type env struct {
+	client *client.Client
+	out    *printer
+}
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = map[string]command{}
+
+// commandNames lists commands in the order of the usage.
+var commandNames = []string{"rooms", "availability", "list", "get", "book", "reschedule", "cancel", "tail"}
+
+func init() {
+	for _, c := range []command{
+		{"rooms", "[-building name]", "list rooms", rooms},
+		{"availability", "-room id [-date YYYY-MM-DD | -from time -to time]", "show times a room is open and free", availability},
+		{"list", "-room id [-all]", "list reservations of a room", list},
+		{"get", "id", "show a reservation", get},
+		{"book", "-room id -start time [-end time | -duration 1h]", "book a room", book},
+		{"reschedule", "[-room id] [-start time] [-end time | -duration d] id", "move a reservation", reschedule},
+		{"cancel", "[-reason text] id", "cancel a reservation", cancel},
+		{"tail", "[-room id] [-since event id]", "follow changes to reservations", tail},
+	} {
+		commands[c.name] = c
+	}
+}
+
+func (c command) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: resctl %s %s\n\n%s\n", c.name, c.usage, c.summary)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses the flags of the command, expecting n arguments after them.
+func (c command) parseFlags(fs *flag.FlagSet, args []string, n int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != n {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	return nil
+}
+
+// timeFlag is a time in the local time zone, set with timeLayout or RFC 3339.
+type timeFlag struct {
+	time.Time
+}
+
+func (t *timeFlag) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(timeLayout)
+}
+
+func (t *timeFlag) Set(s string) (err error) {
+	if t.Time, err = time.ParseInLocation(timeLayout, s, time.Local); err == nil {
+		return nil
+	}
+
+	t.Time, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("expected %q or RFC 3339", timeLayout)
+	}
+
+	return nil
+}
+
+type reservationView struct {
+	ID           string    `json:"id"`
+	RoomID       string    `json:"room_id"`
+	UserID       string    `json:"user_id,omitempty"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	Status       string    `json:"status"`
+	CancelReason string    `json:"cancel_reason,omitempty"`
+}
+
+func toReservationView(data client.Reservation) reservationView {
+	return reservationView{
+		ID:           data.ID,
+		RoomID:       data.RoomID,
+		UserID:       data.UserID,
+		StartTime:    data.StartTime.Local(),
+		EndTime:      data.EndTime.Local(),
+		Status:       data.Status,
+		CancelReason: data.CancelReason,
+	}
+}
+
+var reservationHeader = []string{"ID", "ROOM", "USER", "START", "END", "STATUS", "REASON"}
+
+func (r reservationView) row() []string {
+	return []string{r.ID, r.RoomID, r.UserID, r.StartTime.Format(timeLayout), r.EndTime.Format(timeLayout), r.Status, r.CancelReason}
+}
+
+func rooms(ctx context.Context, e *env, args []string) error {
+	cmd := commands["rooms"]
+	fs := cmd.flags()
+	building := fs.String("building", "", "only list rooms of this building")
+	if err := cmd.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	data, err := e.client.ListRooms(ctx, *building)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(data))
+	for i, r := range data {
+		rows[i] = []string{r.ID, r.Name, r.Building, strconv.Itoa(r.Capacity)}
+	}
+
+	return e.out.print(data, []string{"ID", "NAME", "BUILDING", "CAPACITY"}, rows)
+}
+
+type slotView struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func availability(ctx context.Context, e *env, args []string) error {
+	cmd := commands["availability"]
+	fs := cmd.flags()
+	roomID := fs.String("room", "", "room id")
+	date := fs.String("date", "", "day to show (YYYY-MM-DD), today by default")
+	var from, to timeFlag
+	fs.Var(&from, "from", "start of the time to show, instead of -date")
+	fs.Var(&to, "to", "end of the time to show, instead of -date")
+	if err := cmd.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	if *roomID == "" {
+		return errors.New("-room is required")
+	}
+
+	if from.IsZero() != to.IsZero() {
+		return errors.New("-from and -to are given together")
+	}
+
+	if from.IsZero() {
+		day := time.Now()
+		if *date != "" {
+			var err error
+			if day, err = time.ParseInLocation(time.DateOnly, *date, time.Local); err != nil {
+				return fmt.Errorf("invalid -date: %w", err)
+			}
+		}
+
+		from.Time = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+		to.Time = from.AddDate(0, 0, 1)
+	}
+
+	if !to.After(from.Time) {
+		return errors.New("-to must be after -from")
+	}
+
+	data, err := e.client.RoomAvailability(ctx, *roomID, from.Time, to.Time)
+	if err != nil {
+		return err
+	}
+
+	res := make([]slotView, len(data.Slots))
+	rows := make([][]string, len(data.Slots))
+	for i, s := range data.Slots {
+		res[i] = slotView{Start: s.Start.Local(), End: s.End.Local()}
+		rows[i] = []string{res[i].Start.Format(timeLayout), res[i].End.Format(timeLayout), s.End.Sub(s.Start).String()}
+	}
+
+	return e.out.print(res, []string{"FREE FROM", "UNTIL", "DURATION"}, rows)
+}
+
+func list(ctx context.Context, e *env, args []string) error {
+	cmd := commands["list"]
+	fs := cmd.flags()
+	roomID := fs.String("room", "", "room id")
+	all := fs.Bool("all", false, "include cancelled reservations")
+	if err := cmd.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	if *roomID == "" {
+		return errors.New("-room is required")
+	}
+
+	data, err := e.client.ListRoomReservations(ctx, *roomID, client.ListOptions{IncludeCancelled: *all})
+	if err != nil {
+		return err
+	}
+
+	res := make([]reservationView, len(data))
+	rows := make([][]string, len(data))
+	for i, r := range data {
+		res[i] = toReservationView(r)
+		rows[i] = res[i].row()
+	}
+
+	return e.out.print(res, reservationHeader, rows)
+}
+
+func get(ctx context.Context, e *env, args []string) error {
+	cmd := commands["get"]
+	fs := cmd.flags()
+	if err := cmd.parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	data, err := e.client.GetReservation(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	res := toReservationView(data)
+
+	return e.out.print(res, reservationHeader, [][]string{res.row()})
+}
+
+func book(ctx context.Context, e *env, args []string) error {
+	cmd := commands["book"]
+	fs := cmd.flags()
+	roomID := fs.String("room", "", "room id")
+	var start, end timeFlag
+	fs.Var(&start, "start", "start time, "+timeLayout)
+	fs.Var(&end, "end", "end time, instead of -duration")
+	duration := fs.Duration("duration", time.Hour, "length of the reservation")
+	if err := cmd.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	if *roomID == "" || start.IsZero() {
+		return errors.New("-room and -start are required")
+	}
+
+	if end.IsZero() {
+		end.Time = start.Add(*duration)
+	}
+
+	ID, err := e.client.CreateReservation(ctx, client.ReservationRequest{RoomID: *roomID, StartTime: start.Time, EndTime: end.Time})
+	if err != nil {
+		if errors.Is(err, client.ErrorOverlaps) {
+			return errors.New("the room is already booked at that time")
+		}
+
+		if errors.Is(err, client.ErrorClosed) {
+			return errors.New("the room is closed at that time")
+		}
+
+		return err
+	}
+
+	return get(ctx, e, []string{ID})
+}
+
+func reschedule(ctx context.Context, e *env, args []string) error {
+	cmd := commands["reschedule"]
+	fs := cmd.flags()
+	roomID := fs.String("room", "", "room to move the reservation to, unchanged by default")
+	var start, end timeFlag
+	fs.Var(&start, "start", "new start time, unchanged by default")
+	fs.Var(&end, "end", "new end time, instead of -duration")
+	duration := fs.Duration("duration", 0, "new length of the reservation, unchanged by default")
+	if err := cmd.parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	ID := fs.Arg(0)
+
+	data, err := e.client.GetReservation(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	if start.IsZero() {
+		start.Time = data.StartTime
+	}
+
+	if end.IsZero() {
+		if *duration == 0 {
+			*duration = data.EndTime.Sub(data.StartTime)
+		}
+
+		end.Time = start.Add(*duration)
+	}
+
+	err = e.client.UpdateReservation(ctx, ID, client.ReservationRequest{RoomID: *roomID, StartTime: start.Time, EndTime: end.Time})
+	if err != nil {
+		if errors.Is(err, client.ErrorOverlaps) {
+			return errors.New("the room is already booked at that time")
+		}
+
+		if errors.Is(err, client.ErrorClosed) {
+			return errors.New("the room is closed at that time")
+		}
+
+		return err
+	}
+
+	return get(ctx, e, []string{ID})
+}
+
+func cancel(ctx context.Context, e *env, args []string) error {
+	cmd := commands["cancel"]
+	fs := cmd.flags()
+	reason := fs.String("reason", "", "why the reservation is cancelled")
+	if err := cmd.parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	if err := e.client.CancelReservation(ctx, fs.Arg(0), *reason); err != nil {
+		if errors.Is(err, client.ErrorCancelled) {
+			return errors.New("the reservation is already cancelled")
+		}
+
+		return err
+	}
+
+	return get(ctx, e, []string{fs.Arg(0)})
+}
+
+type eventView struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Actor       string          `json:"actor"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Reservation reservationView `json:"reservation"`
+}
+
+func tail(ctx context.Context, e *env, args []string) error {
+	cmd := commands["tail"]
+	fs := cmd.flags()
+	roomID := fs.String("room", "", "only follow reservations of this room")
+	since := fs.Int64("since", 0, "replay events after this event id first")
+	if err := cmd.parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	write, err := e.out.stream([]string{"EVENT", "TIME", "TYPE", "ACTOR", "RESERVATION", "ROOM", "START", "END"})
+	if err != nil {
+		return err
+	}
+
+	err = e.client.StreamEvents(ctx, *roomID, *since, func(ev client.Event) error {
+		res := eventView{
+			ID:          ev.ID,
+			Type:        ev.Type,
+			Actor:       ev.Actor,
+			OccurredAt:  ev.OccurredAt.Local(),
+			Reservation: toReservationView(ev.Reservation),
+		}
+
+		return write(res, []string{
+			strconv.FormatInt(res.ID, 10),
+			res.OccurredAt.Format(time.DateTime),
+			res.Type,
+			res.Actor,
+			res.Reservation.ID,
+			res.Reservation.RoomID,
+			res.Reservation.StartTime.Format(timeLayout),
+			res.Reservation.EndTime.Format(timeLayout),
+		})
+	})
+	if errors.Is(err, context.Canceled) {
+		// interrupted
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFlag(t *testing.T) {
+	var f timeFlag
+	assert.Equal(t, "", f.String())
+
+	require.NoError(t, f.Set("2024-09-02 09:30"))
+	assert.Equal(t, time.Date(2024, 9, 2, 9, 30, 0, 0, time.Local), f.Time, "expected times in the local time zone")
+	assert.Equal(t, "2024-09-02 09:30", f.String())
+
+	require.NoError(t, f.Set("2024-09-02T09:30:00+02:00"))
+	assert.True(t, f.Equal(time.Date(2024, 9, 2, 7, 30, 0, 0, time.UTC)))
+
+	for _, s := range []string{"", "2024-09-02", "09:30", "2024-09-02 9h30", "tomorrow"} {
+		assert.Error(t, f.Set(s), s)
+	}
+
+	fs := flag.NewFlagSet("book", flag.ContinueOnError)
+	var start timeFlag
+	fs.Var(&start, "start", "start time")
+
+	require.NoError(t, fs.Parse([]string{"-start", "2024-09-02 10:00"}))
+	assert.Equal(t, time.Date(2024, 9, 2, 10, 0, 0, 0, time.Local), start.Time)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// config holds named profiles, so that a single config file serves several deployments or identities.
+type config struct {
+	// Profile is used when no profile is given.
+	Profile  string             `json:"profile"`
+	Profiles map[string]profile `json:"profiles"`
+}
+
+type profile struct {
+	BaseURL string `json:"base_url"`
+	APIKey  string `json:"api_key,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+// defaultProfile is used without a config file.
+var defaultProfile = profile{BaseURL: "http://localhost:8080"}
+
+func defaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "resctl", "config.json")
+}
+
+// loadProfile reads a profile of the config file, or the default profile of the file when name is empty.
+func loadProfile(file, name string) (profile, error) {
+	if file == "" {
+		return defaultProfile, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) && name == "" {
+		return defaultProfile, nil
+	}
+
+	if err != nil {
+		return profile{}, err
+	}
+
+	var c config
+	if err = json.Unmarshal(b, &c); err != nil {
+		return profile{}, fmt.Errorf("invalid config file %s: %w", file, err)
+	}
+
+	if name == "" {
+		name = c.Profile
+	}
+
+	if name == "" {
+		return profile{}, fmt.Errorf("no profile given and no default profile in %s", file)
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("profile %q not found in %s", name, file)
+	}
+
+	if p.BaseURL == "" {
+		return profile{}, fmt.Errorf("profile %q has no base_url", name)
+	}
+
+	return p, nil
+}
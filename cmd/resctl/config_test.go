@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, c config) string {
+	t.Helper()
+
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(file, b, 0o600))
+
+	return file
+}
+
+func TestLoadProfile(t *testing.T) {
+	file := writeConfig(t, config{
+		Profile: "prod",
+		Profiles: map[string]profile{
+			"prod":   {BaseURL: "https://rooms.example.com", UserID: "alice@example.com"},
+			"admin":  {BaseURL: "https://rooms.example.com", APIKey: "secret"},
+			"broken": {APIKey: "secret"},
+		},
+	})
+
+	p, err := loadProfile(file, "")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", p.UserID, "expected the default profile of the file")
+
+	p, err = loadProfile(file, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", p.APIKey, "expected the profile given over the default one")
+
+	_, err = loadProfile(file, "staging")
+	assert.ErrorContains(t, err, `profile "staging" not found`)
+
+	_, err = loadProfile(file, "broken")
+	assert.ErrorContains(t, err, "has no base_url")
+
+	missing := filepath.Join(t.TempDir(), "config.json")
+
+	p, err = loadProfile(missing, "")
+	require.NoError(t, err)
+	assert.Equal(t, defaultProfile, p, "expected the default profile without config file")
+
+	_, err = loadProfile(missing, "admin")
+	assert.Error(t, err, "expected profiles given to require a config file")
+
+	p, err = loadProfile("", "")
+	require.NoError(t, err)
+	assert.Equal(t, defaultProfile, p)
+
+	_, err = loadProfile(writeConfig(t, config{Profiles: map[string]profile{"prod": {BaseURL: "https://rooms.example.com"}}}), "")
+	assert.ErrorContains(t, err, "no default profile")
+
+	invalid := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("{"), 0o600))
+
+	_, err = loadProfile(invalid, "")
+	assert.ErrorContains(t, err, "invalid config file")
+}
+
+func TestProfilePrecedence(t *testing.T) {
+	servers := map[string]*httptest.Server{}
+	hits := map[string]int{}
+
+	profiles := map[string]profile{}
+	for _, name := range []string{"default", "env", "flag"} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[name]++
+			w.Write([]byte(`{"success":true,"data":[]}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		servers[name] = srv
+		profiles[name] = profile{BaseURL: srv.URL}
+	}
+
+	file := writeConfig(t, config{Profile: "default", Profiles: profiles})
+
+	require.Equal(t, 0, run([]string{"-config", file, "-o", "json", "rooms"}))
+	assert.Equal(t, map[string]int{"default": 1}, hits)
+
+	t.Setenv("RESCTL_PROFILE", "env")
+
+	require.Equal(t, 0, run([]string{"-config", file, "-o", "json", "rooms"}))
+	assert.Equal(t, map[string]int{"default": 1, "env": 1}, hits, "expected RESCTL_PROFILE over the default profile")
+
+	require.Equal(t, 0, run([]string{"-config", file, "-profile", "flag", "-o", "json", "rooms"}))
+	assert.Equal(t, map[string]int{"default": 1, "env": 1, "flag": 1}, hits, "expected -profile over RESCTL_PROFILE")
+
+	assert.Equal(t, 1, run([]string{"-config", file, "-profile", "staging", "rooms"}), "expected unknown profiles to fail")
+}
@@ -0,0 +1,103 @@
+// Command resctl books rooms from a terminal through the API of the service.
+//
+//	resctl rooms -building HQ
+//	resctl availability -room 1 -date 2024-09-02
+//	resctl book -room 1 -start "2024-09-02 09:00" -duration 1h
+//	resctl reschedule -start "2024-09-02 10:00" 5f0c9e1d
+//	resctl cancel -reason "meeting moved" 5f0c9e1d
+//	resctl -o json tail -room 1
+//
+// The service and credentials are read from a profile of the config file, $XDG_CONFIG_HOME/resctl/config.json
+// by default:
+//
+//	{
+//		"profile": "prod",
+//		"profiles": {
+//			"prod": {"base_url": "https://rooms.example.com", "user_id": "alice@example.com"},
+//			"admin": {"base_url": "https://rooms.example.com", "api_key": "secret"}
+//		}
+//	}
+//
+// Times are given and shown in the local time zone.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"room-reservation/pkg/client"
+	"syscall"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("resctl", flag.ContinueOnError)
+	configFile := fs.String("config", defaultConfigFile(), "config file with the profiles")
+	profileName := fs.String("profile", os.Getenv("RESCTL_PROFILE"), "profile to use, the default profile of the config file if empty")
+	output := fs.String("o", "table", "output format: table, json or csv")
+	fs.Usage = func() { usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "resctl: unknown command %q\n\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	out, err := newPrinter(os.Stdout, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "resctl:", err)
+		return 2
+	}
+
+	p, err := loadProfile(*configFile, *profileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "resctl:", err)
+		return 1
+	}
+
+	c, err := client.New(p.BaseURL, client.WithAPIKey(p.APIKey), client.WithUserID(p.UserID))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "resctl:", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = cmd.run(ctx, &env{client: c, out: out}, fs.Args()[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resctl %s: %s\n", cmd.name, err)
+		return 1
+	}
+
+	return 0
+}
+
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintln(fs.Output(), "Usage: resctl [flags] <command> [command flags] [args]\n\nCommands:")
+	for _, name := range commandNames {
+		fmt.Fprintf(fs.Output(), "  %-14s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(fs.Output(), "\nFlags:")
+	fs.PrintDefaults()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer writes results as a table, JSON or CSV. Tables and CSV show rows of columns, JSON shows the values
+// returned by the API.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case "table", "json", "csv":
+		return &printer{w: w, format: format}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+// print writes v, or its rows under the header.
+func (p *printer) print(v any, header []string, rows [][]string) error {
+	switch p.format {
+	case "json":
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "csv":
+		w := csv.NewWriter(p.w)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+
+		// WriteAll flushes and reports errors of earlier writes
+		return w.WriteAll(rows)
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// stream writes the header and returns a function writing values one at a time as they come,
+// JSON is written a value per line.
+func (p *printer) stream(header []string) (func(v any, row []string) error, error) {
+	switch p.format {
+	case "json":
+		enc := json.NewEncoder(p.w)
+		return func(v any, _ []string) error {
+			return enc.Encode(v)
+		}, nil
+	case "csv":
+		w := csv.NewWriter(p.w)
+		line := func(row []string) error {
+			if err := w.Write(row); err != nil {
+				return err
+			}
+
+			w.Flush()
+			return w.Error()
+		}
+
+		return func(_ any, row []string) error {
+			return line(row)
+		}, line(header)
+	}
+
+	// rows are written as they come, so columns are padded to a fixed width rather than aligned
+	format := strings.Repeat("%-19s ", len(header)-1) + "%s\n"
+	line := func(row []string) error {
+		args := make([]any, len(row))
+		for i, col := range row {
+			args[i] = col
+		}
+
+		_, err := fmt.Fprintf(p.w, format, args...)
+		return err
+	}
+
+	return func(_ any, row []string) error {
+		return line(row)
+	}, line(header)
+}
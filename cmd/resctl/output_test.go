@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+var (
+	testHeader = []string{"ID", "NAME"}
+	testRows   = [][]string{{"1", "Everest"}, {"2", "Kilimanjaro, east wing"}}
+	testValue  = []map[string]string{{"id": "1", "name": "Everest"}}
+)
+
+func TestPrinter(t *testing.T) {
+	_, err := newPrinter(&bytes.Buffer{}, "xml")
+	assert.Error(t, err)
+
+	for format, want := range map[string]string{
+		"table": "ID  NAME\n1   Everest\n2   Kilimanjaro, east wing\n",
+		"csv":   "ID,NAME\n1,Everest\n2,\"Kilimanjaro, east wing\"\n",
+		"json":  "[\n  {\n    \"id\": \"1\",\n    \"name\": \"Everest\"\n  }\n]\n",
+	} {
+		var buf bytes.Buffer
+		p, err := newPrinter(&buf, format)
+		require.NoError(t, err)
+
+		require.NoError(t, p.print(testValue, testHeader, testRows), format)
+		assert.Equal(t, want, buf.String(), format)
+
+		p.w = failingWriter{}
+		assert.Error(t, p.print(testValue, testHeader, testRows), "expected write errors of %s reported", format)
+	}
+}
+
+func TestPrinterStream(t *testing.T) {
+	for format, want := range map[string]string{
+		"table": "ID                  NAME\n1                   Everest\n",
+		"csv":   "ID,NAME\n1,Everest\n",
+		"json":  "{\"id\":\"1\",\"name\":\"Everest\"}\n",
+	} {
+		var buf bytes.Buffer
+		p, err := newPrinter(&buf, format)
+		require.NoError(t, err)
+
+		write, err := p.stream(testHeader)
+		require.NoError(t, err, format)
+		require.NoError(t, write(testValue[0], testRows[0]), format)
+		assert.Equal(t, want, buf.String(), format)
+
+		p.w = failingWriter{}
+		write, err = p.stream(testHeader)
+		if format != "json" {
+			assert.Error(t, err, "expected the header write error of %s reported", format)
+		}
+		assert.Error(t, write(testValue[0], testRows[0]), "expected write errors of %s reported", format)
+	}
+}
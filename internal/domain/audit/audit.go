@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionCancel  = "cancel"
+	ActionRestore = "restore"
+)
+
+// Entry is an append-only record of a change to a reservation.
+// Before and After hold snapshots of the reservation, Before is empty for creations and After for deletions.
+type Entry struct {
+	ID            int64           `db:"id"`
+	ReservationID string          `db:"reservation_id"`
+	Action        string          `db:"action"`
+	Actor         string          `db:"actor"`
+	RequestID     string          `db:"request_id"`
+	Before        json.RawMessage `db:"before"`
+	After         json.RawMessage `db:"after"`
+	CreatedAt     time.Time       `db:"created_at"`
+}
+
+// Actor is who made a change and within which request.
+type Actor struct {
+	ID        string
+	RequestID string
+}
+
+const Anonymous = "anonymous"
+
+type actorCtxKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorCtxKey{}).(Actor)
+	if !ok || actor.ID == "" {
+		actor.ID = Anonymous
+	}
+
+	return actor
+}
+
+type Filter struct {
+	ReservationID string
+	Actor         string
+	Action        string
+	From          time.Time
+	To            time.Time
+	Limit         int
+}
+
+var ErrorInvalidFilter error = errors.New("invalid audit filter")
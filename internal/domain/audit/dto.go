@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// FilterFromQuery reads a filter from reservation_id, actor, action, from, to and limit query parameters.
+// from and to are RFC 3339 timestamps.
+func FilterFromQuery(q url.Values) (Filter, error) {
+	f := Filter{
+		ReservationID: q.Get("reservation_id"),
+		Actor:         q.Get("actor"),
+		Action:        q.Get("action"),
+		Limit:         DefaultLimit,
+	}
+
+	switch f.Action {
+	case "", ActionCreate, ActionUpdate, ActionDelete, ActionCancel, ActionRestore:
+	default:
+		return Filter{}, fmt.Errorf("%w: unknown action %q", ErrorInvalidFilter, f.Action)
+	}
+
+	var err error
+
+	if v := q.Get("from"); v != "" {
+		if f.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return Filter{}, fmt.Errorf("%w: invalid from %v", ErrorInvalidFilter, err)
+		}
+	}
+
+	if v := q.Get("to"); v != "" {
+		if f.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return Filter{}, fmt.Errorf("%w: invalid to %v", ErrorInvalidFilter, err)
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit <= 0 || f.Limit > MaxLimit {
+			return Filter{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrorInvalidFilter, MaxLimit)
+		}
+	}
+
+	return f, nil
+}
+
+type Response struct {
+	ID            int64           `json:"id"`
+	ReservationID string          `json:"reservation_id"`
+	Action        string          `json:"action"`
+	Actor         string          `json:"actor"`
+	RequestID     string          `json:"request_id,omitempty"`
+	Before        json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After         json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func ToResponse(data Entry) Response {
+	return Response{
+		ID:            data.ID,
+		ReservationID: data.ReservationID,
+		Action:        data.Action,
+		Actor:         data.Actor,
+		RequestID:     data.RequestID,
+		Before:        data.Before,
+		After:         data.After,
+		CreatedAt:     data.CreatedAt,
+	}
+}
+
+func ToResponseSlice(data []Entry) []Response {
+	res := make([]Response, 0)
+
+	for _, e := range data {
+		res = append(res, ToResponse(e))
+	}
+
+	return res
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterFromQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Filter
+		err      bool
+	}{
+		{
+			query:    "",
+			expected: Filter{Limit: DefaultLimit},
+		},
+		{
+			query: "reservation_id=946e2eb89bdc&actor=john&action=delete&from=2024-08-29T13:00:00Z&limit=10",
+			expected: Filter{
+				ReservationID: "946e2eb89bdc",
+				Actor:         "john",
+				Action:        ActionDelete,
+				From:          time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC),
+				Limit:         10,
+			},
+		},
+		{
+			query: "action=drop",
+			err:   true,
+		},
+		{
+			query: "to=29-08-2024",
+			err:   true,
+		},
+		{
+			query: "limit=100000",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		q, err := url.ParseQuery(test.query)
+		require.NoError(t, err)
+
+		f, err := FilterFromQuery(q)
+		if test.err {
+			assert.ErrorIs(t, err, ErrorInvalidFilter)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, f)
+		}
+	}
+}
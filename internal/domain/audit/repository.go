@@ -0,0 +1,8 @@
+package audit
+
+import "context"
+
+type Repository interface {
+	History(ctx context.Context, reservationID string) ([]Entry, error)
+	Query(ctx context.Context, filter Filter) ([]Entry, error)
+}
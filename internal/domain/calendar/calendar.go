@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/ical"
+	"time"
+)
+
+// Feed is a secret URL calendar clients subscribe to without sending headers.
+// It follows either the reservations of a room or the reservations made by a user.
+type Feed struct {
+	Token     string    `db:"token"`
+	RoomID    string    `db:"room_id"`
+	UserID    string    `db:"user_id"`
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+var ErrorNotFound error = errors.New("calendar feed not found")
+
+const (
+	ProdID    = "-//room-reservation//Room reservation system//EN"
+	uidDomain = "room-reservation"
+)
+
+// NewToken generates the secret token of a feed.
+func NewToken() string {
+	bytes := make([]byte, 24)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// UID is the iCalendar UID of a reservation, it stays the same for its whole life.
+// Reservations imported from calendars keep the UID of their event.
+func UID(r reservation.Reservation) string {
+	if r.ICalUID != "" {
+		return r.ICalUID
+	}
+
+	return r.ID + "@" + uidDomain
+}
+
+// New builds a calendar of reservations, cancelled reservations are marked as such
+// so that clients remove them.
+func New(name string, data []reservation.Reservation) ical.Calendar {
+	c := ical.Calendar{
+		ProdID: ProdID,
+		Name:   name,
+		Events: make([]ical.Event, 0, len(data)),
+	}
+
+	for _, r := range data {
+		e := ical.Event{
+			UID:          UID(r),
+			Sequence:     r.Sequence,
+			Stamp:        r.UpdatedAt,
+			LastModified: r.UpdatedAt,
+			Start:        r.StartTime,
+			End:          r.EndTime,
+			Summary:      "Room " + r.RoomID,
+			Location:     r.RoomID,
+			Status:       ical.StatusConfirmed,
+		}
+
+		if r.Cancelled() {
+			e.Status = ical.StatusCancelled
+			e.Description = r.CancelReason
+		}
+
+		c.Events = append(c.Events, e)
+	}
+
+	return c
+}
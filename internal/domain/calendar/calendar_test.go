@@ -0,0 +1,32 @@
+package calendar
+
+import (
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/ical"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	start := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+	cancelledAt := start.Add(-time.Hour)
+
+	data := []reservation.Reservation{
+		{ID: "946e2eb89bdc", RoomID: "1", StartTime: start, EndTime: start.Add(time.Hour), Sequence: 2, UpdatedAt: start},
+		{ID: "a1b2c3d4e5f6", RoomID: "1", StartTime: start, EndTime: start.Add(time.Hour), CancelledAt: &cancelledAt, CancelReason: "moved online"},
+	}
+
+	c := New("Room 1", data)
+
+	require.Equal(t, "Room 1", c.Name)
+	require.Len(t, c.Events, 2)
+
+	require.Equal(t, "946e2eb89bdc@room-reservation", c.Events[0].UID, "expected UID derived from reservation ID")
+	require.Equal(t, 2, c.Events[0].Sequence)
+	require.Equal(t, ical.StatusConfirmed, c.Events[0].Status)
+
+	require.Equal(t, ical.StatusCancelled, c.Events[1].Status, "expected cancelled reservation to be marked")
+	require.Equal(t, "moved online", c.Events[1].Description)
+}
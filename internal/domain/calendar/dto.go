@@ -0,0 +1,26 @@
+package calendar
+
+import "time"
+
+// FeedRequest creates a feed of a room, or of the calling user when the room is left out.
+type FeedRequest struct {
+	RoomID string `json:"room_id,omitempty" example:"1"`
+}
+
+type FeedResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url" example:"http://localhost:8080/api/v1/calendar/5f0c9e1d.ics"`
+	RoomID    string    `json:"room_id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToFeedResponse(data Feed, url string) FeedResponse {
+	return FeedResponse{
+		Token:     data.Token,
+		URL:       url,
+		RoomID:    data.RoomID,
+		UserID:    data.UserID,
+		CreatedAt: data.CreatedAt,
+	}
+}
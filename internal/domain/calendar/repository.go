@@ -0,0 +1,9 @@
+package calendar
+
+import "context"
+
+type Repository interface {
+	CreateFeed(ctx context.Context, data Feed) error
+	GetFeed(ctx context.Context, token string) (Feed, error)
+	DeleteFeed(ctx context.Context, token string) error
+}
@@ -0,0 +1,80 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"slices"
+	"time"
+)
+
+// Channel is a chat channel receiving events through an incoming webhook URL, optionally narrowed down
+// to event types and to rooms or buildings.
+type Channel struct {
+	ID         string    `db:"id"`
+	Name       string    `db:"name"`
+	URL        string    `db:"url"`
+	RoomIDs    []string  `db:"room_ids"`
+	Buildings  []string  `db:"buildings"`
+	EventTypes []string  `db:"event_types"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Matches reports whether the channel wants to receive e about a room of the given building.
+// Channels following rooms and buildings receive events of either.
+func (c *Channel) Matches(e event.Event, building string) bool {
+	if len(c.EventTypes) > 0 && !slices.Contains(c.EventTypes, e.Type) {
+		return false
+	}
+
+	if len(c.RoomIDs) == 0 && len(c.Buildings) == 0 {
+		return true
+	}
+
+	return slices.Contains(c.RoomIDs, e.RoomID) || (building != "" && slices.Contains(c.Buildings, building))
+}
+
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// Message is an event formatted for a channel, kept as a log of what was posted.
+type Message struct {
+	ID             int64           `db:"id"`
+	ChannelID      string          `db:"channel_id"`
+	EventID        int64           `db:"event_id"`
+	EventType      string          `db:"event_type"`
+	Payload        json.RawMessage `db:"payload"`
+	Status         string          `db:"status"`
+	Attempts       int             `db:"attempts"`
+	Rejections     int             `db:"rejections"`
+	ResponseStatus int             `db:"response_status"`
+	LastError      string          `db:"last_error"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at"`
+	SentAt         *time.Time      `db:"sent_at"`
+	CreatedAt      time.Time       `db:"created_at"`
+}
+
+// Result is the outcome of posting a message. Rate limited messages are posted again after RetryAfter
+// without counting as a failed attempt, but only MaxRejections times once the channel itself refuses them.
+type Result struct {
+	ResponseStatus int
+	RetryAfter     time.Duration
+	Err            error
+}
+
+const (
+	// MaxAttempts is how many times a message is posted before giving up on it.
+	MaxAttempts = 5
+	// MaxRejections is how many times a message rejected with 429 Too Many Requests is posted again before
+	// giving up on it. Messages held back by the rate limit of the service are never given up on.
+	MaxRejections = 20
+)
+
+// Backoff is how long to wait before attempts to post a message.
+var Backoff = event.Backoff{Min: 10 * time.Second, Max: 30 * time.Minute}
+
+var ErrorNotFound error = errors.New("chat channel not found")
+var ErrorRateLimited error = errors.New("chat channel rate limited")
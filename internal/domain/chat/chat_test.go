@@ -0,0 +1,71 @@
+package chat
+
+import (
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelMatches(t *testing.T) {
+	e := event.Event{Type: event.TypeReservationCreated, RoomID: "1"}
+
+	tests := []struct {
+		channel  Channel
+		building string
+		expected bool
+	}{
+		{channel: Channel{}, expected: true},
+		{channel: Channel{EventTypes: []string{event.TypeReservationCreated}}, expected: true},
+		{channel: Channel{EventTypes: []string{event.TypeReservationCancelled}}, expected: false},
+		{channel: Channel{RoomIDs: []string{"1"}}, expected: true},
+		{channel: Channel{RoomIDs: []string{"2"}}, expected: false},
+		{channel: Channel{Buildings: []string{"HQ"}}, building: "HQ", expected: true},
+		{channel: Channel{Buildings: []string{"HQ"}}, building: "Annex", expected: false},
+		{channel: Channel{Buildings: []string{"HQ"}}, expected: false},
+		{channel: Channel{RoomIDs: []string{"2"}, Buildings: []string{"HQ"}}, building: "HQ", expected: true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.channel.Matches(e, test.building), "%+v in %q", test.channel, test.building)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	r := reservation.Reservation{
+		ID:           "abc",
+		RoomID:       "1",
+		UserID:       "alice",
+		StartTime:    time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2024, 9, 2, 11, 30, 0, 0, time.UTC),
+		CancelReason: "Moved <online>",
+		CancelledBy:  "bob",
+	}
+	rm := room.Room{ID: "1", Name: "Everest", Building: "HQ"}
+
+	m := Format(event.Event{Type: event.TypeReservationCreated}, r, rm, "https://rooms.example.com/")
+	require.Len(t, m.Attachments, 1)
+
+	a := m.Attachments[0]
+	assert.Equal(t, "Everest booked", a.Title)
+	assert.Equal(t, "Everest booked for Mon, 02 Sep 2024 10:00–11:30 UTC", a.Fallback)
+	assert.Equal(t, "https://rooms.example.com/api/v1/reservations/abc", a.TitleLink)
+	assert.Contains(t, a.Text, "<https://rooms.example.com/api/v1/rooms/1/calendar.ics|Room calendar>")
+	assert.Equal(t, "Everest, HQ", a.Fields[1].Value)
+	assert.Equal(t, "alice", a.Fields[2].Value)
+	assert.Len(t, a.Fields, 3, "expected no cancellation details")
+
+	r.EndTime = time.Date(2024, 9, 3, 9, 0, 0, 0, time.UTC)
+
+	m = Format(event.Event{Type: event.TypeReservationCancelled}, r, room.Room{}, "")
+	a = m.Attachments[0]
+	assert.Equal(t, "Booking of Room 1 cancelled", a.Title)
+	assert.Equal(t, "Mon, 02 Sep 2024 10:00 – Tue, 03 Sep 2024 09:00 UTC", a.Fields[0].Value)
+	assert.Equal(t, "Moved &lt;online&gt;", a.Fields[4].Value, "expected reason to be escaped")
+	assert.Empty(t, a.TitleLink, "expected no links without a base url")
+	assert.Empty(t, a.Text)
+}
@@ -0,0 +1,77 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"room-reservation/internal/domain/event"
+	"time"
+)
+
+type CreateRequest struct {
+	Name       string   `json:"name" example:"#facilities"`
+	URL        string   `json:"url" example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	RoomIDs    []string `json:"room_ids" example:"1"`
+	Buildings  []string `json:"buildings" example:"HQ"`
+	EventTypes []string `json:"event_types" example:"ReservationCreated,ReservationCancelled"`
+}
+
+func (r *CreateRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https url")
+	}
+
+	for _, t := range r.EventTypes {
+		switch t {
+		case event.TypeReservationCreated, event.TypeReservationUpdated, event.TypeReservationCancelled, event.TypeReservationRestored:
+		default:
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+
+	return nil
+}
+
+type Response struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// URL is only shown in full when the channel is created, the path of incoming webhook URLs is a secret.
+	URL        string    `json:"url"`
+	RoomIDs    []string  `json:"room_ids"`
+	Buildings  []string  `json:"buildings"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ToResponse leaves out the path of the URL.
+func ToResponse(data Channel) Response {
+	res := Response{
+		ID:         data.ID,
+		Name:       data.Name,
+		RoomIDs:    data.RoomIDs,
+		Buildings:  data.Buildings,
+		EventTypes: data.EventTypes,
+		CreatedAt:  data.CreatedAt,
+	}
+
+	if u, err := url.Parse(data.URL); err == nil {
+		res.URL = u.Scheme + "://" + u.Host + "/…"
+	}
+
+	return res
+}
+
+func ToResponseSlice(data []Channel) []Response {
+	res := make([]Response, 0)
+
+	for _, c := range data {
+		res = append(res, ToResponse(c))
+	}
+
+	return res
+}
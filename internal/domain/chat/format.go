@@ -0,0 +1,97 @@
+package chat
+
+import (
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/slack"
+	"strings"
+)
+
+const (
+	colorBooked    = "#2eb886"
+	colorChanged   = "#439fe0"
+	colorCancelled = "#d40e0d"
+
+	dateTimeLayout = "Mon, 02 Jan 2006 15:04"
+	timeLayout     = "15:04"
+)
+
+// Format describes e, changing r in room rm, as a chat message. Links to the reservation and the calendar
+// of the room are added when baseURL, the public URL of the service, is set.
+func Format(e event.Event, r reservation.Reservation, rm room.Room, baseURL string) slack.Message {
+	name := rm.Name
+	if name == "" {
+		name = "Room " + r.RoomID
+	}
+
+	var title, color string
+
+	switch e.Type {
+	case event.TypeReservationCreated:
+		title, color = name+" booked", colorBooked
+	case event.TypeReservationRestored:
+		title, color = "Booking of "+name+" restored", colorBooked
+	case event.TypeReservationCancelled:
+		title, color = "Booking of "+name+" cancelled", colorCancelled
+	default:
+		title, color = "Booking of "+name+" changed", colorChanged
+	}
+
+	when := timeSpan(r)
+
+	place := slack.Escape(name)
+	if rm.Building != "" {
+		place += ", " + slack.Escape(rm.Building)
+	}
+
+	a := slack.Attachment{
+		Fallback: title + " for " + when,
+		Color:    color,
+		Title:    title,
+		Fields: []slack.Field{
+			{Title: "When", Value: when, Short: true},
+			{Title: "Room", Value: place, Short: true},
+		},
+		Footer:    "Reservation " + r.ID,
+		Timestamp: e.OccurredAt.Unix(),
+	}
+
+	if r.UserID != "" {
+		a.Fields = append(a.Fields, slack.Field{Title: "Booked by", Value: slack.Escape(r.UserID), Short: true})
+	}
+
+	if e.Type == event.TypeReservationCancelled {
+		if r.CancelledBy != "" {
+			a.Fields = append(a.Fields, slack.Field{Title: "Cancelled by", Value: slack.Escape(r.CancelledBy), Short: true})
+		}
+
+		if r.CancelReason != "" {
+			a.Fields = append(a.Fields, slack.Field{Title: "Reason", Value: slack.Escape(r.CancelReason)})
+		}
+	}
+
+	if baseURL != "" {
+		baseURL = strings.TrimSuffix(baseURL, "/")
+
+		a.TitleLink = baseURL + "/api/v1/reservations/" + r.ID
+		a.Text = slack.Link(a.TitleLink, "View booking") + " · " +
+			slack.Link(baseURL+"/api/v1/rooms/"+r.RoomID+"/calendar.ics", "Room calendar")
+	}
+
+	return slack.Message{
+		Text:        slack.Escape(a.Fallback),
+		Attachments: []slack.Attachment{a},
+	}
+}
+
+// timeSpan is the time span of a reservation in UTC, the end time is shortened when it is on the same day.
+func timeSpan(r reservation.Reservation) string {
+	start, end := r.StartTime.UTC(), r.EndTime.UTC()
+
+	if start.Year() == end.Year() && start.YearDay() == end.YearDay() {
+		return start.Format(dateTimeLayout) + "–" + end.Format(timeLayout) + " UTC"
+	}
+
+	return start.Format(dateTimeLayout) + " – " + end.Format(dateTimeLayout) + " UTC"
+}
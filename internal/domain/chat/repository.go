@@ -0,0 +1,18 @@
+package chat
+
+import (
+	"context"
+	"room-reservation/internal/domain/event"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Channel) (ID string, err error)
+	Get(ctx context.Context, ID string) (Channel, error)
+	List(ctx context.Context) ([]Channel, error)
+	Delete(ctx context.Context, ID string) error
+
+	// Enqueue queues payload for every channel matching e about a room of the given building, once per channel.
+	Enqueue(ctx context.Context, e event.Event, building string, payload []byte) error
+	// Deliver hands up to limit due messages to send and records their results, scheduling retries.
+	Deliver(ctx context.Context, limit int, send func(context.Context, Channel, Message) Result) (int, error)
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	TypeReservationCreated   = "ReservationCreated"
+	TypeReservationUpdated   = "ReservationUpdated"
+	TypeReservationCancelled = "ReservationCancelled"
+	TypeReservationRestored  = "ReservationRestored"
+)
+
+// Event is a change to a reservation recorded in the outbox within the same transaction as the change itself.
+// Data holds a snapshot of the reservation after the change.
+type Event struct {
+	ID            int64           `db:"id" json:"id"`
+	Type          string          `db:"event_type" json:"type"`
+	ReservationID string          `db:"reservation_id" json:"reservation_id"`
+	RoomID        string          `db:"room_id" json:"room_id"`
+	Actor         string          `db:"actor" json:"actor"`
+	Data          json.RawMessage `db:"data" json:"data"`
+	OccurredAt    time.Time       `db:"created_at" json:"occurred_at"`
+	Attempts      int             `db:"attempts" json:"-"`
+}
+
+// Sink receives published events. Delivery is at-least-once, so sinks may see an event more than once.
+type Sink interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+type SinkFunc func(ctx context.Context, e Event) error
+
+func (f SinkFunc) Publish(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// Sinks publishes events to every sink, failing if any of them fails.
+type Sinks []Sink
+
+func (s Sinks) Publish(ctx context.Context, e Event) error {
+	var errs []error
+
+	for _, sink := range s {
+		if err := sink.Publish(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Backoff doubles the wait between attempts from Min up to Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Delay is how long to wait before the given attempt.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := b.Min
+	for i := 1; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+
+	return min(d, b.Max)
+}
+
+// PublishBackoff is how long to wait before attempts to publish an event.
+var PublishBackoff = Backoff{Min: time.Second, Max: time.Hour}
@@ -0,0 +1,42 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 1, expected: time.Second},
+		{attempt: 2, expected: 2 * time.Second},
+		{attempt: 5, expected: 16 * time.Second},
+		{attempt: 100, expected: time.Hour},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, PublishBackoff.Delay(test.attempt), "attempt %d", test.attempt)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	var published []string
+
+	ok := SinkFunc(func(ctx context.Context, e Event) error {
+		published = append(published, e.Type)
+		return nil
+	})
+	failing := SinkFunc(func(ctx context.Context, e Event) error {
+		return errors.New("unavailable")
+	})
+
+	err := Sinks{ok, failing, ok}.Publish(context.Background(), Event{Type: TypeReservationCreated})
+	assert.Error(t, err, "expected failure of any sink to fail publishing")
+	assert.Len(t, published, 2, "expected every sink to be published to")
+}
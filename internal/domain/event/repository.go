@@ -0,0 +1,23 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+type Outbox interface {
+	// Relay hands up to limit pending events to publish, at most one per reservation at a time so that
+	// events of a reservation are published in order. Events are marked published when publish succeeds
+	// and scheduled for another attempt otherwise. It returns the number of events handed to publish.
+	Relay(ctx context.Context, limit int, publish func(context.Context, Event) error) (int, error)
+	// Prune deletes events published before publishedBefore and returns how many were deleted.
+	Prune(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+type Repository interface {
+	// Since lists events after the event with ID afterID, of a room or of every room when roomID is empty.
+	// Events committed after it despite a lower id are listed too, along with some the caller may have had.
+	Since(ctx context.Context, afterID int64, roomID string, limit int) ([]Event, error)
+	// Listen calls fn with events committed by any instance of the service until ctx is done or listening fails.
+	Listen(ctx context.Context, fn func(Event)) error
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+)
+
+type PreferenceRequest struct {
+	// Email overrides the address notifications are sent to, by default they go to the user id when it is an email address.
+	Email           string `json:"email" example:"alice@example.com"`
+	OptOut          bool   `json:"opt_out" example:"false"`
+	ReminderMinutes *int   `json:"reminder_minutes" example:"15"`
+}
+
+func (r *PreferenceRequest) Validate() error {
+	if r.Email != "" {
+		if _, err := mail.ParseAddress(r.Email); err != nil {
+			return errors.New("email must be a valid email address")
+		}
+	}
+
+	if r.ReminderMinutes != nil && (*r.ReminderMinutes < 0 || *r.ReminderMinutes > 7*24*60) {
+		return errors.New("reminder_minutes must be between 0 and 10080")
+	}
+
+	return nil
+}
+
+type PreferenceResponse struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email,omitempty"`
+	// Recipient is where notifications are sent, empty when the user is not notified.
+	Recipient       string     `json:"recipient,omitempty"`
+	OptOut          bool       `json:"opt_out"`
+	ReminderMinutes int        `json:"reminder_minutes"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+func ToPreferenceResponse(data Preference) PreferenceResponse {
+	res := PreferenceResponse{
+		UserID:          data.UserID,
+		Email:           data.Email,
+		Recipient:       data.Recipient(),
+		OptOut:          data.OptOut,
+		ReminderMinutes: data.ReminderMinutes,
+	}
+
+	if !data.UpdatedAt.IsZero() {
+		res.UpdatedAt = &data.UpdatedAt
+	}
+
+	return res
+}
@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"errors"
+	"net/mail"
+	"room-reservation/internal/domain/event"
+	"strings"
+	"time"
+)
+
+const (
+	KindConfirmation = "confirmation"
+	KindChange       = "change"
+	KindCancellation = "cancellation"
+	KindReminder     = "reminder"
+)
+
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	// StatusSkipped marks notifications no longer worth sending, like reminders of cancelled reservations.
+	StatusSkipped = "skipped"
+)
+
+// Notification is an email about a reservation queued for its owner, sent once SendAt has passed.
+type Notification struct {
+	ID            int64      `db:"id"`
+	Kind          string     `db:"kind"`
+	ReservationID string     `db:"reservation_id"`
+	EventID       int64      `db:"event_id"`
+	UserID        string     `db:"user_id"`
+	Recipient     string     `db:"recipient"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	SendAt        time.Time  `db:"send_at"`
+	SentAt        *time.Time `db:"sent_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
+
+// DefaultReminderMinutes is how long before a reservation starts its owner is reminded of it
+// unless they chose otherwise.
+const DefaultReminderMinutes = 15
+
+// Preference is how a user wants to be notified. Users without preferences are notified
+// at their user id when it is an email address.
+type Preference struct {
+	UserID          string    `db:"user_id"`
+	Email           string    `db:"email"`
+	OptOut          bool      `db:"opt_out"`
+	ReminderMinutes int       `db:"reminder_minutes"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// DefaultPreference is the preference of a user who has not saved one.
+func DefaultPreference(userID string) Preference {
+	return Preference{
+		UserID:          userID,
+		ReminderMinutes: DefaultReminderMinutes,
+	}
+}
+
+// Recipient is the address notifications are sent to, empty when the user cannot be notified.
+func (p *Preference) Recipient() string {
+	if p.OptOut {
+		return ""
+	}
+
+	if p.Email != "" {
+		return p.Email
+	}
+
+	if strings.Contains(p.UserID, "@") {
+		if addr, err := mail.ParseAddress(p.UserID); err == nil {
+			return addr.Address
+		}
+	}
+
+	return ""
+}
+
+// Reminder is when the owner of a reservation starting at start is reminded of it,
+// false when they do not want reminders.
+func (p *Preference) Reminder(start time.Time) (time.Time, bool) {
+	if p.ReminderMinutes <= 0 {
+		return time.Time{}, false
+	}
+
+	return start.Add(-time.Duration(p.ReminderMinutes) * time.Minute), true
+}
+
+const (
+	// MaxAttempts is how many times a notification is sent before giving up on it.
+	MaxAttempts = 5
+)
+
+// Backoff is how long to wait before attempts to send a notification.
+var Backoff = event.Backoff{Min: 30 * time.Second, Max: 30 * time.Minute}
+
+var ErrorNotFound error = errors.New("notification preference not found")
+
+// ErrorSkipped is returned when sending a notification that should no longer be sent.
+var ErrorSkipped error = errors.New("notification skipped")
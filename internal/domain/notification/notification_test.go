@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"room-reservation/internal/domain/reservation"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferenceRecipient(t *testing.T) {
+	tests := map[string]struct {
+		pref     Preference
+		expected string
+	}{
+		"Email user id":     {pref: Preference{UserID: "alice@example.com"}, expected: "alice@example.com"},
+		"Plain user id":     {pref: Preference{UserID: "alice"}, expected: ""},
+		"Email":             {pref: Preference{UserID: "alice", Email: "a@example.com"}, expected: "a@example.com"},
+		"Email overrides":   {pref: Preference{UserID: "alice@example.com", Email: "a@example.com"}, expected: "a@example.com"},
+		"Opted out":         {pref: Preference{UserID: "alice@example.com", OptOut: true}, expected: ""},
+		"Invalid user id":   {pref: Preference{UserID: "alice@"}, expected: ""},
+		"Opted out of both": {pref: Preference{UserID: "alice", Email: "a@example.com", OptOut: true}, expected: ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.pref.Recipient())
+		})
+	}
+}
+
+func TestPreferenceReminder(t *testing.T) {
+	start := time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC)
+
+	pref := DefaultPreference("alice@example.com")
+
+	at, ok := pref.Reminder(start)
+	require.True(t, ok)
+	assert.Equal(t, start.Add(-DefaultReminderMinutes*time.Minute), at)
+
+	pref.ReminderMinutes = 0
+
+	_, ok = pref.Reminder(start)
+	assert.False(t, ok, "expected no reminder")
+}
+
+func TestRender(t *testing.T) {
+	data := TemplateData{
+		Reservation: reservation.Reservation{
+			ID:           "abc",
+			StartTime:    time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC),
+			EndTime:      time.Date(2024, 9, 2, 11, 0, 0, 0, time.UTC),
+			CancelReason: "<Offsite>",
+		},
+		Room: "Everest",
+	}
+
+	for _, kind := range []string{KindConfirmation, KindChange, KindCancellation, KindReminder} {
+		t.Run(kind, func(t *testing.T) {
+			res, err := Render(kind, data)
+			require.NoError(t, err, "could not render")
+
+			assert.Contains(t, res.Subject, "Everest")
+			assert.Contains(t, res.Subject, "Mon, 02 Sep 2024 10:00 UTC")
+			assert.NotContains(t, res.Subject, "\n")
+
+			assert.Contains(t, res.Text, "Everest")
+			assert.Contains(t, res.Text, "Mon, 02 Sep 2024 11:00 UTC")
+			assert.Contains(t, res.HTML, "<strong>Everest</strong>")
+			assert.Contains(t, res.HTML, "abc")
+		})
+	}
+
+	res, err := Render(KindCancellation, data)
+	require.NoError(t, err, "could not render")
+	assert.Contains(t, res.Text, "Reason: <Offsite>")
+	assert.Contains(t, res.HTML, "Reason: &lt;Offsite&gt;", "expected html to be escaped")
+
+	_, err = Render("unknown", data)
+	assert.Error(t, err)
+}
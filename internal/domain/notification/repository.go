@@ -0,0 +1,19 @@
+package notification
+
+import "context"
+
+type Repository interface {
+	GetPreference(ctx context.Context, userID string) (Preference, error)
+	// SavePreference creates the preference or replaces it if it exists.
+	SavePreference(ctx context.Context, data Preference) error
+
+	// Enqueue queues a notification, once per event and kind.
+	Enqueue(ctx context.Context, data Notification) error
+	// ScheduleReminder queues a reminder, skipping pending reminders of the same reservation queued by other events.
+	ScheduleReminder(ctx context.Context, data Notification) error
+	// SkipReminders skips pending reminders of a reservation.
+	SkipReminders(ctx context.Context, reservationID string) error
+	// Send hands up to limit due notifications to send and records their results, scheduling retries.
+	// Notifications send returns ErrorSkipped for are not retried.
+	Send(ctx context.Context, limit int, send func(context.Context, Notification) error) (int, error)
+}
@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"room-reservation/internal/domain/reservation"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var templateFuncs = map[string]any{
+	"datetime": func(t time.Time) string {
+		return t.UTC().Format("Mon, 02 Jan 2006 15:04 MST")
+	},
+}
+
+var (
+	textTemplates = map[string]*texttemplate.Template{}
+	htmlTemplates = map[string]*htmltemplate.Template{}
+)
+
+func init() {
+	for _, kind := range []string{KindConfirmation, KindChange, KindCancellation, KindReminder} {
+		textTemplates[kind] = texttemplate.Must(texttemplate.New(kind).Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/layout.txt", "templates/"+kind+".txt"))
+
+		htmlTemplates[kind] = htmltemplate.Must(htmltemplate.New(kind).Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/layout.html", "templates/"+kind+".html"))
+	}
+}
+
+// TemplateData is what notification templates are rendered with.
+type TemplateData struct {
+	Reservation reservation.Reservation
+	// Room is the name of the reserved room, or its id when it has no name.
+	Room string
+}
+
+// Content is a rendered notification.
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Render renders the notification of the given kind.
+func Render(kind string, data TemplateData) (Content, error) {
+	text, ok := textTemplates[kind]
+	if !ok {
+		return Content{}, fmt.Errorf("unknown notification kind %q", kind)
+	}
+
+	var subject, body, html bytes.Buffer
+
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Content{}, err
+	}
+
+	if err := text.ExecuteTemplate(&body, "text", data); err != nil {
+		return Content{}, err
+	}
+
+	if err := htmlTemplates[kind].ExecuteTemplate(&html, "layout", data); err != nil {
+		return Content{}, err
+	}
+
+	return Content{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    body.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -1,5 +1,56 @@
 package reservation
 
+import (
+	"sort"
+	"time"
+)
+
 func (r *Reservation) Overlaps(other Reservation) bool {
 	return r.RoomID == other.RoomID && r.StartTime.Before(other.EndTime) && r.EndTime.After(other.StartTime)
 }
+
+// Slot is a span of time, from Start up to End.
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// FreeSlots lists the times between from and to not taken by any of the reservations.
+// Cancelled reservations don't take any time.
+func FreeSlots(reservations []Reservation, from, to time.Time) []Slot {
+	taken := make([]Reservation, 0, len(reservations))
+	for _, r := range reservations {
+		if !r.Cancelled() {
+			taken = append(taken, r)
+		}
+	}
+
+	sort.Slice(taken, func(i, j int) bool {
+		return taken[i].StartTime.Before(taken[j].StartTime)
+	})
+
+	slots := []Slot{}
+
+	for _, r := range taken {
+		if !r.StartTime.After(from) {
+			if r.EndTime.After(from) {
+				from = r.EndTime
+			}
+			continue
+		}
+
+		if !r.StartTime.Before(to) {
+			break
+		}
+
+		slots = append(slots, Slot{Start: from, End: r.StartTime})
+
+		from = r.EndTime
+	}
+
+	if from.Before(to) {
+		slots = append(slots, Slot{Start: from, End: to})
+	}
+
+	return slots
+}
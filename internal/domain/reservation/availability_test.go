@@ -66,3 +66,30 @@ func testExactEndStart(t *testing.T) {
 	ok := room1.Overlaps(room2)
 	assert.Falsef(t, ok, "expected no overlap between reservations %v and %v", room1, room2)
 }
+
+func TestFreeSlots(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2024, 8, 29, hour, min, 0, 0, time.UTC)
+	}
+
+	cancelled := at(8, 0)
+
+	reservations := []Reservation{
+		{StartTime: at(13, 0), EndTime: at(14, 0)},
+		{StartTime: at(8, 0), EndTime: at(9, 30)},
+		{StartTime: at(11, 0), EndTime: at(12, 0), CancelledAt: &cancelled},
+		{StartTime: at(13, 30), EndTime: at(15, 0)},
+		{StartTime: at(17, 0), EndTime: at(19, 0)},
+	}
+
+	slots := FreeSlots(reservations, at(9, 0), at(18, 0))
+
+	expected := []Slot{
+		{Start: at(9, 30), End: at(13, 0)},
+		{Start: at(15, 0), End: at(17, 0)},
+	}
+	assert.Equal(t, expected, slots)
+
+	assert.Equal(t, []Slot{{Start: at(20, 0), End: at(21, 0)}}, FreeSlots(reservations, at(20, 0), at(21, 0)))
+	assert.Empty(t, FreeSlots(reservations, at(13, 15), at(14, 45)), "expected no free time within back to back reservations")
+}
@@ -71,20 +71,41 @@ func (r *UpdateRequest) Validate() error {
 	return nil
 }
 
+const (
+	StatusActive    = "active"
+	StatusCancelled = "cancelled"
+)
+
 type Response struct {
-	ID        string   `json:"id"`
-	RoomID    string   `json:"room_id"`
-	StartTime DateTime `json:"start_time"`
-	EndTime   DateTime `json:"end_time"`
+	ID           string    `json:"id"`
+	RoomID       string    `json:"room_id"`
+	UserID       string    `json:"user_id,omitempty"`
+	StartTime    DateTime  `json:"start_time"`
+	EndTime      DateTime  `json:"end_time"`
+	Status       string    `json:"status" enums:"active,cancelled"`
+	CancelledAt  *DateTime `json:"cancelled_at,omitempty" swaggertype:"primitive,string"`
+	CancelReason string    `json:"cancel_reason,omitempty"`
+	CancelledBy  string    `json:"cancelled_by,omitempty"`
 }
 
 func ToResponse(data Reservation) Response {
-	return Response{
+	res := Response{
 		ID:        data.ID,
 		RoomID:    data.RoomID,
+		UserID:    data.UserID,
 		StartTime: DateTime{data.StartTime},
 		EndTime:   DateTime{data.EndTime},
+		Status:    StatusActive,
+	}
+
+	if data.Cancelled() {
+		res.Status = StatusCancelled
+		res.CancelledAt = &DateTime{*data.CancelledAt}
+		res.CancelReason = data.CancelReason
+		res.CancelledBy = data.CancelledBy
 	}
+
+	return res
 }
 
 func ToResponseSlice(data []Reservation) []Response {
@@ -96,3 +117,7 @@ func ToResponseSlice(data []Reservation) []Response {
 
 	return res
 }
+
+type CancelRequest struct {
+	Reason string `json:"reason" example:"meeting moved online"`
+}
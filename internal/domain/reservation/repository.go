@@ -1,11 +1,28 @@
 package reservation
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Create(context.Context, Reservation) (ID string, err error)
+	// Import creates reservations in a single transaction, reporting the ones that could not be created.
+	// Nothing is kept when dryRun is set.
+	Import(ctx context.Context, data []Reservation, dryRun bool) ([]ImportResult, error)
 	Get(ctx context.Context, ID string) (Reservation, error)
-	List(ctx context.Context, roomID string) ([]Reservation, error)
-	Delete(ctx context.Context, ID string) error
+	// GetByICalUID gets the reservation created from a calendar event.
+	GetByICalUID(ctx context.Context, uid string) (Reservation, error)
+	List(ctx context.Context, roomID string, opts ListOptions) ([]Reservation, error)
+	ListForUser(ctx context.Context, userID string, opts ListOptions) ([]Reservation, error)
+	// ListForRooms lists reservations of several rooms at once ordered by start time, rooms without
+	// reservations are left out.
+	ListForRooms(ctx context.Context, roomIDs []string, opts ListOptions) ([]Reservation, error)
 	Update(ctx context.Context, ID string, data Reservation) error
+	Cancel(ctx context.Context, ID string, reason string) error
+	Restore(ctx context.Context, ID string) error
+	// RestoreWith restores a cancelled reservation with the fields set in data changed, as Update would, at once.
+	// Overlaps are only checked against the changed reservation.
+	RestoreWith(ctx context.Context, ID string, data Reservation) error
+	Purge(ctx context.Context, cancelledBefore time.Time) (int64, error)
 }
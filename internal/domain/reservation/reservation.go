@@ -6,12 +6,61 @@ import (
 )
 
 type Reservation struct {
-	ID        string    `db:"id"`
-	RoomID    string    `db:"room_id"`
-	StartTime time.Time `db:"start_time"`
-	EndTime   time.Time `db:"end_time"`
+	ID           string     `db:"id" json:"id"`
+	RoomID       string     `db:"room_id" json:"room_id"`
+	UserID       string     `db:"user_id" json:"user_id,omitempty"`
+	StartTime    time.Time  `db:"start_time" json:"start_time"`
+	EndTime      time.Time  `db:"end_time" json:"end_time"`
+	CancelledAt  *time.Time `db:"cancelled_at" json:"cancelled_at,omitempty"`
+	CancelReason string     `db:"cancel_reason" json:"cancel_reason,omitempty"`
+	CancelledBy  string     `db:"cancelled_by" json:"cancelled_by,omitempty"`
+	// Sequence is incremented on every change, calendar clients use it to pick the latest version.
+	Sequence  int       `db:"sequence" json:"sequence"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// ICalUID identifies reservations created from calendar events, empty otherwise.
+	ICalUID string `db:"ical_uid" json:"ical_uid,omitempty"`
+}
+
+// Merge returns r with the fields set in data, as updates leave the others untouched.
+func (r Reservation) Merge(data Reservation) Reservation {
+	if data.RoomID != "" {
+		r.RoomID = data.RoomID
+	}
+
+	if !data.StartTime.IsZero() {
+		r.StartTime = data.StartTime
+	}
+
+	if !data.EndTime.IsZero() {
+		r.EndTime = data.EndTime
+	}
+
+	return r
+}
+
+func (r *Reservation) Cancelled() bool {
+	return r.CancelledAt != nil
+}
+
+// ListOptions narrows down listed reservations, cancelled reservations are left out by default.
+type ListOptions struct {
+	IncludeCancelled bool
+	// From and To list only reservations overlapping the time between them, either may be zero.
+	From time.Time
+	To   time.Time
 }
 
 var ErrorNotFound error = errors.New("reservation not found")
 var ErrorNotFoundForRoom error = errors.New("reservations not found for room")
 var ErrorOverlaps error = errors.New("reservation overlaps with another")
+var ErrorCancelled error = errors.New("reservation is cancelled")
+var ErrorNotCancelled error = errors.New("reservation is not cancelled")
+var ErrorImported error = errors.New("reservation already imported")
+var ErrorClosed error = errors.New("room is closed at that time")
+var ErrorInvalidTime error = errors.New("start_time must be before end_time")
+
+// ImportResult is the outcome of importing a single reservation.
+type ImportResult struct {
+	ID  string
+	Err error
+}
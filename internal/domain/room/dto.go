@@ -0,0 +1,47 @@
+package room
+
+import "errors"
+
+type Request struct {
+	Name     string `json:"name" example:"Everest"`
+	Building string `json:"building" example:"HQ"`
+	Capacity int    `json:"capacity" example:"8"`
+}
+
+func (r *Request) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if r.Capacity < 0 {
+		return errors.New("capacity must not be negative")
+	}
+
+	return nil
+}
+
+type Response struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Building string `json:"building"`
+	Capacity int    `json:"capacity"`
+}
+
+func ToResponse(data Room) Response {
+	return Response{
+		ID:       data.ID,
+		Name:     data.Name,
+		Building: data.Building,
+		Capacity: data.Capacity,
+	}
+}
+
+func ToResponseSlice(data []Room) []Response {
+	res := make([]Response, 0)
+
+	for _, r := range data {
+		res = append(res, ToResponse(r))
+	}
+
+	return res
+}
@@ -0,0 +1,12 @@
+package room
+
+import "context"
+
+type Repository interface {
+	Get(ctx context.Context, ID string) (Room, error)
+	List(ctx context.Context, filter Filter) ([]Room, error)
+	// GetMany gets several rooms at once, unknown rooms are left out.
+	GetMany(ctx context.Context, IDs []string) ([]Room, error)
+	// Save creates the room or replaces it if it exists.
+	Save(ctx context.Context, data Room) error
+}
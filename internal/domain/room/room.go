@@ -0,0 +1,17 @@
+package room
+
+import "errors"
+
+type Room struct {
+	ID       string `db:"id"`
+	Name     string `db:"name"`
+	Building string `db:"building"`
+	Capacity int    `db:"capacity"`
+}
+
+// Filter narrows down listed rooms, empty fields match every room.
+type Filter struct {
+	Building string
+}
+
+var ErrorNotFound error = errors.New("room not found")
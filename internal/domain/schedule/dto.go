@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"room-reservation/internal/domain/reservation"
+	"sort"
+	"strings"
+	"time"
+)
+
+// weekdays are the names of the days of the week in requests, indexed by time.Weekday.
+var weekdays = [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// Request replaces the opening hours of a room or building. Days of the week left out of weekly are closed.
+type Request struct {
+	TimeZone   string              `json:"time_zone" example:"Europe/Paris"`
+	Weekly     map[string][]Period `json:"weekly"`
+	Exceptions []Exception         `json:"exceptions"`
+}
+
+func (r *Request) Validate() error {
+	if r.TimeZone == "" {
+		return errors.New("time_zone is required")
+	}
+
+	if _, err := time.LoadLocation(r.TimeZone); err != nil {
+		return fmt.Errorf("unknown time_zone %q", r.TimeZone)
+	}
+
+	days := make(map[time.Weekday]bool, len(r.Weekly))
+
+	for day, periods := range r.Weekly {
+		d := weekday(day)
+		if d < 0 {
+			return fmt.Errorf("unknown day of the week %q", day)
+		}
+
+		// days are matched whatever their case, so "Monday" and "monday" are the same day
+		if days[d] {
+			return fmt.Errorf("%s is given twice", strings.ToLower(day))
+		}
+		days[d] = true
+
+		if err := validatePeriods(periods); err != nil {
+			return fmt.Errorf("%s: %w", day, err)
+		}
+	}
+
+	dates := make(map[string]bool, len(r.Exceptions))
+
+	for _, e := range r.Exceptions {
+		if _, err := time.Parse(time.DateOnly, e.Date); err != nil {
+			return fmt.Errorf("invalid exception date %q, expected YYYY-MM-DD", e.Date)
+		}
+
+		if dates[e.Date] {
+			return fmt.Errorf("exception date %s is given twice", e.Date)
+		}
+		dates[e.Date] = true
+
+		if err := validatePeriods(e.Periods); err != nil {
+			return fmt.Errorf("%s: %w", e.Date, err)
+		}
+	}
+
+	return nil
+}
+
+func weekday(name string) time.Weekday {
+	for i, d := range weekdays {
+		if strings.EqualFold(d, name) {
+			return time.Weekday(i)
+		}
+	}
+
+	return -1
+}
+
+func validatePeriods(periods []Period) error {
+	sorted := append([]Period(nil), periods...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Open < sorted[j].Open
+	})
+
+	for i, p := range sorted {
+		if p.Open >= p.Close {
+			return fmt.Errorf("period %s-%s must open before it closes", p.Open, p.Close)
+		}
+
+		if i > 0 && p.Open < sorted[i-1].Close {
+			return fmt.Errorf("period %s-%s overlaps with %s-%s", p.Open, p.Close, sorted[i-1].Open, sorted[i-1].Close)
+		}
+	}
+
+	return nil
+}
+
+// ToSchedule builds the schedule of the room or building key from a validated request.
+func (r *Request) ToSchedule(scope, key string) Schedule {
+	s := Schedule{
+		Scope:      scope,
+		Key:        key,
+		TimeZone:   r.TimeZone,
+		Exceptions: r.Exceptions,
+	}
+
+	for day, periods := range r.Weekly {
+		s.Weekly[weekday(day)] = periods
+	}
+
+	if s.Exceptions == nil {
+		s.Exceptions = []Exception{}
+	}
+
+	return s
+}
+
+type Response struct {
+	Scope      string              `json:"scope" enums:"room,building"`
+	Key        string              `json:"key"`
+	TimeZone   string              `json:"time_zone"`
+	Weekly     map[string][]Period `json:"weekly"`
+	Exceptions []Exception         `json:"exceptions"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+func ToResponse(data Schedule) Response {
+	res := Response{
+		Scope:      data.Scope,
+		Key:        data.Key,
+		TimeZone:   data.TimeZone,
+		Weekly:     make(map[string][]Period),
+		Exceptions: data.Exceptions,
+		UpdatedAt:  data.UpdatedAt,
+	}
+
+	for day, periods := range data.Weekly {
+		if len(periods) > 0 {
+			res.Weekly[weekdays[day]] = periods
+		}
+	}
+
+	if res.Exceptions == nil {
+		res.Exceptions = []Exception{}
+	}
+
+	return res
+}
+
+type SlotResponse struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type AvailabilityResponse struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Available tells whether the room is open and free the whole time.
+	Available bool           `json:"available"`
+	Slots     []SlotResponse `json:"slots"`
+}
+
+func ToAvailabilityResponse(from, to time.Time, slots []reservation.Slot) AvailabilityResponse {
+	res := AvailabilityResponse{
+		From:      from,
+		To:        to,
+		Available: len(slots) == 1 && slots[0].Start.Equal(from) && slots[0].End.Equal(to),
+		Slots:     make([]SlotResponse, len(slots)),
+	}
+
+	for i, s := range slots {
+		res.Slots[i] = SlotResponse{Start: s.Start, End: s.End}
+	}
+
+	return res
+}
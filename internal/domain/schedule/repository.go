@@ -0,0 +1,13 @@
+package schedule
+
+import "context"
+
+type Repository interface {
+	Get(ctx context.Context, scope, key string) (Schedule, error)
+	// Save creates the schedule or replaces it if it exists.
+	Save(ctx context.Context, data Schedule) error
+	Delete(ctx context.Context, scope, key string) error
+	// ForRooms gets the hours of several rooms at once, the schedule of their building first. Rooms
+	// without any schedule are left out.
+	ForRooms(ctx context.Context, roomIDs []string) (map[string]Hours, error)
+}
@@ -0,0 +1,136 @@
+package schedule
+
+import (
+	"context"
+	"room-reservation/internal/domain/reservation"
+)
+
+type reservationRepository struct {
+	reservation.Repository
+
+	schedules Repository
+}
+
+// Reservations refuses reservations made through repo outside the opening hours of their room with
+// reservation.ErrorClosed, whichever API they come from.
+func Reservations(repo reservation.Repository, schedules Repository) reservation.Repository {
+	return &reservationRepository{
+		Repository: repo,
+		schedules:  schedules,
+	}
+}
+
+func (r *reservationRepository) check(ctx context.Context, data reservation.Reservation) error {
+	hours, err := r.schedules.ForRooms(ctx, []string{data.RoomID})
+	if err != nil {
+		return err
+	}
+
+	return hours[data.RoomID].Check(data.StartTime, data.EndTime)
+}
+
+func (r *reservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	if err := r.check(ctx, data); err != nil {
+		return "", err
+	}
+
+	return r.Repository.Create(ctx, data)
+}
+
+// Update checks the reservation as it is once updated, fields left out of data keep their value.
+func (r *reservationRepository) Update(ctx context.Context, ID string, data reservation.Reservation) error {
+	if data.RoomID == "" && data.StartTime.IsZero() && data.EndTime.IsZero() {
+		return r.Repository.Update(ctx, ID, data)
+	}
+
+	before, err := r.Repository.Get(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	// cancelled reservations are refused by the repository whatever their time
+	if !before.Cancelled() {
+		if err := r.check(ctx, before.Merge(data)); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.Update(ctx, ID, data)
+}
+
+// Restore refuses to restore reservations whose time is outside the opening hours of their room,
+// which may have changed since they were cancelled.
+func (r *reservationRepository) Restore(ctx context.Context, ID string) error {
+	data, err := r.Repository.Get(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	// active reservations are refused by the repository whatever their time
+	if data.Cancelled() {
+		if err := r.check(ctx, data); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.Restore(ctx, ID)
+}
+
+// RestoreWith checks the reservation as it is once restored and changed, fields left out of data keep their value.
+func (r *reservationRepository) RestoreWith(ctx context.Context, ID string, data reservation.Reservation) error {
+	before, err := r.Repository.Get(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	// active reservations are refused by the repository whatever their time
+	if before.Cancelled() {
+		if err := r.check(ctx, before.Merge(data)); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.RestoreWith(ctx, ID, data)
+}
+
+// Import reports reservations outside opening hours as not created, importing the others.
+func (r *reservationRepository) Import(ctx context.Context, data []reservation.Reservation, dryRun bool) ([]reservation.ImportResult, error) {
+	roomIDs := make([]string, 0, len(data))
+	for _, d := range data {
+		roomIDs = append(roomIDs, d.RoomID)
+	}
+
+	hours, err := r.schedules.ForRooms(ctx, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]reservation.ImportResult, len(data))
+	open := make([]reservation.Reservation, 0, len(data))
+	indexes := make([]int, 0, len(data))
+
+	for i, d := range data {
+		if err := hours[d.RoomID].Check(d.StartTime, d.EndTime); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		open = append(open, d)
+		indexes = append(indexes, i)
+	}
+
+	if len(open) == 0 {
+		return results, nil
+	}
+
+	imported, err := r.Repository.Import(ctx, open, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, res := range imported {
+		results[indexes[j]] = res
+	}
+
+	return results, nil
+}
@@ -0,0 +1,227 @@
+// Package schedule keeps the weekly opening hours of rooms and buildings, along with the days they differ from
+// them, such as holidays and special openings.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"room-reservation/internal/domain/reservation"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	ScopeRoom     = "room"
+	ScopeBuilding = "building"
+)
+
+// Clock is a time of day in minutes since midnight, written as "15:04". It goes up to 24:00, the midnight
+// ending the day.
+type Clock int
+
+const endOfDay Clock = 24 * 60
+
+func ParseClock(s string) (Clock, error) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+
+	h, errH := strconv.Atoi(s[:2])
+	m, errM := strconv.Atoi(s[3:])
+	if errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || Clock(h*60+m) > endOfDay {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+
+	return Clock(h*60 + m), nil
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+func (c Clock) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Clock) UnmarshalText(b []byte) (err error) {
+	*c, err = ParseClock(string(b))
+	return
+}
+
+// Period is the time of a day a room is open, from Open up to Close.
+type Period struct {
+	Open  Clock `json:"open" swaggertype:"primitive,string" example:"08:00"`
+	Close Clock `json:"close" swaggertype:"primitive,string" example:"18:00"`
+}
+
+// Exception replaces the weekly hours of a single day, a day without periods is closed.
+type Exception struct {
+	// Date is the day in the time zone of the schedule, as "2006-01-02".
+	Date    string   `json:"date"`
+	Periods []Period `json:"periods"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// Schedule is the opening hours of a room, or of every room of a building. Days without periods are closed.
+type Schedule struct {
+	Scope string
+	// Key is the room ID or the building name.
+	Key      string
+	TimeZone string
+	// Weekly are the periods of each day of the week, indexed by time.Weekday.
+	Weekly     [7][]Period
+	Exceptions []Exception
+	UpdatedAt  time.Time
+}
+
+var ErrorNotFound error = errors.New("opening schedule not found")
+
+// MaxSearch is the longest time searched for free slots at once, opening hours being walked a day at a time.
+const MaxSearch = 366 * 24 * time.Hour
+
+func (s *Schedule) location() *time.Location {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// periods returns the periods of the day starting at midnight day, in the time zone of the schedule.
+func (s *Schedule) periods(day time.Time) []Period {
+	date := day.Format(time.DateOnly)
+	for _, e := range s.Exceptions {
+		if e.Date == date {
+			return e.Periods
+		}
+	}
+
+	return s.Weekly[day.Weekday()]
+}
+
+// Open lists the times between from and to the schedule is open, in the location of from. Periods following
+// each other, like the evening of a day and the morning of the next, are joined.
+func (s *Schedule) Open(from, to time.Time) []reservation.Slot {
+	loc := s.location()
+
+	local := from.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	slots := []reservation.Slot{}
+
+	for ; day.Before(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+		periods := append([]Period(nil), s.periods(day)...)
+		sort.Slice(periods, func(i, j int) bool {
+			return periods[i].Open < periods[j].Open
+		})
+
+		for _, p := range periods {
+			start := clip(at(day, p.Open).In(from.Location()), from, to)
+			end := clip(at(day, p.Close).In(from.Location()), from, to)
+
+			if !start.Before(end) {
+				continue
+			}
+
+			if n := len(slots); n > 0 && !slots[n-1].End.Before(start) {
+				if end.After(slots[n-1].End) {
+					slots[n-1].End = end
+				}
+				continue
+			}
+
+			slots = append(slots, reservation.Slot{Start: start, End: end})
+		}
+	}
+
+	return slots
+}
+
+// at is the time c of the day starting at midnight day.
+func at(day time.Time, c Clock) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(c)/60, int(c)%60, 0, 0, day.Location())
+}
+
+func clip(t, from, to time.Time) time.Time {
+	if t.Before(from) {
+		return from
+	}
+
+	if t.After(to) {
+		return to
+	}
+
+	return t
+}
+
+// Hours are the schedules a room follows, the schedule of its building and its own. The room is open when
+// all of them are, rooms without any schedule are always open.
+type Hours []Schedule
+
+// Open lists the times between from and to the room is open.
+func (h Hours) Open(from, to time.Time) []reservation.Slot {
+	if !from.Before(to) {
+		return []reservation.Slot{}
+	}
+
+	slots := []reservation.Slot{{Start: from, End: to}}
+
+	for _, s := range h {
+		slots = intersect(slots, s.Open(from, to))
+	}
+
+	return slots
+}
+
+// Check fails with reservation.ErrorClosed unless the room is open from start up to end.
+func (h Hours) Check(start, end time.Time) error {
+	if len(h) == 0 || !start.Before(end) {
+		return nil
+	}
+
+	open := h.Open(start, end)
+	if len(open) == 1 && open[0].Start.Equal(start) && open[0].End.Equal(end) {
+		return nil
+	}
+
+	return reservation.ErrorClosed
+}
+
+// FreeSlots lists the times between from and to the room is open and not taken by any of the reservations.
+func (h Hours) FreeSlots(reservations []reservation.Reservation, from, to time.Time) []reservation.Slot {
+	free := reservation.FreeSlots(reservations, from, to)
+	if len(h) == 0 {
+		return free
+	}
+
+	return intersect(free, h.Open(from, to))
+}
+
+// intersect lists the times within both a and b, which are ordered and don't overlap.
+func intersect(a, b []reservation.Slot) []reservation.Slot {
+	res := []reservation.Slot{}
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].Start, a[i].End
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+
+		if start.Before(end) {
+			res = append(res, reservation.Slot{Start: start, End: end})
+		}
+
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return res
+}
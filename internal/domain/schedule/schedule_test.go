@@ -0,0 +1,268 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"room-reservation/internal/domain/reservation"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func officeHours() Schedule {
+	s := Schedule{Scope: ScopeBuilding, Key: "HQ", TimeZone: "Europe/Paris"}
+	for day := time.Monday; day <= time.Friday; day++ {
+		s.Weekly[day] = []Period{{Open: 8 * 60, Close: 18 * 60}}
+	}
+
+	s.Exceptions = []Exception{
+		{Date: "2024-12-25", Reason: "Christmas"},
+		{Date: "2024-12-28", Periods: []Period{{Open: 10 * 60, Close: 12 * 60}}, Reason: "inventory"},
+	}
+
+	return s
+}
+
+// inUTC makes slots comparable whatever the location of their times.
+func inUTC(slots []reservation.Slot) []reservation.Slot {
+	res := make([]reservation.Slot, len(slots))
+	for i, s := range slots {
+		res[i] = reservation.Slot{Start: s.Start.UTC(), End: s.End.UTC()}
+	}
+
+	return res
+}
+
+func TestClock(t *testing.T) {
+	for s, want := range map[string]Clock{"00:00": 0, "08:30": 8*60 + 30, "24:00": endOfDay} {
+		c, err := ParseClock(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, c)
+		assert.Equal(t, s, c.String())
+	}
+
+	for _, s := range []string{"", "8:00", "24:01", "12:60", "ab:cd", "12-00"} {
+		_, err := ParseClock(s)
+		assert.Error(t, err, s)
+	}
+
+	var p Period
+	require.NoError(t, json.Unmarshal([]byte(`{"open":"09:15","close":"24:00"}`), &p))
+	assert.Equal(t, Period{Open: 9*60 + 15, Close: endOfDay}, p)
+}
+
+func TestOpen(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	s := officeHours()
+
+	// from Monday noon up to Wednesday
+	from := time.Date(2024, 12, 23, 12, 0, 0, 0, paris)
+	to := time.Date(2024, 12, 26, 0, 0, 0, 0, paris)
+
+	slots := s.Open(from, to)
+	assert.Equal(t, inUTC([]reservation.Slot{
+		{Start: from, End: time.Date(2024, 12, 23, 18, 0, 0, 0, paris)},
+		{Start: time.Date(2024, 12, 24, 8, 0, 0, 0, paris), End: time.Date(2024, 12, 24, 18, 0, 0, 0, paris)},
+	}), inUTC(slots), "expected the Christmas exception to close Wednesday")
+
+	// Saturday has special opening hours
+	from = time.Date(2024, 12, 28, 0, 0, 0, 0, paris)
+	slots = s.Open(from, from.AddDate(0, 0, 2))
+	assert.Equal(t, inUTC([]reservation.Slot{
+		{Start: time.Date(2024, 12, 28, 10, 0, 0, 0, paris), End: time.Date(2024, 12, 28, 12, 0, 0, 0, paris)},
+	}), inUTC(slots))
+
+	// times given in another time zone
+	from = time.Date(2024, 12, 23, 6, 0, 0, 0, time.UTC)
+	slots = s.Open(from, from.Add(2*time.Hour))
+	assert.Equal(t, inUTC([]reservation.Slot{
+		{Start: time.Date(2024, 12, 23, 7, 0, 0, 0, time.UTC), End: from.Add(2 * time.Hour)},
+	}), inUTC(slots))
+}
+
+func TestOpenAcrossMidnight(t *testing.T) {
+	s := Schedule{TimeZone: "UTC"}
+	s.Weekly[time.Friday] = []Period{{Open: 20 * 60, Close: endOfDay}}
+	s.Weekly[time.Saturday] = []Period{{Open: 0, Close: 2 * 60}}
+
+	from := time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)
+	slots := s.Open(from, from.AddDate(0, 0, 2))
+
+	assert.Equal(t, inUTC([]reservation.Slot{
+		{Start: time.Date(2024, 12, 27, 20, 0, 0, 0, time.UTC), End: time.Date(2024, 12, 28, 2, 0, 0, 0, time.UTC)},
+	}), inUTC(slots), "expected periods following each other to be joined")
+}
+
+func TestHours(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	own := Schedule{Scope: ScopeRoom, Key: "1", TimeZone: "Europe/Paris"}
+	own.Weekly[time.Monday] = []Period{{Open: 6 * 60, Close: 10 * 60}, {Open: 14 * 60, Close: 22 * 60}}
+
+	hours := Hours{officeHours(), own}
+
+	day := time.Date(2024, 12, 23, 0, 0, 0, 0, paris)
+	at := func(h int) time.Time {
+		return day.Add(time.Duration(h) * time.Hour)
+	}
+
+	assert.Equal(t, inUTC([]reservation.Slot{
+		{Start: at(8), End: at(10)},
+		{Start: at(14), End: at(18)},
+	}), inUTC(hours.Open(day, day.AddDate(0, 0, 1))), "expected the room open only when its building is")
+
+	assert.NoError(t, hours.Check(at(8), at(10)))
+	assert.ErrorIs(t, hours.Check(at(7), at(9)), reservation.ErrorClosed)
+	assert.ErrorIs(t, hours.Check(at(9), at(15)), reservation.ErrorClosed)
+	assert.NoError(t, Hours(nil).Check(at(2), at(3)), "expected rooms without schedule to be always open")
+
+	taken := []reservation.Reservation{{RoomID: "1", StartTime: at(15), EndTime: at(16)}}
+	assert.Equal(t, inUTC([]reservation.Slot{
+		{Start: at(8), End: at(10)},
+		{Start: at(14), End: at(15)},
+		{Start: at(16), End: at(18)},
+	}), inUTC(hours.FreeSlots(taken, day, day.AddDate(0, 0, 1))))
+}
+
+func TestRequestValidate(t *testing.T) {
+	valid := Request{
+		TimeZone: "Europe/Paris",
+		Weekly: map[string][]Period{
+			"monday": {{Open: 8 * 60, Close: 12 * 60}, {Open: 13 * 60, Close: 18 * 60}},
+		},
+		Exceptions: []Exception{{Date: "2024-12-25", Reason: "Christmas"}},
+	}
+	require.NoError(t, valid.Validate())
+
+	s := valid.ToSchedule(ScopeBuilding, "HQ")
+	assert.Equal(t, valid.Weekly["monday"], s.Weekly[time.Monday])
+	assert.Equal(t, valid.Weekly, ToResponse(s).Weekly)
+
+	invalid := map[string]Request{
+		"no time zone":      {},
+		"unknown time zone": {TimeZone: "Mars/Olympus"},
+		"unknown day":       {TimeZone: "UTC", Weekly: map[string][]Period{"someday": nil}},
+		"empty period":      {TimeZone: "UTC", Weekly: map[string][]Period{"monday": {{Open: 60, Close: 60}}}},
+		"overlapping periods": {TimeZone: "UTC", Weekly: map[string][]Period{
+			"monday": {{Open: 13 * 60, Close: 18 * 60}, {Open: 8 * 60, Close: 14 * 60}},
+		}},
+		"invalid date":   {TimeZone: "UTC", Exceptions: []Exception{{Date: "25-12-2024"}}},
+		"duplicate date": {TimeZone: "UTC", Exceptions: []Exception{{Date: "2024-12-25"}, {Date: "2024-12-25"}}},
+		"duplicate day":  {TimeZone: "UTC", Weekly: map[string][]Period{"Monday": nil, "monday": nil}},
+	}
+
+	for name, req := range invalid {
+		assert.Error(t, req.Validate(), name)
+	}
+}
+
+type fakeSchedules struct {
+	Repository
+	hours map[string]Hours
+}
+
+func (f *fakeSchedules) ForRooms(_ context.Context, roomIDs []string) (map[string]Hours, error) {
+	return f.hours, nil
+}
+
+type fakeReservations struct {
+	reservation.Repository
+	data     map[string]reservation.Reservation
+	imported []reservation.Reservation
+	restored []string
+}
+
+func (f *fakeReservations) Create(_ context.Context, data reservation.Reservation) (string, error) {
+	return "created", nil
+}
+
+func (f *fakeReservations) Get(_ context.Context, ID string) (reservation.Reservation, error) {
+	data, ok := f.data[ID]
+	if !ok {
+		return reservation.Reservation{}, reservation.ErrorNotFound
+	}
+
+	return data, nil
+}
+
+func (f *fakeReservations) Update(_ context.Context, ID string, data reservation.Reservation) error {
+	return nil
+}
+
+func (f *fakeReservations) Restore(_ context.Context, ID string) error {
+	f.restored = append(f.restored, ID)
+	return nil
+}
+
+func (f *fakeReservations) RestoreWith(_ context.Context, ID string, data reservation.Reservation) error {
+	f.restored = append(f.restored, ID)
+	return nil
+}
+
+func (f *fakeReservations) Import(_ context.Context, data []reservation.Reservation, dryRun bool) ([]reservation.ImportResult, error) {
+	f.imported = data
+
+	results := make([]reservation.ImportResult, len(data))
+	for i := range data {
+		results[i].ID = "imported"
+	}
+
+	return results, nil
+}
+
+func TestReservations(t *testing.T) {
+	ctx := context.Background()
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	at := func(h int) time.Time {
+		return time.Date(2024, 12, 23, h, 0, 0, 0, paris)
+	}
+
+	cancelledAt := at(8)
+	existing := reservation.Reservation{ID: "r1", RoomID: "1", StartTime: at(9), EndTime: at(10)}
+	reservations := &fakeReservations{data: map[string]reservation.Reservation{
+		existing.ID: existing,
+		"open":      {ID: "open", RoomID: "1", StartTime: at(11), EndTime: at(12), CancelledAt: &cancelledAt},
+		"closed":    {ID: "closed", RoomID: "1", StartTime: at(19), EndTime: at(20), CancelledAt: &cancelledAt},
+	}}
+
+	repo := Reservations(reservations, &fakeSchedules{hours: map[string]Hours{"1": {officeHours()}}})
+
+	_, err = repo.Create(ctx, reservation.Reservation{RoomID: "1", StartTime: at(9), EndTime: at(10)})
+	assert.NoError(t, err)
+
+	_, err = repo.Create(ctx, reservation.Reservation{RoomID: "1", StartTime: at(17), EndTime: at(19)})
+	assert.ErrorIs(t, err, reservation.ErrorClosed)
+
+	_, err = repo.Create(ctx, reservation.Reservation{RoomID: "2", StartTime: at(2), EndTime: at(3)})
+	assert.NoError(t, err, "expected rooms without schedule to be always open")
+
+	assert.NoError(t, repo.Update(ctx, existing.ID, reservation.Reservation{EndTime: at(12)}))
+	assert.ErrorIs(t, repo.Update(ctx, existing.ID, reservation.Reservation{EndTime: at(20)}), reservation.ErrorClosed,
+		"expected the start time kept to be checked along with the new end time")
+	assert.NoError(t, repo.Update(ctx, existing.ID, reservation.Reservation{RoomID: "2", EndTime: at(20)}))
+	assert.ErrorIs(t, repo.Update(ctx, "missing", reservation.Reservation{EndTime: at(12)}), reservation.ErrorNotFound)
+
+	assert.NoError(t, repo.Restore(ctx, "open"))
+	assert.ErrorIs(t, repo.Restore(ctx, "closed"), reservation.ErrorClosed, "expected reservations now outside opening hours kept cancelled")
+	assert.ErrorIs(t, repo.Restore(ctx, "missing"), reservation.ErrorNotFound)
+	assert.NoError(t, repo.RestoreWith(ctx, "closed", reservation.Reservation{StartTime: at(13), EndTime: at(14)}),
+		"expected reservations moved back into opening hours restored")
+	assert.ErrorIs(t, repo.RestoreWith(ctx, "open", reservation.Reservation{EndTime: at(20)}), reservation.ErrorClosed)
+	assert.Equal(t, []string{"open", "closed"}, reservations.restored)
+
+	results, err := repo.Import(ctx, []reservation.Reservation{
+		{RoomID: "1", StartTime: at(6), EndTime: at(7)},
+		{RoomID: "1", StartTime: at(11), EndTime: at(12)},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []reservation.ImportResult{{Err: reservation.ErrorClosed}, {ID: "imported"}}, results)
+	assert.Len(t, reservations.imported, 1, "expected only reservations within opening hours to be imported")
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"room-reservation/internal/domain/event"
+	"time"
+)
+
+type CreateRequest struct {
+	URL        string   `json:"url" example:"https://example.com/hooks/reservations"`
+	EventTypes []string `json:"event_types" example:"ReservationCreated,ReservationCancelled"`
+	RoomIDs    []string `json:"room_ids" example:"1"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+func (r *CreateRequest) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https url")
+	}
+
+	for _, t := range r.EventTypes {
+		switch t {
+		case event.TypeReservationCreated, event.TypeReservationUpdated, event.TypeReservationCancelled, event.TypeReservationRestored:
+		default:
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+
+	return nil
+}
+
+// LookupIP resolves the host of urls, as net.Resolver.LookupNetIP does.
+type LookupIP func(ctx context.Context, network, host string) ([]netip.Addr, error)
+
+// CheckHost refuses urls whose host is or resolves to a loopback, link-local, private or unspecified address,
+// through which subscriptions could reach the network of the service, such as the metadata endpoint of cloud providers.
+func (r *CreateRequest) CheckHost(ctx context.Context, lookup LookupIP) error {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return err
+	}
+
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil {
+		addrs = append(addrs, addr)
+	} else if addrs, err = lookup(ctx, "ip", u.Hostname()); err != nil {
+		return fmt.Errorf("could not resolve url host: %w", err)
+	}
+
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsPrivate() || addr.IsUnspecified() {
+			return ErrorPrivateHost
+		}
+	}
+
+	return nil
+}
+
+type Response struct {
+	ID           string     `json:"id"`
+	URL          string     `json:"url"`
+	EventTypes   []string   `json:"event_types"`
+	RoomIDs      []string   `json:"room_ids"`
+	Secret       string     `json:"secret,omitempty"`
+	Active       bool       `json:"active"`
+	FailureCount int        `json:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ToResponse leaves out the secret, it is only shown once when the subscription is created.
+func ToResponse(data Subscription) Response {
+	return Response{
+		ID:           data.ID,
+		URL:          data.URL,
+		EventTypes:   data.EventTypes,
+		RoomIDs:      data.RoomIDs,
+		Active:       !data.Disabled(),
+		FailureCount: data.FailureCount,
+		DisabledAt:   data.DisabledAt,
+		CreatedAt:    data.CreatedAt,
+	}
+}
+
+func ToResponseSlice(data []Subscription) []Response {
+	res := make([]Response, 0)
+
+	for _, s := range data {
+		res = append(res, ToResponse(s))
+	}
+
+	return res
+}
+
+type DeliveryResponse struct {
+	ID             int64           `json:"id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" enums:"pending,delivered,failed"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func ToDeliveryResponse(data Delivery) DeliveryResponse {
+	res := DeliveryResponse{
+		ID:             data.ID,
+		EventID:        data.EventID,
+		EventType:      data.EventType,
+		Payload:        data.Payload,
+		Status:         data.Status,
+		Attempts:       data.Attempts,
+		ResponseStatus: data.ResponseStatus,
+		LastError:      data.LastError,
+		DeliveredAt:    data.DeliveredAt,
+		CreatedAt:      data.CreatedAt,
+	}
+
+	if data.Status == StatusPending {
+		res.NextAttemptAt = &data.NextAttemptAt
+	}
+
+	return res
+}
+
+func ToDeliveryResponseSlice(data []Delivery) []DeliveryResponse {
+	res := make([]DeliveryResponse, 0)
+
+	for _, d := range data {
+		res = append(res, ToDeliveryResponse(d))
+	}
+
+	return res
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+	"room-reservation/internal/domain/event"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Subscription) (ID string, err error)
+	Get(ctx context.Context, ID string) (Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	Delete(ctx context.Context, ID string) error
+	// Enable enables a subscription again, resetting its failures.
+	Enable(ctx context.Context, ID string) error
+
+	// Enqueue creates deliveries of e for every active subscription matching it, once per subscription.
+	Enqueue(ctx context.Context, e event.Event) error
+	// Deliver hands up to limit due deliveries of active subscriptions to send and records their results,
+	// scheduling retries and disabling subscriptions that keep failing.
+	Deliver(ctx context.Context, limit int, send func(context.Context, Subscription, Delivery) Result) (int, error)
+	Deliveries(ctx context.Context, subscriptionID string) ([]Delivery, error)
+	// Replay schedules a delivery to be sent again.
+	Replay(ctx context.Context, subscriptionID string, deliveryID int64) error
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var ErrorInvalidSignature error = errors.New("invalid webhook signature")
+
+// Sign signs body sent at timestamp with HMAC-SHA256 of "{timestamp}.{body}".
+// The result is the value of the signature header: t={timestamp},v1={hex signature}.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks a signature header of body, rejecting signatures older than tolerance to prevent replays.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, sig string
+
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrorInvalidSignature
+	}
+
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrorInvalidSignature
+	}
+
+	expected, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(expected, mac(secret, ts, body)) {
+		return ErrorInvalidSignature
+	}
+
+	return nil
+}
+
+// NewSecret generates a secret for signing deliveries.
+func NewSecret() string {
+	bytes := make([]byte, 24)
+	rand.Read(bytes)
+	return "whsec_" + hex.EncodeToString(bytes)
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+
+	return h.Sum(nil)
+}
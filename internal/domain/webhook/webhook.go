@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"slices"
+	"time"
+)
+
+// Subscription is an endpoint receiving events, optionally narrowed down to event types and rooms.
+type Subscription struct {
+	ID           string     `db:"id"`
+	URL          string     `db:"url"`
+	EventTypes   []string   `db:"event_types"`
+	RoomIDs      []string   `db:"room_ids"`
+	Secret       string     `db:"secret"`
+	FailureCount int        `db:"failure_count"`
+	DisabledAt   *time.Time `db:"disabled_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (s *Subscription) Disabled() bool {
+	return s.DisabledAt != nil
+}
+
+// Matches reports whether the subscription wants to receive e.
+func (s *Subscription) Matches(e event.Event) bool {
+	if len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, e.Type) {
+		return false
+	}
+
+	if len(s.RoomIDs) > 0 && !slices.Contains(s.RoomIDs, e.RoomID) {
+		return false
+	}
+
+	return true
+}
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Delivery is an attempt to send an event to a subscription, kept as a log that can be replayed.
+type Delivery struct {
+	ID             int64           `db:"id"`
+	SubscriptionID string          `db:"subscription_id"`
+	EventID        int64           `db:"event_id"`
+	EventType      string          `db:"event_type"`
+	Payload        json.RawMessage `db:"payload"`
+	Status         string          `db:"status"`
+	Attempts       int             `db:"attempts"`
+	ResponseStatus int             `db:"response_status"`
+	LastError      string          `db:"last_error"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at"`
+	DeliveredAt    *time.Time      `db:"delivered_at"`
+	CreatedAt      time.Time       `db:"created_at"`
+}
+
+// Result is the outcome of sending a delivery.
+type Result struct {
+	ResponseStatus int
+	Err            error
+}
+
+const (
+	// MaxAttempts is how many times a delivery is sent before giving up on it.
+	MaxAttempts = 10
+	// DisableAfterFailures is how many failed attempts in a row disable a subscription.
+	DisableAfterFailures = 20
+)
+
+// Backoff is how long to wait before attempts to send a delivery.
+var Backoff = event.Backoff{Min: 10 * time.Second, Max: time.Hour}
+
+var ErrorNotFound error = errors.New("webhook not found")
+var ErrorDeliveryNotFound error = errors.New("webhook delivery not found")
+var ErrorPrivateHost error = errors.New("url must not point to a loopback, link-local or private address")
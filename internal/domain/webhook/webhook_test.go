@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"room-reservation/internal/domain/event"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	now := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"ReservationCreated"}`)
+
+	header := Sign("secret", now, body)
+
+	tests := map[string]struct {
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		"Valid":          {secret: "secret", header: header, body: body, now: now},
+		"Wrong secret":   {secret: "other", header: header, body: body, now: now, err: ErrorInvalidSignature},
+		"Tampered body":  {secret: "secret", header: header, body: []byte(`{}`), now: now, err: ErrorInvalidSignature},
+		"Expired":        {secret: "secret", header: header, body: body, now: now.Add(10 * time.Minute), err: ErrorInvalidSignature},
+		"Invalid header": {secret: "secret", header: "v1=abc", body: body, now: now, err: ErrorInvalidSignature},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Verify(test.secret, test.header, test.body, 5*time.Minute, test.now)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	e := event.Event{Type: event.TypeReservationCreated, RoomID: "1"}
+
+	tests := []struct {
+		sub      Subscription
+		expected bool
+	}{
+		{sub: Subscription{}, expected: true},
+		{sub: Subscription{EventTypes: []string{event.TypeReservationCreated}}, expected: true},
+		{sub: Subscription{EventTypes: []string{event.TypeReservationCancelled}}, expected: false},
+		{sub: Subscription{RoomIDs: []string{"1", "2"}}, expected: true},
+		{sub: Subscription{RoomIDs: []string{"2"}}, expected: false},
+	}
+
+	for _, test := range tests {
+		assert.Equalf(t, test.expected, test.sub.Matches(e), "subscription %+v", test.sub)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	hosts := map[string][]netip.Addr{
+		"hooks.example.com":    {netip.MustParseAddr("93.184.215.14")},
+		"internal.example.com": {netip.MustParseAddr("93.184.215.14"), netip.MustParseAddr("10.0.0.5")},
+	}
+	lookup := func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+		addrs, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+
+		return addrs, nil
+	}
+
+	tests := map[string]struct {
+		url string
+		err error
+	}{
+		"Public address":  {url: "https://93.184.215.14/hooks"},
+		"Public name":     {url: "https://hooks.example.com/hooks"},
+		"Loopback":        {url: "http://127.0.0.1:8080/hooks", err: ErrorPrivateHost},
+		"Loopback IPv6":   {url: "http://[::1]/hooks", err: ErrorPrivateHost},
+		"Mapped loopback": {url: "http://[::ffff:127.0.0.1]/hooks", err: ErrorPrivateHost},
+		"Cloud metadata":  {url: "http://169.254.169.254/latest/meta-data", err: ErrorPrivateHost},
+		"Private network": {url: "https://192.168.1.10/hooks", err: ErrorPrivateHost},
+		"Unspecified":     {url: "http://0.0.0.0/hooks", err: ErrorPrivateHost},
+		"Private name":    {url: "https://internal.example.com/hooks", err: ErrorPrivateHost},
+	}
+
+	for name, test := range tests {
+		req := CreateRequest{URL: test.url}
+		assert.ErrorIs(t, req.CheckHost(context.Background(), lookup), test.err, name)
+	}
+
+	req := CreateRequest{URL: "https://unknown.example.com/hooks"}
+	assert.Error(t, req.CheckHost(context.Background(), lookup), "expected hosts that can't be resolved refused")
+}
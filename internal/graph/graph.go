@@ -0,0 +1,130 @@
+// Package graph serves rooms and their reservations over GraphQL, batching the queries of a request with dataloaders.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/stream"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxParallelism bounds the fields resolved at once, which is also the most rooms a dataloader batches together.
+const maxParallelism = 100
+
+// Resolver is the root resolver of the schema.
+type Resolver struct {
+	reservationRepo reservation.Repository
+	roomRepo        room.Repository
+	scheduleRepo    schedule.Repository
+	broker          *stream.Broker
+}
+
+// Handler serves queries and mutations over HTTP and subscriptions over WebSocket.
+type Handler struct {
+	schema   *graphql.Schema
+	resolver *Resolver
+	upgrader websocket.Upgrader
+}
+
+type Option func(*Handler)
+
+// WithAllowedOrigins sets the origins of web pages allowed to open subscriptions besides the service itself.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(h *Handler) {
+		h.upgrader.CheckOrigin = router.CheckOrigin(origins...)
+	}
+}
+
+// NewHandler creates a handler resolving changes published to broker. broker may be nil, subscriptions then fail.
+// scheduleRepo may be nil as well, rooms are then always open.
+func NewHandler(reservationRepo reservation.Repository, roomRepo room.Repository, scheduleRepo schedule.Repository, broker *stream.Broker, opts ...Option) *Handler {
+	r := &Resolver{
+		reservationRepo: reservationRepo,
+		roomRepo:        roomRepo,
+		scheduleRepo:    scheduleRepo,
+		broker:          broker,
+	}
+
+	s := graphql.MustParseSchema(schema, r,
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(10),
+		graphql.MaxParallelism(maxParallelism),
+	)
+
+	h := &Handler{
+		schema:   s,
+		resolver: r,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			Subprotocols:    []string{wsProtocol},
+			CheckOrigin:     router.CheckOrigin(),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Error codes set in the extensions of errors.
+const (
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodeFailedPrecondition = "FAILED_PRECONDITION"
+	CodeForbidden          = "FORBIDDEN"
+	CodeInternal           = "INTERNAL"
+)
+
+type codedError struct {
+	code    string
+	message string
+}
+
+func (e *codedError) Error() string {
+	return e.message
+}
+
+func (e *codedError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func badUserInput(err error) error {
+	return &codedError{code: CodeBadUserInput, message: err.Error()}
+}
+
+// resolverError maps domain errors to error codes. Unexpected errors are logged and not shown to the caller.
+func resolverError(ctx context.Context, err error) error {
+	var code string
+
+	switch {
+	case errors.Is(err, reservation.ErrorNotFound), errors.Is(err, room.ErrorNotFound):
+		code = CodeNotFound
+	case errors.Is(err, reservation.ErrorOverlaps), errors.Is(err, reservation.ErrorImported):
+		code = CodeConflict
+	case errors.Is(err, reservation.ErrorCancelled), errors.Is(err, reservation.ErrorNotCancelled),
+		errors.Is(err, reservation.ErrorClosed):
+		code = CodeFailedPrecondition
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	default:
+		log.LoggerFromContext(ctx).Err(err).Caller(1).Send()
+		return &codedError{code: CodeInternal, message: "internal error"}
+	}
+
+	return &codedError{code: code, message: err.Error()}
+}
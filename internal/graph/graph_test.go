@@ -0,0 +1,455 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/stream"
+	"room-reservation/pkg/router"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+
+type reservationRepository struct {
+	reservation.Repository
+
+	mu      sync.Mutex
+	data    []reservation.Reservation
+	batches [][]string
+	opts    []reservation.ListOptions
+}
+
+func (r *reservationRepository) ListForRooms(ctx context.Context, roomIDs []string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, roomIDs)
+	r.opts = append(r.opts, opts)
+
+	var res []reservation.Reservation
+	for _, d := range r.data {
+		for _, ID := range roomIDs {
+			if d.RoomID == ID && d.EndTime.After(opts.From) && (opts.To.IsZero() || d.StartTime.Before(opts.To)) {
+				res = append(res, d)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (r *reservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	for _, d := range r.data {
+		if d.Overlaps(data) {
+			return "", reservation.ErrorOverlaps
+		}
+	}
+
+	data.ID = "new"
+	r.data = append(r.data, data)
+
+	return data.ID, nil
+}
+
+func (r *reservationRepository) Get(ctx context.Context, ID string) (reservation.Reservation, error) {
+	for _, d := range r.data {
+		if d.ID == ID {
+			return d, nil
+		}
+	}
+
+	return reservation.Reservation{}, reservation.ErrorNotFound
+}
+
+func (r *reservationRepository) Cancel(ctx context.Context, ID string, reason string) error {
+	for i, d := range r.data {
+		if d.ID == ID {
+			now := time.Now()
+			r.data[i].CancelledAt = &now
+			r.data[i].CancelReason = reason
+			return nil
+		}
+	}
+
+	return reservation.ErrorNotFound
+}
+
+type roomRepository struct {
+	room.Repository
+
+	mu      sync.Mutex
+	data    []room.Room
+	batches [][]string
+}
+
+func (r *roomRepository) List(ctx context.Context, filter room.Filter) ([]room.Room, error) {
+	return r.data, nil
+}
+
+func (r *roomRepository) GetMany(ctx context.Context, IDs []string) ([]room.Room, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, IDs)
+
+	var res []room.Room
+	for _, rm := range r.data {
+		for _, ID := range IDs {
+			if rm.ID == ID {
+				res = append(res, rm)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func newRepositories() (*reservationRepository, *roomRepository) {
+	reservations := &reservationRepository{data: []reservation.Reservation{
+		{ID: "a", RoomID: "1", UserID: "alice", StartTime: day.Add(9 * time.Hour), EndTime: day.Add(10 * time.Hour)},
+		{ID: "b", RoomID: "2", UserID: "bob", StartTime: day.Add(11 * time.Hour), EndTime: day.Add(12 * time.Hour)},
+		{ID: "c", RoomID: "unknown", UserID: "carol", StartTime: day.Add(9 * time.Hour), EndTime: day.Add(10 * time.Hour)},
+	}}
+
+	rooms := &roomRepository{data: []room.Room{
+		{ID: "1", Name: "Everest", Building: "HQ", Capacity: 8},
+		{ID: "2", Name: "K2", Building: "HQ", Capacity: 4},
+		{ID: "3", Name: "Denali", Building: "HQ", Capacity: 12},
+	}}
+
+	return reservations, rooms
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h http.Handler, query string, variables map[string]any) response {
+	t.Helper()
+
+	return postAs(t, h, router.Identity{}, query, variables)
+}
+
+// postAs posts a query on behalf of the caller id.
+func postAs(t *testing.T, h http.Handler, id router.Identity, query string, variables map[string]any) response {
+	t.Helper()
+
+	body, err := json.Marshal(Request{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req.WithContext(router.WithIdentity(req.Context(), id)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+	return res
+}
+
+func TestRoomsQuery(t *testing.T) {
+	reservations, rooms := newRepositories()
+	h := NewHandler(reservations, rooms, nil, nil)
+
+	query := `query ($from: Time!, $to: Time!) {
+		rooms {
+			id
+			reservations(from: $from, to: $to) { id room { name } }
+			availability(from: $from, to: $to) { available slots { start end } }
+		}
+	}`
+
+	res := post(t, h, query, map[string]any{
+		"from": day.Add(8 * time.Hour).Format(time.RFC3339),
+		"to":   day.Add(18 * time.Hour).Format(time.RFC3339),
+	})
+	require.Empty(t, res.Errors)
+
+	var data struct {
+		Rooms []struct {
+			ID           string
+			Reservations []struct {
+				ID   string
+				Room struct{ Name string }
+			}
+			Availability struct {
+				Available bool
+				Slots     []struct{ Start, End time.Time }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(res.Data, &data))
+
+	require.Len(t, data.Rooms, 3)
+	assert.Equal(t, "a", data.Rooms[0].Reservations[0].ID)
+	assert.Equal(t, "Everest", data.Rooms[0].Reservations[0].Room.Name)
+	assert.False(t, data.Rooms[0].Availability.Available)
+	assert.Len(t, data.Rooms[0].Availability.Slots, 2)
+	assert.Empty(t, data.Rooms[2].Reservations)
+	assert.True(t, data.Rooms[2].Availability.Available)
+
+	assert.Len(t, reservations.batches, 1, "expected reservations of every room to be loaded at once")
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, reservations.batches[0])
+	assert.Len(t, rooms.batches, 1, "expected rooms of every reservation to be loaded at once")
+}
+
+type scheduleRepository struct {
+	schedule.Repository
+
+	hours   map[string]schedule.Hours
+	batches [][]string
+}
+
+func (r *scheduleRepository) ForRooms(ctx context.Context, roomIDs []string) (map[string]schedule.Hours, error) {
+	r.batches = append(r.batches, roomIDs)
+
+	return r.hours, nil
+}
+
+func TestAvailabilityOpeningHours(t *testing.T) {
+	reservations, rooms := newRepositories()
+
+	mornings := schedule.Schedule{Scope: schedule.ScopeRoom, Key: "1", TimeZone: "UTC"}
+	mornings.Weekly[day.Weekday()] = []schedule.Period{{Open: 8 * 60, Close: 12 * 60}}
+
+	schedules := &scheduleRepository{hours: map[string]schedule.Hours{"1": {mornings}}}
+	h := NewHandler(reservations, rooms, schedules, nil)
+
+	query := `query ($from: Time!, $to: Time!) {
+		rooms { id availability(from: $from, to: $to) { available slots { start end } } }
+	}`
+
+	res := post(t, h, query, map[string]any{
+		"from": day.Add(8 * time.Hour).Format(time.RFC3339),
+		"to":   day.Add(18 * time.Hour).Format(time.RFC3339),
+	})
+	require.Empty(t, res.Errors)
+
+	var data struct {
+		Rooms []struct {
+			ID           string
+			Availability struct {
+				Available bool
+				Slots     []struct{ Start, End time.Time }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(res.Data, &data))
+
+	require.Len(t, data.Rooms, 3)
+	assert.Equal(t, []struct{ Start, End time.Time }{
+		{Start: day.Add(8 * time.Hour), End: day.Add(9 * time.Hour)},
+		{Start: day.Add(10 * time.Hour), End: day.Add(12 * time.Hour)},
+	}, data.Rooms[0].Availability.Slots, "expected the afternoon the room is closed to be left out")
+	assert.True(t, data.Rooms[2].Availability.Available, "expected rooms without schedule to be always open")
+
+	assert.Len(t, schedules.batches, 1, "expected opening hours of every room to be loaded at once")
+}
+
+func TestReservationQuery(t *testing.T) {
+	reservations, rooms := newRepositories()
+	h := NewHandler(reservations, rooms, nil, nil)
+
+	res := post(t, h, `{ reservation(id: "c") { userId room { id name } } }`, nil)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"reservation":{"userId":"carol","room":{"id":"unknown","name":""}}}`, string(res.Data),
+		"expected rooms not described to only have an id")
+
+	res = post(t, h, `{ reservation(id: "missing") { id } }`, nil)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"reservation":null}`, string(res.Data))
+}
+
+func TestBookMutation(t *testing.T) {
+	reservations, rooms := newRepositories()
+	h := NewHandler(reservations, rooms, nil, nil)
+
+	mutation := `mutation ($start: Time!, $end: Time!) {
+		book(roomId: "1", startTime: $start, endTime: $end) { id room { name } }
+	}`
+
+	res := post(t, h, mutation, map[string]any{
+		"start": time.Now().Add(time.Hour).Format(time.RFC3339),
+		"end":   time.Now().Add(2 * time.Hour).Format(time.RFC3339),
+	})
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"book":{"id":"new","room":{"name":"Everest"}}}`, string(res.Data))
+
+	res = post(t, h, mutation, map[string]any{
+		"start": time.Now().Add(time.Hour).Format(time.RFC3339),
+		"end":   time.Now().Add(2 * time.Hour).Format(time.RFC3339),
+	})
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, CodeConflict, res.Errors[0].Extensions["code"], "expected overlapping reservation to be rejected")
+
+	res = post(t, h, mutation, map[string]any{
+		"start": time.Now().Add(2 * time.Hour).Format(time.RFC3339),
+		"end":   time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, CodeBadUserInput, res.Errors[0].Extensions["code"])
+}
+
+func TestTimeOffsets(t *testing.T) {
+	reservations, rooms := newRepositories()
+	h := NewHandler(reservations, rooms, nil, nil)
+
+	paris := time.FixedZone("CEST", 2*60*60)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour).In(paris)
+
+	mutation := `mutation ($start: Time!, $end: Time!) { book(roomId: "3", startTime: $start, endTime: $end) { id } }`
+	res := post(t, h, mutation, map[string]any{
+		"start": start.Format(time.RFC3339),
+		"end":   start.Add(time.Hour).Format(time.RFC3339),
+	})
+	require.Empty(t, res.Errors)
+
+	booked, err := reservations.Get(context.Background(), "new")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, booked.StartTime.Location(), "expected times stored in UTC")
+	assert.Equal(t, start.UTC(), booked.StartTime)
+	assert.Equal(t, start.Add(time.Hour).UTC(), booked.EndTime)
+
+	query := `query ($from: Time!, $to: Time!) {
+		room(id: "1") { reservations(from: $from, to: $to) { id } availability(from: $from, to: $to) { available } }
+	}`
+	res = post(t, h, query, map[string]any{
+		"from": day.Add(8 * time.Hour).In(paris).Format(time.RFC3339),
+		"to":   day.Add(18 * time.Hour).In(paris).Format(time.RFC3339),
+	})
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"room":{"reservations":[{"id":"a"}],"availability":{"available":false}}}`, string(res.Data))
+
+	require.NotEmpty(t, reservations.opts)
+	for _, opts := range reservations.opts {
+		assert.Equal(t, time.UTC, opts.From.Location(), "expected searches in UTC")
+		assert.Equal(t, day.Add(8*time.Hour), opts.From)
+		assert.Equal(t, day.Add(18*time.Hour), opts.To)
+	}
+}
+
+func TestCancelMutation(t *testing.T) {
+	reservations, rooms := newRepositories()
+	h := NewHandler(reservations, rooms, nil, nil)
+
+	mutation := `mutation { cancel(id: "a", reason: "moved") { cancelReason } }`
+
+	for name, id := range map[string]router.Identity{
+		"anonymous":   {},
+		"other user":  {UserID: "bob"},
+		"unknown key": {UserID: "bob", APIKey: "unknown"},
+	} {
+		res := postAs(t, h, id, mutation, nil)
+		require.Len(t, res.Errors, 1, name)
+		assert.Equal(t, CodeForbidden, res.Errors[0].Extensions["code"], name)
+	}
+	assert.Nil(t, reservations.data[0].CancelledAt, "expected the reservation kept")
+
+	res := postAs(t, h, router.Identity{UserID: "alice"}, mutation, nil)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"cancel":{"cancelReason":"moved"}}`, string(res.Data), "expected owners to cancel their reservations")
+
+	res = postAs(t, h, router.Identity{APIKey: "key", Authenticated: true}, `mutation { cancel(id: "b") { id } }`, nil)
+	require.Empty(t, res.Errors)
+	assert.NotNil(t, reservations.data[1].CancelledAt, "expected callers with an API key to cancel any reservation")
+
+	res = postAs(t, h, router.Identity{UserID: "alice"}, `mutation { cancel(id: "missing") { id } }`, nil)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, CodeNotFound, res.Errors[0].Extensions["code"])
+}
+
+func TestSubscription(t *testing.T) {
+	reservations, rooms := newRepositories()
+	broker := stream.NewBroker()
+
+	srv := httptest.NewServer(NewHandler(reservations, rooms, nil, broker))
+	defer srv.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: wsConnectionInit}))
+
+	var m wsMessage
+	require.NoError(t, conn.ReadJSON(&m))
+	require.Equal(t, wsConnectionAck, m.Type)
+
+	payload, err := json.Marshal(Request{Query: `subscription { reservationChanged(roomId: "1") { type reservation { id room { name } } } }`})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(wsMessage{ID: "1", Type: wsSubscribe, Payload: payload}))
+
+	data, err := json.Marshal(reservations.data[0])
+	require.NoError(t, err)
+
+	// the subscription starts asynchronously, keep publishing until it receives an event
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		for ctx.Err() == nil {
+			broker.Publish(ctx, event.Event{ID: 1, Type: event.TypeReservationCancelled, RoomID: "2", Data: data})
+			broker.Publish(ctx, event.Event{ID: 2, Type: event.TypeReservationCancelled, RoomID: "1", Data: data})
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	require.NoError(t, conn.ReadJSON(&m))
+	require.Equal(t, wsNext, m.Type)
+	assert.Equal(t, "1", m.ID)
+	assert.JSONEq(t, `{"data":{"reservationChanged":{"type":"ReservationCancelled","reservation":{"id":"a","room":{"name":"Everest"}}}}}`, string(m.Payload))
+
+	cancel()
+
+	require.NoError(t, conn.WriteJSON(wsMessage{ID: "1", Type: wsSubscribe, Payload: payload}))
+
+	// skip events published before the publisher stopped
+	for err == nil {
+		_, _, err = conn.ReadMessage()
+	}
+
+	var closeErr *websocket.CloseError
+	if assert.ErrorAs(t, err, &closeErr) {
+		assert.Equal(t, wsSubscriberExists, closeErr.Code, "expected operation ids to be unique")
+	}
+}
+
+func TestSubscriptionOrigin(t *testing.T) {
+	reservations, rooms := newRepositories()
+
+	srv := httptest.NewServer(NewHandler(reservations, rooms, nil, stream.NewBroker(), WithAllowedOrigins("https://board.example.com")))
+	defer srv.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	_, resp, err := dialer.Dial(url, http.Header{"Origin": {"https://evil.example.com"}})
+	require.Error(t, err, "expected pages of other sites to be refused")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	conn, _, err := dialer.Dial(url, http.Header{"Origin": {"https://board.example.com"}})
+	require.NoError(t, err, "expected allowed origins to be let through")
+	conn.Close()
+}
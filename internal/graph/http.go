@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"room-reservation/pkg/log"
+
+	"github.com/gorilla/websocket"
+)
+
+const maxRequestSize = 1 << 20
+
+// Request is a GraphQL request, sent as the JSON body of POST requests.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// ServeHTTP executes requests, upgrading WebSocket requests to the graphql-transport-ws protocol.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		h.serveWebSocket(w, r)
+		return
+	}
+
+	logger := log.LoggerFromContext(r.Context())
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Query == "" {
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := h.resolver.withLoaders(r.Context(), true)
+
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Err(err).Caller().Send()
+	}
+}
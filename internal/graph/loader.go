@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"context"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// reservationsKey loads the reservations of a room. Keys of rooms listed with the same options
+// are loaded with a single query.
+type reservationsKey struct {
+	roomID string
+	opts   reservation.ListOptions
+}
+
+// loaders batch the queries made while resolving a single request, so that listing rooms with their
+// reservations takes a query for the rooms and another for all of their reservations.
+type loaders struct {
+	rooms        *dataloader.Loader[string, room.Room]
+	reservations *dataloader.Loader[reservationsKey, []reservation.Reservation]
+	hours        *dataloader.Loader[string, schedule.Hours]
+}
+
+type loadersKey struct{}
+
+// withLoaders stores new loaders in ctx. Loaders of long lived requests, like subscriptions, don't cache
+// what they load since it would get out of date.
+func (r *Resolver) withLoaders(ctx context.Context, cache bool) context.Context {
+	var (
+		roomOpts        []dataloader.Option[string, room.Room]
+		reservationOpts []dataloader.Option[reservationsKey, []reservation.Reservation]
+		hoursOpts       []dataloader.Option[string, schedule.Hours]
+	)
+
+	if !cache {
+		roomOpts = append(roomOpts, dataloader.WithCache[string, room.Room](&dataloader.NoCache[string, room.Room]{}))
+		reservationOpts = append(reservationOpts, dataloader.WithCache[reservationsKey, []reservation.Reservation](
+			&dataloader.NoCache[reservationsKey, []reservation.Reservation]{},
+		))
+		hoursOpts = append(hoursOpts, dataloader.WithCache[string, schedule.Hours](&dataloader.NoCache[string, schedule.Hours]{}))
+	}
+
+	l := &loaders{
+		rooms:        dataloader.NewBatchedLoader(r.loadRooms, roomOpts...),
+		reservations: dataloader.NewBatchedLoader(r.loadReservations, reservationOpts...),
+		hours:        dataloader.NewBatchedLoader(r.loadHours, hoursOpts...),
+	}
+
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func (r *Resolver) loadRooms(ctx context.Context, IDs []string) []*dataloader.Result[room.Room] {
+	results := make([]*dataloader.Result[room.Room], len(IDs))
+
+	rooms, err := r.roomRepo.GetMany(ctx, IDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[room.Room]{Error: err}
+		}
+
+		return results
+	}
+
+	byID := make(map[string]room.Room, len(rooms))
+	for _, rm := range rooms {
+		byID[rm.ID] = rm
+	}
+
+	for i, ID := range IDs {
+		rm, ok := byID[ID]
+		if !ok {
+			results[i] = &dataloader.Result[room.Room]{Error: room.ErrorNotFound}
+			continue
+		}
+
+		results[i] = &dataloader.Result[room.Room]{Data: rm}
+	}
+
+	return results
+}
+
+func (r *Resolver) loadReservations(ctx context.Context, keys []reservationsKey) []*dataloader.Result[[]reservation.Reservation] {
+	results := make([]*dataloader.Result[[]reservation.Reservation], len(keys))
+
+	// keys are grouped by their options, which are usually all the same
+	groups := map[reservation.ListOptions][]int{}
+	for i, key := range keys {
+		groups[key.opts] = append(groups[key.opts], i)
+	}
+
+	for opts, indexes := range groups {
+		roomIDs := make([]string, len(indexes))
+		for j, i := range indexes {
+			roomIDs[j] = keys[i].roomID
+		}
+
+		data, err := r.reservationRepo.ListForRooms(ctx, roomIDs, opts)
+
+		byRoom := map[string][]reservation.Reservation{}
+		for _, res := range data {
+			byRoom[res.RoomID] = append(byRoom[res.RoomID], res)
+		}
+
+		for _, i := range indexes {
+			if err != nil {
+				results[i] = &dataloader.Result[[]reservation.Reservation]{Error: err}
+				continue
+			}
+
+			results[i] = &dataloader.Result[[]reservation.Reservation]{Data: byRoom[keys[i].roomID]}
+		}
+	}
+
+	return results
+}
+
+// loadHours loads the opening hours of rooms, rooms are always open without a schedule repository.
+func (r *Resolver) loadHours(ctx context.Context, roomIDs []string) []*dataloader.Result[schedule.Hours] {
+	results := make([]*dataloader.Result[schedule.Hours], len(roomIDs))
+
+	var (
+		hours map[string]schedule.Hours
+		err   error
+	)
+
+	if r.scheduleRepo != nil {
+		hours, err = r.scheduleRepo.ForRooms(ctx, roomIDs)
+	}
+
+	for i, ID := range roomIDs {
+		results[i] = &dataloader.Result[schedule.Hours]{Data: hours[ID], Error: err}
+	}
+
+	return results
+}
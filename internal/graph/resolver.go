@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/router"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+func (r *Resolver) Rooms(ctx context.Context, args struct{ Building *string }) ([]*roomResolver, error) {
+	var filter room.Filter
+	if args.Building != nil {
+		filter.Building = *args.Building
+	}
+
+	data, err := r.roomRepo.List(ctx, filter)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	res := make([]*roomResolver, len(data))
+	for i, rm := range data {
+		res[i] = &roomResolver{root: r, data: rm}
+	}
+
+	return res, nil
+}
+
+func (r *Resolver) Room(ctx context.Context, args struct{ ID graphql.ID }) (*roomResolver, error) {
+	data, err := loadersFromContext(ctx).rooms.Load(ctx, string(args.ID))()
+	if err != nil {
+		if errors.Is(err, room.ErrorNotFound) {
+			return nil, nil
+		}
+
+		return nil, resolverError(ctx, err)
+	}
+
+	return &roomResolver{root: r, data: data}, nil
+}
+
+func (r *Resolver) Reservation(ctx context.Context, args struct{ ID graphql.ID }) (*reservationResolver, error) {
+	data, err := r.reservationRepo.Get(ctx, string(args.ID))
+	if err != nil {
+		if errors.Is(err, reservation.ErrorNotFound) {
+			return nil, nil
+		}
+
+		return nil, resolverError(ctx, err)
+	}
+
+	return &reservationResolver{root: r, data: data}, nil
+}
+
+type bookArgs struct {
+	RoomID    graphql.ID
+	StartTime graphql.Time
+	EndTime   graphql.Time
+}
+
+func (r *Resolver) Book(ctx context.Context, args bookArgs) (*reservationResolver, error) {
+	req := reservation.Request{
+		RoomID:    string(args.RoomID),
+		StartTime: reservation.DateTime{Time: args.StartTime.UTC()},
+		EndTime:   reservation.DateTime{Time: args.EndTime.UTC()},
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, badUserInput(err)
+	}
+
+	data := reservation.Reservation{
+		RoomID:    req.RoomID,
+		UserID:    router.IdentityFromContext(ctx).UserID,
+		StartTime: req.StartTime.Time,
+		EndTime:   req.EndTime.Time,
+	}
+
+	ID, err := r.reservationRepo.Create(ctx, data)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	return r.Reservation(ctx, struct{ ID graphql.ID }{graphql.ID(ID)})
+}
+
+type cancelArgs struct {
+	ID     graphql.ID
+	Reason *string
+}
+
+// Cancel cancels reservations of the caller, or any reservation for callers with an API key.
+func (r *Resolver) Cancel(ctx context.Context, args cancelArgs) (*reservationResolver, error) {
+	var reason string
+	if args.Reason != nil {
+		reason = *args.Reason
+	}
+
+	data, err := r.reservationRepo.Get(ctx, string(args.ID))
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	if !router.IdentityFromContext(ctx).Owns(data.UserID) {
+		return nil, &codedError{code: CodeForbidden, message: "reservation belongs to another user"}
+	}
+
+	if err := r.reservationRepo.Cancel(ctx, data.ID, reason); err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	data, err = r.reservationRepo.Get(ctx, data.ID)
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	return &reservationResolver{root: r, data: data}, nil
+}
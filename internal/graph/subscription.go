@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/log"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// ReservationChanged streams events published to the broker until ctx is done. Subscribers too slow to
+// keep up have their subscription completed and should subscribe again.
+func (r *Resolver) ReservationChanged(ctx context.Context, args struct{ RoomID *graphql.ID }) (<-chan *eventResolver, error) {
+	if r.broker == nil {
+		return nil, errors.New("subscriptions are not enabled")
+	}
+
+	var roomID string
+	if args.RoomID != nil {
+		roomID = string(*args.RoomID)
+	}
+
+	sub := r.broker.Subscribe(roomID)
+
+	c := make(chan *eventResolver)
+
+	go func() {
+		defer close(c)
+		defer sub.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+
+				var data reservation.Reservation
+				if err := json.Unmarshal(e.Data, &data); err != nil {
+					log.LoggerFromContext(ctx).Err(err).Caller().Send()
+					continue
+				}
+
+				res := &eventResolver{data: e, reservation: &reservationResolver{root: r, data: data}}
+
+				select {
+				case c <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return c, nil
+}
@@ -0,0 +1,229 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type roomResolver struct {
+	root *Resolver
+	data room.Room
+}
+
+func (r *roomResolver) ID() graphql.ID {
+	return graphql.ID(r.data.ID)
+}
+
+func (r *roomResolver) Name() string {
+	return r.data.Name
+}
+
+func (r *roomResolver) Building() string {
+	return r.data.Building
+}
+
+func (r *roomResolver) Capacity() int32 {
+	return int32(r.data.Capacity)
+}
+
+type reservationsArgs struct {
+	From             *graphql.Time
+	To               *graphql.Time
+	IncludeCancelled bool
+}
+
+func (r *roomResolver) Reservations(ctx context.Context, args reservationsArgs) ([]*reservationResolver, error) {
+	opts := reservation.ListOptions{IncludeCancelled: args.IncludeCancelled}
+	if args.From != nil {
+		opts.From = args.From.UTC()
+	}
+	if args.To != nil {
+		opts.To = args.To.UTC()
+	}
+
+	data, err := loadersFromContext(ctx).reservations.Load(ctx, reservationsKey{roomID: r.data.ID, opts: opts})()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	res := make([]*reservationResolver, len(data))
+	for i, d := range data {
+		res[i] = &reservationResolver{root: r.root, data: d}
+	}
+
+	return res, nil
+}
+
+type availabilityArgs struct {
+	From graphql.Time
+	To   graphql.Time
+}
+
+func (r *roomResolver) Availability(ctx context.Context, args availabilityArgs) (*availabilityResolver, error) {
+	if !args.To.After(args.From.Time) {
+		return nil, badUserInput(errors.New("to must be after from"))
+	}
+
+	if args.To.Sub(args.From.Time) > schedule.MaxSearch {
+		return nil, badUserInput(errors.New("to must be at most 366 days after from"))
+	}
+
+	opts := reservation.ListOptions{From: args.From.UTC(), To: args.To.UTC()}
+
+	l := loadersFromContext(ctx)
+	reservations := l.reservations.Load(ctx, reservationsKey{roomID: r.data.ID, opts: opts})
+	hours := l.hours.Load(ctx, r.data.ID)
+
+	data, err := reservations()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	open, err := hours()
+	if err != nil {
+		return nil, resolverError(ctx, err)
+	}
+
+	return &availabilityResolver{
+		from:  args.From,
+		to:    args.To,
+		slots: open.FreeSlots(data, args.From.Time, args.To.Time),
+	}, nil
+}
+
+type reservationResolver struct {
+	root *Resolver
+	data reservation.Reservation
+}
+
+func (r *reservationResolver) ID() graphql.ID {
+	return graphql.ID(r.data.ID)
+}
+
+func (r *reservationResolver) Room(ctx context.Context) (*roomResolver, error) {
+	data, err := loadersFromContext(ctx).rooms.Load(ctx, r.data.RoomID)()
+	if err != nil {
+		if !errors.Is(err, room.ErrorNotFound) {
+			return nil, resolverError(ctx, err)
+		}
+
+		data = room.Room{ID: r.data.RoomID}
+	}
+
+	return &roomResolver{root: r.root, data: data}, nil
+}
+
+func (r *reservationResolver) UserID() *string {
+	return optional(r.data.UserID)
+}
+
+func (r *reservationResolver) StartTime() graphql.Time {
+	return graphql.Time{Time: r.data.StartTime}
+}
+
+func (r *reservationResolver) EndTime() graphql.Time {
+	return graphql.Time{Time: r.data.EndTime}
+}
+
+func (r *reservationResolver) CancelledAt() *graphql.Time {
+	if !r.data.Cancelled() {
+		return nil
+	}
+
+	return &graphql.Time{Time: *r.data.CancelledAt}
+}
+
+func (r *reservationResolver) CancelReason() *string {
+	return optional(r.data.CancelReason)
+}
+
+func (r *reservationResolver) CancelledBy() *string {
+	return optional(r.data.CancelledBy)
+}
+
+func (r *reservationResolver) Sequence() int32 {
+	return int32(r.data.Sequence)
+}
+
+func (r *reservationResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.data.UpdatedAt}
+}
+
+type availabilityResolver struct {
+	from  graphql.Time
+	to    graphql.Time
+	slots []reservation.Slot
+}
+
+func (r *availabilityResolver) From() graphql.Time {
+	return r.from
+}
+
+func (r *availabilityResolver) To() graphql.Time {
+	return r.to
+}
+
+func (r *availabilityResolver) Available() bool {
+	return len(r.slots) == 1 && r.slots[0].Start.Equal(r.from.Time) && r.slots[0].End.Equal(r.to.Time)
+}
+
+func (r *availabilityResolver) Slots() []*timeSlotResolver {
+	res := make([]*timeSlotResolver, len(r.slots))
+	for i, s := range r.slots {
+		res[i] = &timeSlotResolver{data: s}
+	}
+
+	return res
+}
+
+type timeSlotResolver struct {
+	data reservation.Slot
+}
+
+func (r *timeSlotResolver) Start() graphql.Time {
+	return graphql.Time{Time: r.data.Start}
+}
+
+func (r *timeSlotResolver) End() graphql.Time {
+	return graphql.Time{Time: r.data.End}
+}
+
+type eventResolver struct {
+	data        event.Event
+	reservation *reservationResolver
+}
+
+func (r *eventResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(r.data.ID, 10))
+}
+
+func (r *eventResolver) Type() string {
+	return r.data.Type
+}
+
+func (r *eventResolver) Actor() string {
+	return r.data.Actor
+}
+
+func (r *eventResolver) Reservation() *reservationResolver {
+	return r.reservation
+}
+
+func (r *eventResolver) OccurredAt() graphql.Time {
+	return graphql.Time{Time: r.data.OccurredAt}
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
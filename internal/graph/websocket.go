@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"room-reservation/pkg/log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Subscriptions follow the graphql-transport-ws protocol,
+// see https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md.
+const (
+	wsProtocol = "graphql-transport-ws"
+
+	wsConnectionInit = "connection_init"
+	wsConnectionAck  = "connection_ack"
+	wsPing           = "ping"
+	wsPong           = "pong"
+	wsSubscribe      = "subscribe"
+	wsNext           = "next"
+	wsError          = "error"
+	wsComplete       = "complete"
+
+	wsInvalidMessage     = 4400
+	wsUnauthorized       = 4401
+	wsInitTimeout        = 4408
+	wsSubscriberExists   = 4409
+	wsTooManyInitRequest = 4429
+
+	wsInitWait   = 10 * time.Second
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+	wsMaxMessage = 64 << 10
+)
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsConn multiplexes the operations of a single connection, writing their results from one goroutine.
+type wsConn struct {
+	conn *websocket.Conn
+	out  chan wsMessage
+
+	mu         sync.Mutex
+	operations map[string]context.CancelFunc
+}
+
+func (h *Handler) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		return
+	}
+	defer conn.Close()
+
+	if conn.Subprotocol() != wsProtocol {
+		closeWebSocket(conn, websocket.CloseProtocolError, "unsupported subprotocol")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	c := &wsConn{
+		conn:       conn,
+		out:        make(chan wsMessage, 16),
+		operations: map[string]context.CancelFunc{},
+	}
+
+	// closing the connection when writing stops ends reading as well
+	go func() {
+		c.write(ctx)
+		cancel()
+		conn.Close()
+	}()
+
+	conn.SetReadLimit(wsMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(wsInitWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	acknowledged := false
+
+	for {
+		var m wsMessage
+		if err := conn.ReadJSON(&m); err != nil {
+			if !acknowledged && isTimeout(err) {
+				closeWebSocket(conn, wsInitTimeout, "connection initialisation timeout")
+			}
+
+			return
+		}
+
+		switch m.Type {
+		case wsConnectionInit:
+			if acknowledged {
+				closeWebSocket(conn, wsTooManyInitRequest, "too many initialisation requests")
+				return
+			}
+
+			acknowledged = true
+			conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+			c.send(ctx, wsMessage{Type: wsConnectionAck})
+		case wsPing:
+			c.send(ctx, wsMessage{Type: wsPong})
+		case wsPong:
+		case wsSubscribe:
+			if !acknowledged {
+				closeWebSocket(conn, wsUnauthorized, "unauthorized")
+				return
+			}
+
+			var req Request
+			if m.ID == "" || json.Unmarshal(m.Payload, &req) != nil || req.Query == "" {
+				closeWebSocket(conn, wsInvalidMessage, "invalid subscribe message")
+				return
+			}
+
+			if !c.start(ctx, h, m.ID, req) {
+				closeWebSocket(conn, wsSubscriberExists, "subscriber for "+m.ID+" already exists")
+				return
+			}
+		case wsComplete:
+			c.stop(m.ID)
+		default:
+			closeWebSocket(conn, wsInvalidMessage, "invalid message type")
+			return
+		}
+	}
+}
+
+// start runs an operation until its results run out or it is stopped, it reports false when an
+// operation with the same id is running.
+func (c *wsConn) start(ctx context.Context, h *Handler, ID string, req Request) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.operations[ID]; ok {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.operations[ID] = cancel
+
+	go func() {
+		defer cancel()
+
+		results, err := h.schema.Subscribe(h.resolver.withLoaders(ctx, false), req.Query, req.OperationName, req.Variables)
+		if err != nil {
+			payload, _ := json.Marshal([]map[string]string{{"message": err.Error()}})
+			c.finish(ctx, ID, wsMessage{ID: ID, Type: wsError, Payload: payload})
+			return
+		}
+
+		for res := range results {
+			payload, err := json.Marshal(res)
+			if err != nil {
+				log.LoggerFromContext(ctx).Err(err).Caller().Send()
+				continue
+			}
+
+			c.send(ctx, wsMessage{ID: ID, Type: wsNext, Payload: payload})
+		}
+
+		c.finish(ctx, ID, wsMessage{ID: ID, Type: wsComplete})
+	}()
+
+	return true
+}
+
+// stop cancels an operation on request of the client, which expects no further messages about it.
+func (c *wsConn) stop(ID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cancel, ok := c.operations[ID]; ok {
+		cancel()
+		delete(c.operations, ID)
+	}
+}
+
+// finish sends the last message of an operation unless the client stopped it.
+func (c *wsConn) finish(ctx context.Context, ID string, m wsMessage) {
+	c.mu.Lock()
+	_, ok := c.operations[ID]
+	delete(c.operations, ID)
+	c.mu.Unlock()
+
+	if ok && ctx.Err() == nil {
+		c.send(ctx, m)
+	}
+}
+
+func (c *wsConn) send(ctx context.Context, m wsMessage) {
+	select {
+	case c.out <- m:
+	case <-ctx.Done():
+	}
+}
+
+func (c *wsConn) write(ctx context.Context) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-c.out:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(m); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func closeWebSocket(conn *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(interface{ Timeout() bool })
+	return ok && netErr.Timeout()
+}
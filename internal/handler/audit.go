@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// withActor stores who is making the request in its context for the audit log.
+func withActor(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		actor := audit.Actor{
+			ID:        actorID(router.IdentityFromContext(r.Context())),
+			RequestID: middleware.GetReqID(r.Context()),
+		}
+
+		next.ServeHTTP(w, r.WithContext(audit.WithActor(r.Context(), actor)))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// actorID names the caller by user, or by a fingerprint of its API key so the key itself is never stored.
+func actorID(id router.Identity) string {
+	switch {
+	case id.UserID != "":
+		return id.UserID
+	case id.APIKey != "":
+		sum := sha256.Sum256([]byte(id.APIKey))
+		return "api-key:" + hex.EncodeToString(sum[:4])
+	}
+
+	return audit.Anonymous
+}
+
+func (h *ReservationHandler) adminRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(router.RequireAPIKey(h.adminAPIKeys...))
+
+	r.Get("/audit", h.queryAudit)
+
+	return r
+}
+
+// @Summary Reservation history
+// @Description List every change made to a reservation, including deleted ones
+// @Tags Reservations
+// @Produce json
+// @Param id path string true "Reservation id"
+// @Success 200 {object} response.BaseObject{data=[]audit.Response}
+// @Success 204
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /reservations/{id}/history [get]
+func (h *ReservationHandler) reservationHistory(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "id")
+
+	data, err := h.auditRepo.History(r.Context(), ID)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if len(data) == 0 {
+		response.NoContent(w)
+		return
+	}
+
+	response.OK(w, r, audit.ToResponseSlice(data))
+}
+
+// @Summary Query audit log
+// @Description Query changes of all reservations, requires an admin API key
+// @Tags Admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param reservation_id query string false "Reservation id"
+// @Param actor query string false "Actor"
+// @Param action query string false "Action" Enums(create, update, delete)
+// @Param from query string false "From, RFC 3339"
+// @Param to query string false "To, RFC 3339"
+// @Param limit query int false "Limit" default(100)
+// @Success 200 {object} response.BaseObject{data=[]audit.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /admin/audit [get]
+func (h *ReservationHandler) queryAudit(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	filter, err := audit.FilterFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, r.URL.RawQuery)
+		return
+	}
+
+	data, err := h.auditRepo.Query(r.Context(), filter)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, audit.ToResponseSlice(data))
+}
@@ -0,0 +1,409 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav"
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/go-chi/chi/v5"
+)
+
+func init() {
+	// chi only routes methods it knows about
+	for _, method := range []string{"PROPFIND", "PROPPATCH", "REPORT", "MKCOL", "COPY", "MOVE"} {
+		chi.RegisterMethod(method)
+	}
+}
+
+// Every room is a calendar of a single principal shared by all clients:
+//
+//	/api/v1/caldav/rooms/                          principal
+//	/api/v1/caldav/rooms/calendars/                calendar home
+//	/api/v1/caldav/rooms/calendars/{roomID}/       calendar of a room
+//	/api/v1/caldav/rooms/calendars/{roomID}/{name} event of a reservation
+const (
+	calDAVPrefix    = "/api/v1/caldav"
+	calDAVPrincipal = calDAVPrefix + "/rooms/"
+	calDAVHome      = calDAVPrincipal + "calendars/"
+)
+
+// calDAVReadMethods can be used without credentials, any other method may change reservations.
+var calDAVReadMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", "REPORT"}
+
+// calDAVHandler serves the calendars of rooms. Writing to them requires one of the client API keys,
+// which calendar clients send as their basic auth password.
+func (h *ReservationHandler) calDAVHandler() http.Handler {
+	handler := &caldav.Handler{
+		Backend: &calDAVBackend{reservationRepo: h.reservationRepo, roomRepo: h.roomRepo},
+		Prefix:  calDAVPrefix,
+	}
+	keys := h.clientAPIKeys()
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(calDAVReadMethods, r.Method) && !router.IdentityFromContext(r.Context()).HasAPIKey(keys...) {
+			// calendar clients only ask for credentials when challenged
+			w.Header().Set("WWW-Authenticate", `Basic realm="room-reservation", charset="UTF-8"`)
+			response.Unauthorized(w)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// calDAVBackend maps CalDAV calendars onto rooms and events onto their active reservations.
+// Events are named after their UID, and deleting them cancels their reservation.
+type calDAVBackend struct {
+	reservationRepo reservation.Repository
+	roomRepo        room.Repository
+}
+
+func (b *calDAVBackend) CurrentUserPrincipal(ctx context.Context) (string, error) {
+	return calDAVPrincipal, nil
+}
+
+func (b *calDAVBackend) CalendarHomeSetPath(ctx context.Context) (string, error) {
+	return calDAVHome, nil
+}
+
+func (b *calDAVBackend) CreateCalendar(ctx context.Context, c *caldav.Calendar) error {
+	return webdav.NewHTTPError(http.StatusForbidden, errors.New("rooms are managed through the API"))
+}
+
+func (b *calDAVBackend) ListCalendars(ctx context.Context) ([]caldav.Calendar, error) {
+	rooms, err := b.roomRepo.List(ctx, room.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := make([]caldav.Calendar, 0, len(rooms))
+	for _, r := range rooms {
+		calendars = append(calendars, calDAVCalendar(r))
+	}
+
+	return calendars, nil
+}
+
+func (b *calDAVBackend) GetCalendar(ctx context.Context, p string) (*caldav.Calendar, error) {
+	roomID, _, err := parseCalDAVPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := b.getRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := calDAVCalendar(r)
+	return &c, nil
+}
+
+func (b *calDAVBackend) GetCalendarObject(ctx context.Context, p string, req *caldav.CalendarCompRequest) (*caldav.CalendarObject, error) {
+	roomID, name, err := parseCalDAVPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.getReservation(ctx, name)
+	if err != nil {
+		return nil, calDAVError(err)
+	}
+
+	if res.RoomID != roomID || res.Cancelled() {
+		return nil, webdav.NewHTTPError(http.StatusNotFound, reservation.ErrorNotFound)
+	}
+
+	co := calDAVObject(res)
+	return &co, nil
+}
+
+func (b *calDAVBackend) ListCalendarObjects(ctx context.Context, p string, req *caldav.CalendarCompRequest) ([]caldav.CalendarObject, error) {
+	roomID, _, err := parseCalDAVPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.getRoom(ctx, roomID); err != nil {
+		return nil, err
+	}
+
+	data, err := b.reservationRepo.List(ctx, roomID, reservation.ListOptions{})
+	if err != nil && !errors.Is(err, reservation.ErrorNotFoundForRoom) {
+		return nil, err
+	}
+
+	objects := make([]caldav.CalendarObject, 0, len(data))
+	for _, res := range data {
+		objects = append(objects, calDAVObject(res))
+	}
+
+	return objects, nil
+}
+
+func (b *calDAVBackend) QueryCalendarObjects(ctx context.Context, p string, query *caldav.CalendarQuery) ([]caldav.CalendarObject, error) {
+	objects, err := b.ListCalendarObjects(ctx, p, &query.CompRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return caldav.Filter(query, objects)
+}
+
+// PutCalendarObject creates or reschedules the reservation of an event.
+// Putting an event that was deleted before restores its reservation.
+func (b *calDAVBackend) PutCalendarObject(ctx context.Context, p string, cal *ical.Calendar, opts *caldav.PutCalendarObjectOptions) (*caldav.CalendarObject, error) {
+	roomID, name, err := parseCalDAVPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.getRoom(ctx, roomID); err != nil {
+		return nil, err
+	}
+
+	data, err := parseCalDAVEvent(cal)
+	if err != nil {
+		return nil, webdav.NewHTTPError(http.StatusBadRequest, err)
+	}
+	data.RoomID = roomID
+	data.UserID = router.IdentityFromContext(ctx).UserID
+
+	existing, err := b.getReservation(ctx, name)
+	if errors.Is(err, reservation.ErrorNotFound) {
+		if opts.IfMatch.IsSet() {
+			return nil, webdav.NewHTTPError(http.StatusPreconditionFailed, errors.New("event does not exist"))
+		}
+
+		ID, err := b.reservationRepo.Create(ctx, data)
+		if err != nil {
+			return nil, calDAVError(err)
+		}
+
+		return b.GetCalendarObject(ctx, calDAVObjectPath(reservation.Reservation{ID: ID, RoomID: roomID, ICalUID: data.ICalUID}), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.RoomID != roomID {
+		return nil, webdav.NewHTTPError(http.StatusConflict, errors.New("event belongs to another room"))
+	}
+
+	if err = checkCalDAVConditions(existing, opts); err != nil {
+		return nil, err
+	}
+
+	update := reservation.Reservation{StartTime: data.StartTime, EndTime: data.EndTime}
+	if existing.Cancelled() {
+		err = b.reservationRepo.RestoreWith(ctx, existing.ID, update)
+	} else {
+		err = b.reservationRepo.Update(ctx, existing.ID, update)
+	}
+	if err != nil {
+		return nil, calDAVError(err)
+	}
+
+	return b.GetCalendarObject(ctx, p, nil)
+}
+
+func (b *calDAVBackend) DeleteCalendarObject(ctx context.Context, p string) error {
+	if _, err := b.GetCalendarObject(ctx, p, nil); err != nil {
+		return err
+	}
+
+	_, name, _ := parseCalDAVPath(p)
+
+	res, err := b.getReservation(ctx, name)
+	if err != nil {
+		return calDAVError(err)
+	}
+
+	return calDAVError(b.reservationRepo.Cancel(ctx, res.ID, "deleted from calendar"))
+}
+
+func (b *calDAVBackend) getRoom(ctx context.Context, ID string) (room.Room, error) {
+	r, err := b.roomRepo.Get(ctx, ID)
+	if errors.Is(err, room.ErrorNotFound) {
+		return room.Room{}, webdav.NewHTTPError(http.StatusNotFound, err)
+	}
+
+	return r, err
+}
+
+// getReservation finds the reservation of an event by its name,
+// which is the UID of events created by calendar clients and the reservation ID otherwise.
+func (b *calDAVBackend) getReservation(ctx context.Context, name string) (reservation.Reservation, error) {
+	res, err := b.reservationRepo.GetByICalUID(ctx, name)
+	if errors.Is(err, reservation.ErrorNotFound) {
+		return b.reservationRepo.Get(ctx, name)
+	}
+
+	return res, err
+}
+
+func checkCalDAVConditions(existing reservation.Reservation, opts *caldav.PutCalendarObjectOptions) error {
+	if opts.IfNoneMatch.IsWildcard() && !existing.Cancelled() {
+		return webdav.NewHTTPError(http.StatusPreconditionFailed, errors.New("event exists"))
+	}
+
+	if opts.IfMatch.IsSet() && !opts.IfMatch.IsWildcard() {
+		etag, err := opts.IfMatch.ETag()
+		if err != nil {
+			return webdav.NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		if etag != calDAVETag(existing) {
+			return webdav.NewHTTPError(http.StatusPreconditionFailed, errors.New("event has changed"))
+		}
+	}
+
+	return nil
+}
+
+func calDAVError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, reservation.ErrorOverlaps):
+		return webdav.NewHTTPError(http.StatusConflict, err)
+	case errors.Is(err, reservation.ErrorNotFound):
+		return webdav.NewHTTPError(http.StatusNotFound, err)
+	case errors.Is(err, reservation.ErrorCancelled), errors.Is(err, reservation.ErrorImported):
+		return webdav.NewHTTPError(http.StatusConflict, err)
+	case errors.Is(err, reservation.ErrorClosed):
+		return webdav.NewHTTPError(http.StatusForbidden, err)
+	case errors.Is(err, reservation.ErrorInvalidTime):
+		return webdav.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	return err
+}
+
+// parseCalDAVPath splits the path of a calendar or event into the room ID and event name.
+func parseCalDAVPath(p string) (roomID, name string, err error) {
+	rest, ok := strings.CutPrefix(path.Clean(p)+"/", calDAVHome)
+	if !ok {
+		return "", "", webdav.NewHTTPError(http.StatusNotFound, fmt.Errorf("no calendar at %s", p))
+	}
+
+	roomID, name, _ = strings.Cut(strings.TrimSuffix(rest, "/"), "/")
+	if roomID == "" || strings.Contains(name, "/") {
+		return "", "", webdav.NewHTTPError(http.StatusNotFound, fmt.Errorf("no calendar at %s", p))
+	}
+
+	return roomID, strings.TrimSuffix(name, ".ics"), nil
+}
+
+// parseCalDAVEvent reads the reservation of a calendar object put by a client, only single events are supported.
+func parseCalDAVEvent(cal *ical.Calendar) (reservation.Reservation, error) {
+	compType, uid, err := caldav.ValidateCalendarObject(cal)
+	if err != nil {
+		return reservation.Reservation{}, err
+	}
+
+	events := cal.Events()
+	if compType != ical.CompEvent || len(events) == 0 {
+		return reservation.Reservation{}, errors.New("calendar object must be an event")
+	}
+
+	if len(events) > 1 || events[0].Props.Get(ical.PropRecurrenceRule) != nil || events[0].Props.Get(ical.PropRecurrenceDates) != nil {
+		return reservation.Reservation{}, errors.New("recurring events are not supported")
+	}
+
+	if uid == "" || strings.Contains(uid, "/") {
+		return reservation.Reservation{}, errors.New("event must have a UID without slashes")
+	}
+
+	ev := events[0]
+
+	if p := ev.Props.Get(ical.PropDateTimeStart); p != nil && p.ValueType() == ical.ValueDate {
+		return reservation.Reservation{}, errors.New("all-day events are not supported")
+	}
+
+	start, err := ev.DateTimeStart(time.UTC)
+	if err != nil {
+		return reservation.Reservation{}, err
+	}
+
+	end, err := ev.DateTimeEnd(time.UTC)
+	if err != nil {
+		return reservation.Reservation{}, err
+	}
+
+	if start.IsZero() || !start.Before(end) {
+		return reservation.Reservation{}, errors.New("event must start before it ends")
+	}
+
+	return reservation.Reservation{
+		ICalUID:   uid,
+		StartTime: start.UTC(),
+		EndTime:   end.UTC(),
+	}, nil
+}
+
+func calDAVCalendar(r room.Room) caldav.Calendar {
+	return caldav.Calendar{
+		Path:                  calDAVHome + r.ID + "/",
+		Name:                  r.Name,
+		Description:           r.Building,
+		SupportedComponentSet: []string{ical.CompEvent},
+	}
+}
+
+// calDAVObjectPath names events after their UID unless it can't be part of a path.
+func calDAVObjectPath(res reservation.Reservation) string {
+	name := res.ID
+	if res.ICalUID != "" && !strings.Contains(res.ICalUID, "/") {
+		name = res.ICalUID
+	}
+
+	return calDAVHome + res.RoomID + "/" + name + ".ics"
+}
+
+func calDAVETag(res reservation.Reservation) string {
+	return res.ID + "-" + strconv.Itoa(res.Sequence)
+}
+
+func calDAVObject(res reservation.Reservation) caldav.CalendarObject {
+	ev := ical.NewEvent()
+	ev.Props.SetText(ical.PropUID, calendar.UID(res))
+	sequence := ical.NewProp(ical.PropSequence)
+	sequence.Value = strconv.Itoa(res.Sequence)
+	ev.Props.Set(sequence)
+	ev.Props.SetDateTime(ical.PropDateTimeStamp, res.UpdatedAt.UTC())
+	ev.Props.SetDateTime(ical.PropLastModified, res.UpdatedAt.UTC())
+	ev.Props.SetDateTime(ical.PropDateTimeStart, res.StartTime.UTC())
+	ev.Props.SetDateTime(ical.PropDateTimeEnd, res.EndTime.UTC())
+	ev.Props.SetText(ical.PropSummary, "Room "+res.RoomID)
+	ev.Props.SetText(ical.PropLocation, res.RoomID)
+	ev.SetStatus(ical.EventConfirmed)
+
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, calendar.ProdID)
+	cal.Children = append(cal.Children, ev.Component)
+
+	return caldav.CalendarObject{
+		Path:    calDAVObjectPath(res),
+		ModTime: res.UpdatedAt,
+		ETag:    calDAVETag(res),
+		Data:    cal,
+	}
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roomRepository struct {
+	room.Repository
+
+	data map[string]room.Room
+}
+
+func (r *roomRepository) Get(ctx context.Context, ID string) (room.Room, error) {
+	data, ok := r.data[ID]
+	if !ok {
+		return room.Room{}, room.ErrorNotFound
+	}
+
+	return data, nil
+}
+
+func (r *roomRepository) List(ctx context.Context, filter room.Filter) ([]room.Room, error) {
+	res := make([]room.Room, 0, len(r.data))
+	for _, data := range r.data {
+		res = append(res, data)
+	}
+
+	return res, nil
+}
+
+const calDAVRoom = "/api/v1/caldav/rooms/calendars/1/"
+
+var calDAVStart = time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+
+func newCalDAVServer(t *testing.T) (*httptest.Server, *reservationRepository) {
+	t.Helper()
+
+	reservations := &reservationRepository{data: map[string]reservation.Reservation{
+		"r1": {ID: "r1", RoomID: "1", StartTime: calDAVStart, EndTime: calDAVStart.Add(time.Hour)},
+		"r2": {ID: "r2", RoomID: "1", StartTime: calDAVStart.Add(2 * time.Hour), EndTime: calDAVStart.Add(3 * time.Hour)},
+		"r3": {ID: "r3", RoomID: "1", StartTime: calDAVStart.Add(24 * time.Hour), EndTime: calDAVStart.Add(25 * time.Hour)},
+	}}
+	rooms := &roomRepository{data: map[string]room.Room{"1": {ID: "1", Name: "Kilimanjaro", Building: "HQ"}}}
+
+	h := NewReservationHandler(reservations, WithRoomRepository(rooms), WithAPIKeys("client-key"))
+
+	srv := httptest.NewServer(h.HTTP)
+	t.Cleanup(srv.Close)
+
+	return srv, reservations
+}
+
+// calDAVRequest sends a request as a calendar client would, password being its basic auth password if set.
+func calDAVRequest(t *testing.T, srv *httptest.Server, method, path, password, body string, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if password != "" {
+		req.SetBasicAuth("alice@example.com", password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(data)
+}
+
+func calDAVEvent(uid string, start, end time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//EN",
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:20240801T000000Z",
+		"DTSTART:" + start.UTC().Format("20060102T150405Z"),
+		"DTEND:" + end.UTC().Format("20060102T150405Z"),
+		"SUMMARY:Meeting",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func putCalDAVEvent(t *testing.T, srv *httptest.Server, name string, start, end time.Time, header http.Header) *http.Response {
+	t.Helper()
+
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "text/calendar")
+
+	resp, _ := calDAVRequest(t, srv, http.MethodPut, calDAVRoom+name+".ics", "client-key", calDAVEvent(name, start, end), header)
+	return resp
+}
+
+func TestCalDAVAuth(t *testing.T) {
+	srv, reservations := newCalDAVServer(t)
+
+	for name, tc := range map[string]struct {
+		method   string
+		password string
+		status   int
+	}{
+		"read without credentials":   {method: "PROPFIND", status: http.StatusMultiStatus},
+		"put without credentials":    {method: http.MethodPut, status: http.StatusUnauthorized},
+		"put with unknown password":  {method: http.MethodPut, password: "unknown", status: http.StatusUnauthorized},
+		"delete without credentials": {method: http.MethodDelete, status: http.StatusUnauthorized},
+		"mkcol without credentials":  {method: "MKCOL", status: http.StatusUnauthorized},
+	} {
+		header := http.Header{"Content-Type": {"text/calendar"}, "Depth": {"0"}}
+		body := ""
+		if tc.method == http.MethodPut {
+			body = calDAVEvent("event-1", calDAVStart.Add(4*time.Hour), calDAVStart.Add(5*time.Hour))
+		}
+
+		resp, _ := calDAVRequest(t, srv, tc.method, calDAVRoom+"r1.ics", tc.password, body, header)
+		assert.Equal(t, tc.status, resp.StatusCode, name)
+
+		if tc.status == http.StatusUnauthorized {
+			assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Basic", name)
+		}
+	}
+
+	assert.Nil(t, reservations.data["r1"].CancelledAt, "expected the reservation left as it was")
+	assert.Len(t, reservations.data, 3)
+}
+
+func TestCalDAVRead(t *testing.T) {
+	srv, reservations := newCalDAVServer(t)
+
+	header := func(depth string) http.Header {
+		return http.Header{"Depth": {depth}, "Content-Type": {"application/xml"}}
+	}
+
+	propfind := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/></d:prop></d:propfind>`
+
+	require.NoError(t, reservations.Cancel(context.Background(), "r2", "moved"))
+
+	resp, body := calDAVRequest(t, srv, "PROPFIND", calDAVRoom, "", propfind, header("1"))
+	require.Equal(t, http.StatusMultiStatus, resp.StatusCode, body)
+	assert.Contains(t, body, calDAVRoom+"r1.ics")
+	assert.Contains(t, body, `r1-0`)
+	assert.NotContains(t, body, calDAVRoom+"r2.ics", "expected cancelled reservations left out")
+
+	report := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+	<d:prop><d:getetag/><c:calendar-data/></d:prop>
+	<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT">
+		<c:time-range start="%s" end="%s"/>
+	</c:comp-filter></c:comp-filter></c:filter>
+</c:calendar-query>`, calDAVStart.Add(-30*time.Minute).Format("20060102T150405Z"), calDAVStart.Add(30*time.Minute).Format("20060102T150405Z"))
+
+	resp, body = calDAVRequest(t, srv, "REPORT", calDAVRoom, "", report, header("1"))
+	require.Equal(t, http.StatusMultiStatus, resp.StatusCode, body)
+	assert.Contains(t, body, calDAVRoom+"r1.ics")
+	assert.Contains(t, body, "DTSTART:20240829T130000Z")
+	assert.NotContains(t, body, calDAVRoom+"r3.ics", "expected events outside the time range left out")
+
+	resp, _ = calDAVRequest(t, srv, "PROPFIND", "/api/v1/caldav/rooms/calendars/2/", "", propfind, header("0"))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "expected unknown rooms not to have a calendar")
+}
+
+func TestCalDAVPut(t *testing.T) {
+	srv, reservations := newCalDAVServer(t)
+
+	resp := putCalDAVEvent(t, srv, "event-1", calDAVStart.Add(4*time.Hour), calDAVStart.Add(5*time.Hour), nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, calDAVRoom+"event-1.ics", resp.Header.Get("Location"))
+
+	created := reservations.data["abc"]
+	assert.Equal(t, "event-1", created.ICalUID)
+	assert.Equal(t, "alice@example.com", created.UserID, "expected the basic auth username as user ID")
+	assert.Equal(t, "1", created.RoomID)
+
+	resp = putCalDAVEvent(t, srv, "event-2", calDAVStart.Add(30*time.Minute), calDAVStart.Add(90*time.Minute), nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "expected overlapping events refused")
+
+	resp = putCalDAVEvent(t, srv, "event-3", calDAVStart.Add(6*time.Hour), calDAVStart.Add(7*time.Hour),
+		http.Header{"If-Match": {`"event-3-0"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode, "expected If-Match to require an existing event")
+
+	// rescheduling
+	resp = putCalDAVEvent(t, srv, "r1", calDAVStart.Add(time.Hour), calDAVStart.Add(2*time.Hour), http.Header{"If-Match": {`"r1-0"`}})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, `"r1-1"`, resp.Header.Get("ETag"))
+	assert.Equal(t, calDAVStart.Add(time.Hour), reservations.data["r1"].StartTime)
+	assert.Equal(t, calDAVStart.Add(2*time.Hour), reservations.data["r1"].EndTime)
+
+	resp = putCalDAVEvent(t, srv, "r1", calDAVStart, calDAVStart.Add(time.Hour), http.Header{"If-Match": {`"r1-0"`}})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode, "expected stale ETags refused")
+
+	resp = putCalDAVEvent(t, srv, "r1", calDAVStart.Add(90*time.Minute), calDAVStart.Add(150*time.Minute), nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "expected rescheduling onto another reservation refused")
+	assert.Equal(t, calDAVStart.Add(time.Hour), reservations.data["r1"].StartTime, "expected the reservation kept")
+
+	resp = putCalDAVEvent(t, srv, "r1", calDAVStart, calDAVStart.Add(time.Hour), http.Header{"If-None-Match": {"*"}})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode, "expected If-None-Match to refuse existing events")
+
+	resp = putCalDAVEvent(t, srv, "r1", calDAVStart, calDAVStart.Add(time.Hour), http.Header{"If-Match": {"*"}})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, calDAVStart, reservations.data["r1"].StartTime)
+}
+
+func TestCalDAVDelete(t *testing.T) {
+	srv, reservations := newCalDAVServer(t)
+
+	resp, _ := calDAVRequest(t, srv, http.MethodDelete, calDAVRoom+"r2.ics", "client-key", "", nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.NotNil(t, reservations.data["r2"].CancelledAt)
+	assert.Equal(t, "deleted from calendar", reservations.data["r2"].CancelReason)
+
+	resp, _ = calDAVRequest(t, srv, http.MethodDelete, calDAVRoom+"r2.ics", "client-key", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = calDAVRequest(t, srv, http.MethodDelete, calDAVRoom+"missing.ics", "client-key", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = putCalDAVEvent(t, srv, "r2", calDAVStart.Add(2*time.Hour), calDAVStart.Add(3*time.Hour), http.Header{"If-None-Match": {"*"}})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "expected putting a deleted event to restore it")
+	assert.Nil(t, reservations.data["r2"].CancelledAt)
+}
+
+func TestCalDAVPutCancelled(t *testing.T) {
+	srv, reservations := newCalDAVServer(t)
+
+	require.NoError(t, reservations.Cancel(context.Background(), "r2", "moved"))
+	reservations.data["r4"] = reservation.Reservation{ID: "r4", RoomID: "1", StartTime: calDAVStart.Add(2 * time.Hour), EndTime: calDAVStart.Add(3 * time.Hour)}
+
+	resp := putCalDAVEvent(t, srv, "r2", calDAVStart.Add(30*time.Minute), calDAVStart.Add(90*time.Minute), nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode, "expected events moved onto another reservation refused")
+	assert.NotNil(t, reservations.data["r2"].CancelledAt, "expected the reservation not restored")
+
+	resp = putCalDAVEvent(t, srv, "r2", calDAVStart.Add(3*time.Hour), calDAVStart.Add(4*time.Hour), nil)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "expected events moved away from their taken time restored")
+	assert.Nil(t, reservations.data["r2"].CancelledAt)
+	assert.Equal(t, calDAVStart.Add(3*time.Hour), reservations.data["r2"].StartTime)
+}
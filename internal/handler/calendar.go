@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/ical"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) calendarRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Post("/feeds", h.createCalendarFeed)
+	r.Delete("/feeds/{token}", h.deleteCalendarFeed)
+	r.Get("/{token}.ics", h.feedCalendar)
+
+	return r
+}
+
+// @Summary Room calendar
+// @Description iCalendar feed of the reservations of a room, including cancelled ones
+// @Tags Calendar
+// @Produce text/calendar
+// @Param roomID path string true "Room id"
+// @Success 200
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID}/calendar.ics [get]
+func (h *ReservationHandler) roomCalendar(w http.ResponseWriter, r *http.Request) {
+	h.writeRoomCalendar(w, r, chi.URLParam(r, "roomID"))
+}
+
+// @Summary Create calendar feed
+// @Description Create a secret calendar URL for subscribing without headers. The feed follows the given room, or the reservations of the calling user when no room is given.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string false "User id, required for user feeds"
+// @Param feed body calendar.FeedRequest false "Feed"
+// @Success 201 {object} response.BaseObject{data=calendar.FeedResponse}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /calendar/feeds [post]
+func (h *ReservationHandler) createCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var req calendar.FeedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data := calendar.Feed{
+		Token:     calendar.NewToken(),
+		RoomID:    req.RoomID,
+		CreatedBy: audit.ActorFromContext(r.Context()).ID,
+	}
+
+	if data.RoomID == "" {
+		data.UserID = router.IdentityFromContext(r.Context()).UserID
+		if data.UserID == "" {
+			response.Unauthorized(w)
+			return
+		}
+	}
+
+	if err := h.calendarRepo.CreateFeed(r.Context(), data); err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	data, err := h.calendarRepo.GetFeed(r.Context(), data.Token)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.Status(w, r, http.StatusCreated, calendar.ToFeedResponse(data, feedURL(r, data.Token)))
+}
+
+// @Summary Delete calendar feed
+// @Description Revoke a calendar URL, only its creator or an admin may do so
+// @Tags Calendar
+// @Param token path string true "Feed token"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /calendar/feeds/{token} [delete]
+func (h *ReservationHandler) deleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	token := chi.URLParam(r, "token")
+
+	data, err := h.calendarRepo.GetFeed(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, calendar.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, nil)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if data.CreatedBy != audit.ActorFromContext(r.Context()).ID && !router.IdentityFromContext(r.Context()).HasAPIKey(h.adminAPIKeys...) {
+		response.Forbidden(w)
+		return
+	}
+
+	if err = h.calendarRepo.DeleteFeed(r.Context(), token); err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// @Summary Calendar feed
+// @Description iCalendar feed behind a secret URL
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200
+// @Failure 404
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /calendar/{token}.ics [get]
+func (h *ReservationHandler) feedCalendar(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	data, err := h.calendarRepo.GetFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, calendar.ErrorNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if data.RoomID != "" {
+		h.writeRoomCalendar(w, r, data.RoomID)
+		return
+	}
+
+	reservations, err := h.reservationRepo.ListForUser(r.Context(), data.UserID, reservation.ListOptions{IncludeCancelled: true})
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	writeCalendar(w, r, calendar.New("Reservations of "+data.UserID, reservations))
+}
+
+func (h *ReservationHandler) writeRoomCalendar(w http.ResponseWriter, r *http.Request, roomID string) {
+	logger := log.LoggerFromContext(r.Context())
+
+	reservations, err := h.reservationRepo.List(r.Context(), roomID, reservation.ListOptions{IncludeCancelled: true})
+	if err != nil && !errors.Is(err, reservation.ErrorNotFoundForRoom) {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	name := "Room " + roomID
+	if h.roomRepo != nil {
+		if data, err := h.roomRepo.Get(r.Context(), roomID); err == nil {
+			name = data.Name
+		}
+	}
+
+	writeCalendar(w, r, calendar.New(name, reservations))
+}
+
+func writeCalendar(w http.ResponseWriter, r *http.Request, c ical.Calendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+
+	if err := c.Encode(w); err != nil {
+		log.LoggerFromContext(r.Context()).Err(err).Caller().Send()
+	}
+}
+
+// feedURL is the absolute URL of a feed as seen by the client that created it.
+func feedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + "/api/v1/calendar/" + token + ".ics"
+}
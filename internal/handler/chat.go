@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) chatRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(router.RequireAPIKey(h.adminAPIKeys...))
+
+	r.Post("/", h.createChatChannel)
+	r.Get("/", h.listChatChannels)
+
+	r.Route("/{channelID}", func(r chi.Router) {
+		r.Get("/", h.getChatChannel)
+		r.Delete("/", h.deleteChatChannel)
+	})
+
+	return r
+}
+
+// @Summary Create chat channel
+// @Description Post reservation events to a Slack or Mattermost channel through its incoming webhook URL, optionally narrowed down to event types and to rooms or buildings. The URL is only returned in full here.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param channel body chat.CreateRequest true "Chat channel"
+// @Success 201 {object} response.BaseObject{data=chat.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /chat/channels [post]
+func (h *ReservationHandler) createChatChannel(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var req chat.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data := chat.Channel{
+		Name:       req.Name,
+		URL:        req.URL,
+		RoomIDs:    req.RoomIDs,
+		Buildings:  req.Buildings,
+		EventTypes: req.EventTypes,
+	}
+
+	ID, err := h.chatRepo.Create(r.Context(), data)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	data, err = h.chatRepo.Get(r.Context(), ID)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	res := chat.ToResponse(data)
+	res.URL = data.URL
+
+	w.Header().Set("Location", r.URL.Path+"/"+ID)
+	response.Status(w, r, http.StatusCreated, res)
+}
+
+// @Summary List chat channels
+// @Description List chat channels
+// @Tags Chat
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} response.BaseObject{data=[]chat.Response}
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /chat/channels [get]
+func (h *ReservationHandler) listChatChannels(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	data, err := h.chatRepo.List(r.Context())
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, chat.ToResponseSlice(data))
+}
+
+// @Summary Get chat channel
+// @Description Get chat channel
+// @Tags Chat
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param channelID path string true "Chat channel id"
+// @Success 200 {object} response.BaseObject{data=chat.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /chat/channels/{channelID} [get]
+func (h *ReservationHandler) getChatChannel(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "channelID")
+
+	data, err := h.chatRepo.Get(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, chat.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, chat.ToResponse(data))
+}
+
+// @Summary Delete chat channel
+// @Description Stop posting to a chat channel and delete its message log
+// @Tags Chat
+// @Param X-API-Key header string true "Admin API key"
+// @Param channelID path string true "Chat channel id"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /chat/channels/{channelID} [delete]
+func (h *ReservationHandler) deleteChatChannel(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "channelID")
+
+	err := h.chatRepo.Delete(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, chat.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
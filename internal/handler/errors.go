@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/domain/webhook"
+	"room-reservation/pkg/server/response"
+)
+
+// Codes of the errors of error responses, clients rely on them so they must not change.
+func init() {
+	response.RegisterErrorCode(reservation.ErrorNotFound, "reservation_not_found")
+	response.RegisterErrorCode(reservation.ErrorNotFoundForRoom, "reservations_not_found_for_room")
+	response.RegisterErrorCode(reservation.ErrorOverlaps, "reservation_overlaps")
+	response.RegisterErrorCode(reservation.ErrorCancelled, "reservation_cancelled")
+	response.RegisterErrorCode(reservation.ErrorNotCancelled, "reservation_not_cancelled")
+	response.RegisterErrorCode(reservation.ErrorImported, "reservation_imported")
+	response.RegisterErrorCode(reservation.ErrorClosed, "room_closed")
+	response.RegisterErrorCode(reservation.ErrorInvalidTime, "reservation_invalid_time")
+	response.RegisterErrorCode(room.ErrorNotFound, "room_not_found")
+	response.RegisterErrorCode(schedule.ErrorNotFound, "schedule_not_found")
+	response.RegisterErrorCode(webhook.ErrorNotFound, "webhook_not_found")
+	response.RegisterErrorCode(webhook.ErrorDeliveryNotFound, "delivery_not_found")
+	response.RegisterErrorCode(webhook.ErrorPrivateHost, "webhook_private_host")
+	response.RegisterErrorCode(chat.ErrorNotFound, "channel_not_found")
+	response.RegisterErrorCode(calendar.ErrorNotFound, "feed_not_found")
+}
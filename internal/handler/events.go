@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"room-reservation/internal/stream"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/server/response"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// resumeLimit is how many missed events are replayed to a client resuming a stream.
+const resumeLimit = 1000
+
+// @Summary Stream room events
+// @Description Stream changes to reservations of a room as Server-Sent Events. Send the Last-Event-ID header to resume after the last received event. Requires an API key, which browsers may pass as the api_key query parameter.
+// @Tags Events
+// @Produce text/event-stream
+// @Param roomID path string true "Room id"
+// @Param Last-Event-ID header string false "Id of the last received event"
+// @Param X-API-Key header string false "API key"
+// @Param api_key query string false "API key"
+// @Success 200
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Router /rooms/{roomID}/events [get]
+func (h *ReservationHandler) streamRoomEvents(w http.ResponseWriter, r *http.Request) {
+	h.streamEvents(w, r, chi.URLParam(r, "roomID"))
+}
+
+// @Summary Stream events
+// @Description Stream changes to reservations of every room as Server-Sent Events. Send the Last-Event-ID header to resume after the last received event. Requires an API key, which browsers may pass as the api_key query parameter.
+// @Tags Events
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "Id of the last received event"
+// @Param X-API-Key header string false "API key"
+// @Param api_key query string false "API key"
+// @Success 200
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Router /events [get]
+func (h *ReservationHandler) streamAllEvents(w http.ResponseWriter, r *http.Request) {
+	h.streamEvents(w, r, "")
+}
+
+func (h *ReservationHandler) streamEvents(w http.ResponseWriter, r *http.Request, roomID string) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var lastEventID int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		ID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, v)
+			return
+		}
+
+		lastEventID = ID
+	}
+
+	// subscribe before catching up so that nothing committed in between is missed
+	sub := h.broker.Subscribe(roomID)
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	replayed := map[int64]bool{}
+
+	if lastEventID > 0 {
+		missed, err := h.eventRepo.Since(r.Context(), lastEventID, roomID, resumeLimit)
+		if err != nil {
+			logger.Err(err).Caller().Msg("error resuming event stream")
+			return
+		}
+
+		for _, e := range missed {
+			if err := stream.WriteEvent(w, e); err != nil {
+				return
+			}
+
+			replayed[e.ID] = true
+		}
+	}
+
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			err = stream.WriteHeartbeat(w)
+		case e, ok := <-sub.C:
+			if !ok {
+				// too slow to keep up, the client resumes from its last event when reconnecting
+				return
+			}
+
+			if replayed[e.ID] {
+				continue
+			}
+
+			err = stream.WriteEvent(w, e)
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/stream"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next event or comment of a Server-Sent Events stream.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err, "could not read event stream")
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	events := &eventRepository{
+		events: []event.Event{
+			{ID: 1, Type: event.TypeReservationCreated, ReservationID: "abc", RoomID: "1", Data: []byte(`{}`)},
+			{ID: 2, Type: event.TypeReservationUpdated, ReservationID: "abc", RoomID: "1", Data: []byte(`{}`)},
+			{ID: 3, Type: event.TypeReservationCancelled, ReservationID: "abc", RoomID: "1", Data: []byte(`{}`)},
+		},
+	}
+	broker := stream.NewBroker()
+
+	h := NewReservationHandler(&reservationRepository{data: map[string]reservation.Reservation{}},
+		WithEventStream(events, broker), WithAPIKeys("client-key"))
+
+	srv := httptest.NewServer(h.HTTP)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/rooms/1/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	req.Header.Set("X-API-Key", "client-key")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+
+	require.True(t, strings.HasPrefix(readEvent(t, r), "id: 2\nevent: ReservationUpdated\n"), "expected missed events to be replayed")
+	require.True(t, strings.HasPrefix(readEvent(t, r), "id: 3\n"))
+
+	// the replayed event is published once its transaction commits, it is not sent twice
+	require.NoError(t, broker.Publish(ctx, events.events[2]))
+	require.NoError(t, broker.Publish(ctx, event.Event{ID: 4, Type: event.TypeReservationRestored, ReservationID: "abc", RoomID: "1", Data: []byte(`{}`)}))
+	require.NoError(t, broker.Publish(ctx, event.Event{ID: 5, Type: event.TypeReservationCreated, ReservationID: "def", RoomID: "2", Data: []byte(`{}`)}))
+
+	require.True(t, strings.HasPrefix(readEvent(t, r), "id: 4\nevent: ReservationRestored\n"), "expected new events of the room to be streamed")
+}
+
+func TestStreamEventsHeartbeat(t *testing.T) {
+	h := NewReservationHandler(&reservationRepository{data: map[string]reservation.Reservation{}},
+		WithEventStream(&eventRepository{}, stream.NewBroker()), WithAPIKeys("client-key"))
+	h.heartbeatInterval = 10 * time.Millisecond
+
+	srv := httptest.NewServer(h.HTTP)
+	defer srv.Close()
+
+	// browsers can't set headers on event streams
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/events?api_key=client-key", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, ": heartbeat", readEvent(t, bufio.NewReader(resp.Body)), "expected a heartbeat on idle streams")
+}
+
+func TestStreamEventsInvalidLastEventID(t *testing.T) {
+	h := NewReservationHandler(&reservationRepository{data: map[string]reservation.Reservation{}},
+		WithEventStream(&eventRepository{}, stream.NewBroker()), WithAPIKeys("client-key"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	req.Header.Set("X-API-Key", "client-key")
+	rec := httptest.NewRecorder()
+
+	h.HTTP.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStreamEventsAPIKey(t *testing.T) {
+	h := NewReservationHandler(&reservationRepository{data: map[string]reservation.Reservation{}},
+		WithEventStream(&eventRepository{}, stream.NewBroker()), WithAPIKeys("client-key"))
+
+	tests := map[string]int{
+		"/api/v1/events":               http.StatusUnauthorized,
+		"/api/v1/rooms/1/events":       http.StatusUnauthorized,
+		"/api/v1/events?api_key=other": http.StatusForbidden,
+	}
+
+	for path, code := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", "text/event-stream")
+		rec := httptest.NewRecorder()
+
+		h.HTTP.ServeHTTP(rec, req)
+
+		require.Equal(t, code, rec.Code, path)
+	}
+}
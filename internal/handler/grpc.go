@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	reservationv1 "room-reservation/api/reservation/v1"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// reservationServer serves the reservation API over gRPC on top of the same repositories as the HTTP routes.
+type reservationServer struct {
+	reservationv1.UnimplementedReservationServiceServer
+
+	h *ReservationHandler
+}
+
+func (h *ReservationHandler) grpcServer() *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(identifyUnary),
+		grpc.ChainStreamInterceptor(identifyStream),
+	)
+
+	reservationv1.RegisterReservationServiceServer(s, &reservationServer{h: h})
+
+	return s
+}
+
+// identify stores the caller identity and the audit actor in the context of a call,
+// reading them from the same metadata keys as the HTTP headers.
+func identify(ctx context.Context, method string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+
+		return ""
+	}
+
+	id := router.Identity{
+		APIKey: first(strings.ToLower(router.APIKeyHeader)),
+		UserID: first(strings.ToLower(router.UserIDHeader)),
+	}
+
+	actor := audit.Actor{
+		ID:        actorID(id),
+		RequestID: first("x-request-id"),
+	}
+
+	c := log.Logger().With().Str("request_id", actor.RequestID).Str("method", method)
+	if id.UserID != "" {
+		c = c.Str("user", id.UserID)
+	}
+
+	ctx = log.WithLogger(ctx, c.Logger())
+
+	return audit.WithActor(router.WithIdentity(ctx, id), actor)
+}
+
+func identifyUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(identify(ctx, info.FullMethod), req)
+}
+
+type identifiedStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
+
+func identifyStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &identifiedStream{ServerStream: ss, ctx: identify(ss.Context(), info.FullMethod)})
+}
+
+// grpcError maps domain errors to status codes. Unexpected errors are logged and not shown to the caller.
+func grpcError(ctx context.Context, err error) error {
+	var code codes.Code
+
+	switch {
+	case errors.Is(err, reservation.ErrorNotFound):
+		code = codes.NotFound
+	case errors.Is(err, reservation.ErrorInvalidTime):
+		code = codes.InvalidArgument
+	case errors.Is(err, reservation.ErrorOverlaps), errors.Is(err, reservation.ErrorImported):
+		code = codes.AlreadyExists
+	case errors.Is(err, reservation.ErrorCancelled), errors.Is(err, reservation.ErrorNotCancelled),
+		errors.Is(err, reservation.ErrorClosed):
+		code = codes.FailedPrecondition
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		log.LoggerFromContext(ctx).Err(err).Caller(1).Send()
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return status.Error(code, err.Error())
+}
+
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func toProtoReservation(data reservation.Reservation) *reservationv1.Reservation {
+	res := &reservationv1.Reservation{
+		Id:           data.ID,
+		RoomId:       data.RoomID,
+		UserId:       data.UserID,
+		StartTime:    toTimestamp(data.StartTime),
+		EndTime:      toTimestamp(data.EndTime),
+		CancelReason: data.CancelReason,
+		CancelledBy:  data.CancelledBy,
+		Sequence:     int32(data.Sequence),
+		UpdatedAt:    toTimestamp(data.UpdatedAt),
+	}
+
+	if data.Cancelled() {
+		res.CancelledAt = timestamppb.New(*data.CancelledAt)
+	}
+
+	return res
+}
+
+func toProtoEvent(e event.Event) (*reservationv1.Event, error) {
+	var data reservation.Reservation
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, err
+	}
+
+	return &reservationv1.Event{
+		Id:            e.ID,
+		Type:          e.Type,
+		ReservationId: e.ReservationID,
+		RoomId:        e.RoomID,
+		Actor:         e.Actor,
+		Reservation:   toProtoReservation(data),
+		OccurredAt:    toTimestamp(e.OccurredAt),
+	}, nil
+}
+
+func (s *reservationServer) Create(ctx context.Context, req *reservationv1.CreateRequest) (*reservationv1.Reservation, error) {
+	r := reservation.Request{
+		RoomID:    req.GetRoomId(),
+		StartTime: reservation.DateTime{Time: toTime(req.GetStartTime())},
+		EndTime:   reservation.DateTime{Time: toTime(req.GetEndTime())},
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data := reservation.Reservation{
+		RoomID:    r.RoomID,
+		UserID:    router.IdentityFromContext(ctx).UserID,
+		StartTime: r.StartTime.Time,
+		EndTime:   r.EndTime.Time,
+	}
+
+	ID, err := s.h.reservationRepo.Create(ctx, data)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return s.Get(ctx, &reservationv1.GetRequest{Id: ID})
+}
+
+func (s *reservationServer) Get(ctx context.Context, req *reservationv1.GetRequest) (*reservationv1.Reservation, error) {
+	data, err := s.h.reservationRepo.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return toProtoReservation(data), nil
+}
+
+func (s *reservationServer) List(ctx context.Context, req *reservationv1.ListRequest) (*reservationv1.ListResponse, error) {
+	if req.GetRoomId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+
+	opts := reservation.ListOptions{IncludeCancelled: req.GetIncludeCancelled()}
+
+	data, err := s.h.reservationRepo.List(ctx, req.GetRoomId(), opts)
+	if err != nil && !errors.Is(err, reservation.ErrorNotFoundForRoom) {
+		return nil, grpcError(ctx, err)
+	}
+
+	res := &reservationv1.ListResponse{
+		Reservations: make([]*reservationv1.Reservation, 0, len(data)),
+	}
+
+	for _, r := range data {
+		res.Reservations = append(res.Reservations, toProtoReservation(r))
+	}
+
+	return res, nil
+}
+
+func (s *reservationServer) Update(ctx context.Context, req *reservationv1.UpdateRequest) (*reservationv1.Reservation, error) {
+	r := reservation.UpdateRequest{
+		RoomID:    req.GetRoomId(),
+		StartTime: reservation.DateTime{Time: toTime(req.GetStartTime())},
+		EndTime:   reservation.DateTime{Time: toTime(req.GetEndTime())},
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data := reservation.Reservation{
+		RoomID:    r.RoomID,
+		StartTime: r.StartTime.Time,
+		EndTime:   r.EndTime.Time,
+	}
+
+	if err := s.h.reservationRepo.Update(ctx, req.GetId(), data); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return s.Get(ctx, &reservationv1.GetRequest{Id: req.GetId()})
+}
+
+func (s *reservationServer) Delete(ctx context.Context, req *reservationv1.DeleteRequest) (*emptypb.Empty, error) {
+	if err := s.h.reservationRepo.Cancel(ctx, req.GetId(), req.GetReason()); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// WatchRoom replays the events after AfterEventId before streaming new ones. Streams of callers
+// too slow to keep up are ended with UNAVAILABLE, they resume from the last event they received.
+func (s *reservationServer) WatchRoom(req *reservationv1.WatchRoomRequest, stream grpc.ServerStreamingServer[reservationv1.Event]) error {
+	ctx := stream.Context()
+
+	if s.h.broker == nil {
+		return status.Error(codes.Unimplemented, "event streaming is not enabled")
+	}
+
+	if req.GetRoomId() == "" {
+		return status.Error(codes.InvalidArgument, "room_id is required")
+	}
+
+	// subscribe before catching up so that nothing committed in between is missed
+	sub := s.h.broker.Subscribe(req.GetRoomId())
+	defer sub.Close()
+
+	send := func(e event.Event) error {
+		res, err := toProtoEvent(e)
+		if err != nil {
+			return grpcError(ctx, err)
+		}
+
+		return stream.Send(res)
+	}
+
+	replayed := map[int64]bool{}
+
+	if req.GetAfterEventId() > 0 {
+		missed, err := s.h.eventRepo.Since(ctx, req.GetAfterEventId(), req.GetRoomId(), resumeLimit)
+		if err != nil {
+			return grpcError(ctx, err)
+		}
+
+		for _, e := range missed {
+			if err := send(e); err != nil {
+				return err
+			}
+
+			replayed[e.ID] = true
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case e, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "too slow to keep up, resume from the last received event")
+			}
+
+			if replayed[e.ID] {
+				continue
+			}
+
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
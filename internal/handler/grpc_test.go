@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	reservationv1 "room-reservation/api/reservation/v1"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/stream"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type reservationRepository struct {
+	reservation.Repository
+
+	data  map[string]reservation.Reservation
+	actor audit.Actor
+}
+
+// overlaps reports whether data overlaps another active reservation of its room.
+func (r *reservationRepository) overlaps(data reservation.Reservation) bool {
+	for _, res := range r.data {
+		if res.ID != data.ID && res.RoomID == data.RoomID && !res.Cancelled() && res.Overlaps(data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *reservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	if r.overlaps(data) {
+		return "", reservation.ErrorOverlaps
+	}
+
+	data.ID = "abc"
+	r.data[data.ID] = data
+	r.actor = audit.ActorFromContext(ctx)
+
+	return data.ID, nil
+}
+
+func (r *reservationRepository) Get(ctx context.Context, ID string) (reservation.Reservation, error) {
+	data, ok := r.data[ID]
+	if !ok {
+		return reservation.Reservation{}, reservation.ErrorNotFound
+	}
+
+	return data, nil
+}
+
+func (r *reservationRepository) List(ctx context.Context, roomID string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	var res []reservation.Reservation
+	for _, data := range r.data {
+		if data.RoomID == roomID && (opts.IncludeCancelled || !data.Cancelled()) {
+			res = append(res, data)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, reservation.ErrorNotFoundForRoom
+	}
+
+	return res, nil
+}
+
+func (r *reservationRepository) Cancel(ctx context.Context, ID string, reason string) error {
+	data, ok := r.data[ID]
+	if !ok {
+		return reservation.ErrorNotFound
+	}
+
+	if data.Cancelled() {
+		return reservation.ErrorCancelled
+	}
+
+	now := time.Now()
+	data.CancelledAt = &now
+	data.CancelReason = reason
+	data.Sequence++
+	r.data[ID] = data
+
+	return nil
+}
+
+func (r *reservationRepository) GetByICalUID(ctx context.Context, uid string) (reservation.Reservation, error) {
+	for _, data := range r.data {
+		if data.ICalUID == uid {
+			return data, nil
+		}
+	}
+
+	return reservation.Reservation{}, reservation.ErrorNotFound
+}
+
+func (r *reservationRepository) Update(ctx context.Context, ID string, data reservation.Reservation) error {
+	after, ok := r.data[ID]
+	if !ok {
+		return reservation.ErrorNotFound
+	}
+
+	if after.Cancelled() {
+		return reservation.ErrorCancelled
+	}
+
+	if !data.StartTime.IsZero() {
+		after.StartTime = data.StartTime
+	}
+	if !data.EndTime.IsZero() {
+		after.EndTime = data.EndTime
+	}
+
+	if !after.StartTime.Before(after.EndTime) {
+		return reservation.ErrorInvalidTime
+	}
+
+	if r.overlaps(after) {
+		return reservation.ErrorOverlaps
+	}
+
+	after.Sequence++
+	r.data[ID] = after
+
+	return nil
+}
+
+func (r *reservationRepository) Restore(ctx context.Context, ID string) error {
+	return r.RestoreWith(ctx, ID, reservation.Reservation{})
+}
+
+func (r *reservationRepository) RestoreWith(ctx context.Context, ID string, update reservation.Reservation) error {
+	before, ok := r.data[ID]
+	if !ok {
+		return reservation.ErrorNotFound
+	}
+
+	if !before.Cancelled() {
+		return reservation.ErrorNotCancelled
+	}
+
+	data := before.Merge(update)
+	if r.overlaps(data) {
+		return reservation.ErrorOverlaps
+	}
+
+	data.CancelledAt = nil
+	data.CancelReason = ""
+	data.Sequence++
+	r.data[ID] = data
+
+	return nil
+}
+
+type eventRepository struct {
+	event.Repository
+
+	events []event.Event
+}
+
+func (r *eventRepository) Since(ctx context.Context, afterID int64, roomID string, limit int) ([]event.Event, error) {
+	var res []event.Event
+	for _, e := range r.events {
+		if e.ID > afterID && e.RoomID == roomID {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+func newGRPCClient(t *testing.T, h *ReservationHandler) reservationv1.ReservationServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+
+	go h.GRPC.Serve(lis)
+	t.Cleanup(h.GRPC.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return reservationv1.NewReservationServiceClient(conn)
+}
+
+func TestGRPCReservationService(t *testing.T) {
+	repo := &reservationRepository{data: map[string]reservation.Reservation{}}
+	client := newGRPCClient(t, NewReservationHandler(repo))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "alice", "x-request-id", "req-1")
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	req := &reservationv1.CreateRequest{
+		RoomId:    "1",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+	}
+
+	created, err := client.Create(ctx, req)
+	require.NoError(t, err, "could not create reservation")
+	assert.Equal(t, "abc", created.GetId())
+	assert.Equal(t, "alice", created.GetUserId(), "expected user to be taken from metadata")
+	assert.True(t, start.Equal(created.GetStartTime().AsTime()))
+	assert.Equal(t, "alice", repo.actor.ID)
+	assert.Equal(t, "req-1", repo.actor.RequestID)
+
+	_, err = client.Create(ctx, req)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err), "expected overlapping reservation to be rejected")
+
+	_, err = client.Create(ctx, &reservationv1.CreateRequest{RoomId: "1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := client.List(ctx, &reservationv1.ListRequest{RoomId: "1"})
+	require.NoError(t, err)
+	assert.Len(t, list.GetReservations(), 1)
+
+	list, err = client.List(ctx, &reservationv1.ListRequest{RoomId: "2"})
+	require.NoError(t, err, "expected rooms without reservations to be listed empty")
+	assert.Empty(t, list.GetReservations())
+
+	_, err = client.Get(ctx, &reservationv1.GetRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Update(ctx, &reservationv1.UpdateRequest{Id: "abc", EndTime: timestamppb.New(start.Add(-time.Hour))})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected an end time before the kept start time to be rejected")
+
+	_, err = client.Delete(ctx, &reservationv1.DeleteRequest{Id: "abc", Reason: "moved"})
+	require.NoError(t, err, "could not cancel reservation")
+
+	cancelled, err := client.Get(ctx, &reservationv1.GetRequest{Id: "abc"})
+	require.NoError(t, err)
+	assert.NotNil(t, cancelled.GetCancelledAt())
+	assert.Equal(t, "moved", cancelled.GetCancelReason())
+
+	_, err = client.Delete(ctx, &reservationv1.DeleteRequest{Id: "abc"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "expected cancelled reservation not to be cancelled again")
+}
+
+func TestGRPCWatchRoom(t *testing.T) {
+	data, err := json.Marshal(reservation.Reservation{ID: "abc", RoomID: "1"})
+	require.NoError(t, err)
+
+	broker := stream.NewBroker()
+	events := &eventRepository{events: []event.Event{
+		{ID: 1, Type: event.TypeReservationCreated, ReservationID: "abc", RoomID: "1", Data: data},
+		{ID: 2, Type: event.TypeReservationUpdated, ReservationID: "abc", RoomID: "1", Data: data},
+	}}
+
+	h := NewReservationHandler(&reservationRepository{}, WithEventStream(events, broker))
+	client := newGRPCClient(t, h)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watch, err := client.WatchRoom(ctx, &reservationv1.WatchRoomRequest{RoomId: "1", AfterEventId: 1})
+	require.NoError(t, err)
+
+	e, err := watch.Recv()
+	require.NoError(t, err, "expected missed event to be replayed")
+	assert.Equal(t, int64(2), e.GetId())
+	assert.Equal(t, "abc", e.GetReservation().GetId())
+
+	// the stream is subscribed before replaying, so events published now are streamed
+	broker.Publish(ctx, event.Event{ID: 2, RoomID: "1", Data: data})
+	broker.Publish(ctx, event.Event{ID: 3, Type: event.TypeReservationCancelled, ReservationID: "abc", RoomID: "1", Data: data})
+
+	e, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), e.GetId(), "expected replayed events not to be sent again")
+	assert.Equal(t, event.TypeReservationCancelled, e.GetType())
+}
+
+func TestGRPCWatchRoomDisabled(t *testing.T) {
+	client := newGRPCClient(t, NewReservationHandler(&reservationRepository{}))
+
+	watch, err := client.WatchRoom(context.Background(), &reservationv1.WatchRoomRequest{RoomId: "1"})
+	require.NoError(t, err)
+
+	_, err = watch.Recv()
+	assert.Equal(t, codes.Unimplemented, status.Code(err), "expected streaming to require an event stream")
+}
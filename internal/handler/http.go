@@ -3,22 +3,65 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/notification"
 	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/domain/webhook"
+	"room-reservation/internal/graph"
+	"room-reservation/internal/stream"
+	"room-reservation/pkg/health"
 	"room-reservation/pkg/log"
 	"room-reservation/pkg/router"
 	"room-reservation/pkg/server/response"
+	"slices"
+	"strconv"
+	"time"
 
 	_ "room-reservation/docs"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
 )
 
 type ReservationHandler struct {
 	reservationRepo reservation.Repository
 
+	auditRepo        audit.Repository
+	webhookRepo      webhook.Repository
+	eventRepo        event.Repository
+	roomRepo         room.Repository
+	calendarRepo     calendar.Repository
+	notificationRepo notification.Repository
+	chatRepo         chat.Repository
+	scheduleRepo     schedule.Repository
+
+	broker            *stream.Broker
+	heartbeatInterval time.Duration
+
+	rateLimitStore router.LimiterStore
+	adminAPIKeys   []string
+	apiKeys        []string
+	allowedOrigins []string
+	trustedProxies []string
+	upgrader       websocket.Upgrader
+
+	metrics *prometheus.Registry
+	health  *health.Checker
+
 	HTTP *chi.Mux
+	// GRPC serves the reservation API over gRPC, see api/reservation/v1.
+	GRPC *grpc.Server
 }
 
 // @title Room reservation system
@@ -27,29 +70,116 @@ type ReservationHandler struct {
 // @host localhost:8080
 // @BasePath /api/v1
 // @query.collection.format multi
-func NewReservationHandler(repo reservation.Repository) *ReservationHandler {
-	h := &ReservationHandler{reservationRepo: repo}
+func NewReservationHandler(repo reservation.Repository, opts ...Option) *ReservationHandler {
+	h := &ReservationHandler{
+		reservationRepo:   repo,
+		heartbeatInterval: 15 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.rateLimitStore == nil {
+		h.rateLimitStore = router.NewMemoryStore()
+	}
+
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     router.CheckOrigin(h.allowedOrigins...),
+	}
+
+	h.HTTP = router.New(h.trustedProxies...)
+
+	if h.metrics != nil {
+		h.HTTP.Use(router.Metrics(h.metrics))
+		h.HTTP.Handle("/metrics", promhttp.HandlerFor(h.metrics, promhttp.HandlerOpts{}))
+	}
+
+	h.HTTP.Use(router.Authenticate(h.clientAPIKeys()...))
+	h.HTTP.Use(router.RateLimit(h.rateLimitStore, rateLimits))
 
-	h.HTTP = router.New()
+	h.HTTP.Use(withActor)
+
+	if h.health != nil {
+		h.HTTP.Get("/healthz", h.health.ServeLive)
+		h.HTTP.Get("/readyz", h.health.ServeReady)
+	}
 
 	h.HTTP.Get("/swagger/*", httpSwagger.WrapHandler)
 
+	if h.roomRepo != nil {
+		h.HTTP.Handle("/.well-known/caldav", h.calDAVHandler())
+		h.HTTP.Handle("/graphql", graph.NewHandler(h.reservationRepo, h.roomRepo, h.scheduleRepo, h.broker,
+			graph.WithAllowedOrigins(h.allowedOrigins...)))
+	}
+
 	h.HTTP.Route("/api/v1", func(r chi.Router) {
 		r.Mount("/reservations", h.routes())
+		r.Mount("/rooms", h.roomRoutes())
+
+		if h.broker != nil {
+			r.With(router.RequireAPIKey(h.clientAPIKeys()...)).Get("/events", h.streamAllEvents)
+			r.With(router.RequireAPIKey(h.clientAPIKeys()...)).Get("/live", h.liveBoard)
+		}
+
+		if h.calendarRepo != nil {
+			r.Mount("/calendar", h.calendarRoutes())
+		}
+
+		if h.roomRepo != nil {
+			r.Handle("/caldav/*", h.calDAVHandler())
+		}
+
+		if h.notificationRepo != nil {
+			r.Mount("/users", h.userRoutes())
+		}
+
+		if h.auditRepo != nil {
+			r.Mount("/admin", h.adminRoutes())
+		}
+
+		if h.webhookRepo != nil {
+			r.Mount("/webhooks", h.webhookRoutes())
+		}
+
+		if h.chatRepo != nil {
+			r.Mount("/chat/channels", h.chatRoutes())
+		}
+
+		if h.scheduleRepo != nil {
+			r.Mount("/buildings", h.buildingRoutes())
+		}
 	})
 
+	h.GRPC = h.grpcServer()
+
 	return h
 }
 
+// clientAPIKeys are the API keys clients authenticate with, admin keys included.
+func (h *ReservationHandler) clientAPIKeys() []string {
+	return append(slices.Clip(h.apiKeys), h.adminAPIKeys...)
+}
+
 func (h *ReservationHandler) routes() *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Post("/", h.createReservation)
+	r.With(router.RequireAPIKey(h.adminAPIKeys...)).Post("/import", h.importReservations)
 
 	r.Route("/{id}", func(r chi.Router) {
+		r.Use(withReservationLogger)
+
 		r.Delete("/", h.deleteReservation)
 		r.Patch("/", h.updateReservation)
 		r.Get("/", h.getReservation)
+		r.Post("/restore", h.restoreReservation)
+
+		if h.auditRepo != nil {
+			r.Get("/history", h.reservationHistory)
+		}
 	})
 
 	r.Get("/room/{roomID}", h.listRoomReservations)
@@ -57,14 +187,25 @@ func (h *ReservationHandler) routes() *chi.Mux {
 	return r
 }
 
+// withReservationLogger adds the reservation ID of the route to the request logger.
+func withReservationLogger(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		logger := log.LoggerFromContext(r.Context()).With().Str("reservation_id", chi.URLParam(r, "id")).Logger()
+
+		next.ServeHTTP(w, r.WithContext(log.WithLogger(r.Context(), logger)))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 // @Summary Create new reservation
-// @Description Create new reservation
+// @Description Create new reservation, within the opening hours of the room
 // @Tags Reservations
 // @Accept json
 // @Param reservation body reservation.Request true "Reservation object to be added"
 // @Success 201
 // @Failure 409 "Overlapping reservation"
-// @Failure 400 {object} response.BadRequestResponse
+// @Failure 400 {object} response.BadRequestResponse "Invalid reservation or room closed at that time"
 // @Failure 500 {object} response.InternalServerErrorResponse
 // @Router /reservations [post]
 func (h *ReservationHandler) createReservation(w http.ResponseWriter, r *http.Request) {
@@ -85,6 +226,7 @@ func (h *ReservationHandler) createReservation(w http.ResponseWriter, r *http.Re
 
 	data := reservation.Reservation{
 		RoomID:    req.RoomID,
+		UserID:    router.IdentityFromContext(r.Context()).UserID,
 		StartTime: req.StartTime.Time,
 		EndTime:   req.EndTime.Time,
 	}
@@ -97,6 +239,12 @@ func (h *ReservationHandler) createReservation(w http.ResponseWriter, r *http.Re
 			return
 		}
 
+		if errors.Is(err, reservation.ErrorClosed) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, req)
+			return
+		}
+
 		logger.Err(err).Caller().Send()
 		response.InternalServerError(w, r, err)
 		return
@@ -111,7 +259,8 @@ func (h *ReservationHandler) createReservation(w http.ResponseWriter, r *http.Re
 // @Accept json
 // @Produce json
 // @Param roomID path string true "Room id"
-// @Success 200 {object} response.BaseObject
+// @Param include_cancelled query bool false "Include cancelled reservations"
+// @Success 200 {object} response.BaseObject{data=[]reservation.Response}
 // @Success 204
 // @Failure 500 {object} response.InternalServerErrorResponse
 // @Router /reservations/room/{roomID} [get]
@@ -120,7 +269,19 @@ func (h *ReservationHandler) listRoomReservations(w http.ResponseWriter, r *http
 
 	roomID := chi.URLParam(r, "roomID")
 
-	data, err := h.reservationRepo.List(r.Context(), roomID)
+	opts := reservation.ListOptions{}
+	if v := r.URL.Query().Get("include_cancelled"); v != "" {
+		includeCancelled, err := strconv.ParseBool(v)
+		if err != nil {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, v)
+			return
+		}
+
+		opts.IncludeCancelled = includeCancelled
+	}
+
+	data, err := h.reservationRepo.List(r.Context(), roomID, opts)
 	if err != nil {
 		if errors.Is(err, reservation.ErrorNotFoundForRoom) {
 			logger.Err(err).Caller().Send()
@@ -141,7 +302,7 @@ func (h *ReservationHandler) listRoomReservations(w http.ResponseWriter, r *http
 // @Tags Reservations
 // @Accept json
 // @Param id path string true "Reservation id"
-// @Success 200
+// @Success 200 {object} response.BaseObject{data=reservation.Response}
 // @Failure 400 {object} response.BadRequestResponse
 // @Failure 500 {object} response.InternalServerErrorResponse
 // @Router /reservations/{id} [get]
@@ -163,16 +324,21 @@ func (h *ReservationHandler) getReservation(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	response.OK(w, r, data)
+	response.OK(w, r, reservation.ToResponse(data))
 }
 
-// @Summary Delete reservation
-// @Description Delete reservation
+// @Summary Cancel reservation
+// @Description Cancel reservation, freeing its time slot. Cancelled reservations are kept until the retention period ends. Users may only cancel their own reservations, callers with an API key any reservation.
 // @Tags Reservations
 // @Accept json
+// @Param X-User-ID header string false "User id"
+// @Param X-API-Key header string false "API key"
 // @Param id path string true "Reservation id"
+// @Param body body reservation.CancelRequest false "Cancellation reason"
 // @Success 204
 // @Failure 400 {object} response.BadRequestResponse
+// @Failure 403 "Reservation of another user"
+// @Failure 409 "Reservation is already cancelled"
 // @Failure 500 {object} response.InternalServerErrorResponse
 // @Router /reservations/{id} [delete]
 func (h *ReservationHandler) deleteReservation(w http.ResponseWriter, r *http.Request) {
@@ -180,7 +346,32 @@ func (h *ReservationHandler) deleteReservation(w http.ResponseWriter, r *http.Re
 
 	ID := chi.URLParam(r, "id")
 
-	err := h.reservationRepo.Delete(r.Context(), ID)
+	var req reservation.CancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data, err := h.reservationRepo.Get(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, reservation.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	if !router.IdentityFromContext(r.Context()).Owns(data.UserID) {
+		response.Forbidden(w)
+		return
+	}
+
+	err = h.reservationRepo.Cancel(r.Context(), ID, req.Reason)
 	if err != nil {
 		if errors.Is(err, reservation.ErrorNotFound) {
 			logger.Err(err).Caller().Send()
@@ -188,6 +379,48 @@ func (h *ReservationHandler) deleteReservation(w http.ResponseWriter, r *http.Re
 			return
 		}
 
+		if errors.Is(err, reservation.ErrorCancelled) {
+			logger.Err(err).Caller().Send()
+			response.Conflict(w)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// @Summary Restore reservation
+// @Description Restore a cancelled reservation if its time slot is still free
+// @Tags Reservations
+// @Param id path string true "Reservation id"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 409 "Overlapping reservation"
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /reservations/{id}/restore [post]
+func (h *ReservationHandler) restoreReservation(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "id")
+
+	err := h.reservationRepo.Restore(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, reservation.ErrorNotFound) || errors.Is(err, reservation.ErrorNotCancelled) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		if errors.Is(err, reservation.ErrorOverlaps) {
+			logger.Err(err).Caller().Send()
+			response.Conflict(w)
+			return
+		}
+
 		logger.Err(err).Caller().Send()
 		response.InternalServerError(w, r, err)
 		return
@@ -197,7 +430,7 @@ func (h *ReservationHandler) deleteReservation(w http.ResponseWriter, r *http.Re
 }
 
 // @Summary Update reservation
-// @Description Update reservation
+// @Description Update reservation, within the opening hours of the room
 // @Tags Reservations
 // @Accept json
 // @Param id path string true "Reservation id"
@@ -232,12 +465,18 @@ func (h *ReservationHandler) updateReservation(w http.ResponseWriter, r *http.Re
 
 	err := h.reservationRepo.Update(r.Context(), ID, data)
 	if err != nil {
-		if errors.Is(err, reservation.ErrorNotFound) {
+		if errors.Is(err, reservation.ErrorNotFound) || errors.Is(err, reservation.ErrorCancelled) {
 			logger.Err(err).Caller().Send()
 			response.BadRequest(w, r, err, ID)
 			return
 		}
 
+		if errors.Is(err, reservation.ErrorClosed) || errors.Is(err, reservation.ErrorInvalidTime) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, req)
+			return
+		}
+
 		logger.Err(err).Caller().Send()
 		response.InternalServerError(w, r, err)
 		return
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"room-reservation/internal/importer"
+	"room-reservation/pkg/ical"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/server/response"
+	"strings"
+	"time"
+)
+
+// maxImportSize limits the size of imported calendars.
+const maxImportSize = 10 << 20
+
+// @Summary Import reservations
+// @Description Create reservations from the events of an iCalendar file, requires an admin API key. Recurring events are expanded and events are mapped to rooms by their location. Every reservation goes through the usual overlap checks, nothing is created on a dry run. Events imported before are skipped.
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param import body importer.Request true "Calendar and room mapping"
+// @Success 200 {object} response.BaseObject{data=importer.Report}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /reservations/import [post]
+func (h *ReservationHandler) importReservations(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var req importer.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportSize)).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	report, err := importer.New(h.reservationRepo).Import(r.Context(), strings.NewReader(req.Calendar), req.Options(time.Now()))
+	if err != nil {
+		if errors.Is(err, ical.ErrorInvalid) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, nil)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, report)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import "room-reservation/pkg/router"
+
+// rateLimits are the per client request limits of the API.
+var rateLimits = router.RateLimits{
+	Default: router.PerMinute(300),
+	Routes: map[string]router.Limit{
+		"POST /api/v1/reservations":              router.PerMinute(30),
+		"PATCH /api/v1/reservations/{id}":        router.PerMinute(60),
+		"DELETE /api/v1/reservations/{id}":       router.PerMinute(60),
+		"POST /api/v1/reservations/{id}/restore": router.PerMinute(60),
+		"POST /api/v1/reservations/import":       router.PerMinute(10),
+		// queries are batched, but mutations book rooms as well
+		"POST /graphql": router.PerMinute(60),
+	},
+}
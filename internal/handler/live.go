@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	liveWriteWait  = 10 * time.Second
+	livePongWait   = 60 * time.Second
+	livePingPeriod = livePongWait * 9 / 10
+	liveMaxMessage = 64 << 10
+	liveMaxRooms   = 100
+)
+
+// liveRequest is sent by clients to choose the rooms they follow, replacing any previous choice.
+type liveRequest struct {
+	Type      string   `json:"type"`
+	Rooms     []string `json:"rooms"`
+	Buildings []string `json:"buildings"`
+}
+
+const (
+	liveSubscribe = "subscribe"
+	liveSnapshot  = "snapshot"
+	liveEvent     = "event"
+	liveError     = "error"
+)
+
+type liveMessage struct {
+	Type    string       `json:"type"`
+	Rooms   []liveRoom   `json:"rooms,omitempty"`
+	Event   *event.Event `json:"event,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+type liveRoom struct {
+	RoomID       string                 `json:"room_id"`
+	Reservations []reservation.Response `json:"reservations"`
+}
+
+// @Summary Live room boards
+// @Description WebSocket following reservations of rooms. Clients send {"type":"subscribe","rooms":[...],"buildings":[...]} and receive a snapshot of the reservations of those rooms followed by events of their changes. Requires an API key, which browsers may pass as the api_key query parameter, and web pages must be served by the service or from an allowed origin. Clients that can't keep up are disconnected with close code 1013 and should subscribe again.
+// @Tags Events
+// @Param X-API-Key header string false "API key"
+// @Param api_key query string false "API key"
+// @Success 101
+// @Failure 401
+// @Failure 403
+// @Router /live [get]
+func (h *ReservationHandler) liveBoard(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// subscribe before taking snapshots so that nothing committed in between is missed
+	sub := h.broker.Subscribe("")
+	defer sub.Close()
+
+	requests := make(chan liveRequest)
+	go h.readLiveRequests(ctx, cancel, conn, requests)
+
+	ping := time.NewTicker(livePingPeriod)
+	defer ping.Stop()
+
+	rooms := map[string]bool{}
+
+	for {
+		var msg *liveMessage
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		case req := <-requests:
+			snapshot, subscribed, err := h.liveSnapshot(ctx, req)
+			if err != nil {
+				logger.Err(err).Caller().Send()
+				msg = &liveMessage{Type: liveError, Message: err.Error()}
+				break
+			}
+
+			rooms = subscribed
+			msg = &liveMessage{Type: liveSnapshot, Rooms: snapshot}
+		case e, ok := <-sub.C:
+			if !ok {
+				code, reason := websocket.CloseTryAgainLater, "too slow"
+				if h.broker.Closed() {
+					code, reason = websocket.CloseGoingAway, "shutting down"
+				}
+
+				conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+				return
+			}
+
+			if !rooms[e.RoomID] {
+				continue
+			}
+
+			msg = &liveMessage{Type: liveEvent, Event: &e}
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+func (h *ReservationHandler) readLiveRequests(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, requests chan<- liveRequest) {
+	defer cancel()
+
+	conn.SetReadLimit(liveMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(livePongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(livePongWait))
+	})
+
+	for {
+		var req liveRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		select {
+		case requests <- req:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// liveSnapshot resolves the rooms of a request and lists their reservations.
+func (h *ReservationHandler) liveSnapshot(ctx context.Context, req liveRequest) ([]liveRoom, map[string]bool, error) {
+	if req.Type != liveSubscribe {
+		return nil, nil, fmt.Errorf("unknown message type %q", req.Type)
+	}
+
+	rooms := map[string]bool{}
+	for _, ID := range req.Rooms {
+		rooms[ID] = true
+	}
+
+	if len(req.Buildings) > 0 && h.roomRepo == nil {
+		return nil, nil, errors.New("buildings are not supported")
+	}
+
+	for _, building := range req.Buildings {
+		data, err := h.roomRepo.List(ctx, room.Filter{Building: building})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, r := range data {
+			rooms[r.ID] = true
+		}
+	}
+
+	if len(rooms) > liveMaxRooms {
+		return nil, nil, fmt.Errorf("at most %d rooms can be followed", liveMaxRooms)
+	}
+
+	snapshot := make([]liveRoom, 0, len(rooms))
+	for ID := range rooms {
+		data, err := h.reservationRepo.List(ctx, ID, reservation.ListOptions{})
+		if err != nil && !errors.Is(err, reservation.ErrorNotFoundForRoom) {
+			return nil, nil, err
+		}
+
+		snapshot = append(snapshot, liveRoom{RoomID: ID, Reservations: reservation.ToResponseSlice(data)})
+	}
+
+	return snapshot, rooms, nil
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/stream"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLiveServer(t *testing.T) (*httptest.Server, *stream.Broker) {
+	t.Helper()
+
+	start := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+	reservations := &reservationRepository{data: map[string]reservation.Reservation{
+		"abc": {ID: "abc", RoomID: "1", StartTime: start, EndTime: start.Add(time.Hour)},
+	}}
+	broker := stream.NewBroker()
+
+	h := NewReservationHandler(reservations,
+		WithEventStream(&eventRepository{}, broker),
+		WithAPIKeys("client-key"),
+		WithAllowedOrigins("https://board.example.com"),
+	)
+
+	srv := httptest.NewServer(h.HTTP)
+	t.Cleanup(srv.Close)
+
+	return srv, broker
+}
+
+func dialLive(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/live?api_key=client-key", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestLiveBoardAccess(t *testing.T) {
+	srv, _ := newLiveServer(t)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/live"
+
+	for name, tc := range map[string]struct {
+		query  string
+		origin string
+		status int
+	}{
+		"no API key":      {status: http.StatusUnauthorized},
+		"unknown API key": {query: "?api_key=unknown", status: http.StatusForbidden},
+		"other site":      {query: "?api_key=client-key", origin: "https://evil.example.com", status: http.StatusForbidden},
+		"allowed origin":  {query: "?api_key=client-key", origin: "https://board.example.com", status: http.StatusSwitchingProtocols},
+	} {
+		header := http.Header{}
+		if tc.origin != "" {
+			header.Set("Origin", tc.origin)
+		}
+
+		conn, resp, err := websocket.DefaultDialer.Dial(url+tc.query, header)
+		if conn != nil {
+			conn.Close()
+		}
+
+		require.NotNil(t, resp, name, err)
+		assert.Equal(t, tc.status, resp.StatusCode, name)
+	}
+}
+
+func TestLiveBoard(t *testing.T) {
+	srv, broker := newLiveServer(t)
+	conn := dialLive(t, srv)
+
+	require.NoError(t, conn.WriteJSON(liveRequest{Type: liveSubscribe, Rooms: []string{"1"}}))
+
+	var msg liveMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, liveSnapshot, msg.Type)
+	require.Len(t, msg.Rooms, 1)
+	assert.Equal(t, "1", msg.Rooms[0].RoomID)
+	require.Len(t, msg.Rooms[0].Reservations, 1, "expected the reservations of the room in the snapshot")
+	assert.Equal(t, "abc", msg.Rooms[0].Reservations[0].ID)
+
+	ctx := context.Background()
+	require.NoError(t, broker.Publish(ctx, event.Event{ID: 1, Type: event.TypeReservationCreated, RoomID: "2", Data: []byte(`{}`)}))
+	require.NoError(t, broker.Publish(ctx, event.Event{ID: 2, Type: event.TypeReservationCancelled, RoomID: "1", Data: []byte(`{}`)}))
+
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, liveEvent, msg.Type)
+	assert.Equal(t, int64(2), msg.Event.ID, "expected events of rooms not followed to be left out")
+
+	require.NoError(t, conn.WriteJSON(liveRequest{Type: "unsubscribe"}))
+
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, liveError, msg.Type)
+}
+
+func TestLiveBoardSlowClient(t *testing.T) {
+	srv, broker := newLiveServer(t)
+	conn := dialLive(t, srv)
+
+	require.NoError(t, conn.WriteJSON(liveRequest{Type: liveSubscribe, Rooms: []string{"1"}}))
+
+	var msg liveMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, liveSnapshot, msg.Type)
+
+	// events too large for the connection to keep up fill the buffer of the subscription while nothing is read
+	data, err := json.Marshal(strings.Repeat("x", 256<<10))
+	require.NoError(t, err)
+
+	for i := range 500 {
+		broker.Publish(context.Background(), event.Event{ID: int64(i), Type: event.TypeReservationUpdated, RoomID: "1", Data: data})
+	}
+
+	for {
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "expected slow clients to be told to try again later, got %v", err)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"room-reservation/internal/domain/notification"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) userRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Get("/me/notifications", h.getNotificationPreference)
+	r.Put("/me/notifications", h.saveNotificationPreference)
+
+	return r
+}
+
+// @Summary Get notification preferences
+// @Description Get how the calling user is notified of their reservations
+// @Tags Notifications
+// @Produce json
+// @Param X-User-ID header string true "User id"
+// @Success 200 {object} response.BaseObject{data=notification.PreferenceResponse}
+// @Failure 401
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /users/me/notifications [get]
+func (h *ReservationHandler) getNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	userID := router.IdentityFromContext(r.Context()).UserID
+	if userID == "" {
+		response.Unauthorized(w)
+		return
+	}
+
+	data, err := h.notificationRepo.GetPreference(r.Context(), userID)
+	if err != nil {
+		if !errors.Is(err, notification.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.InternalServerError(w, r, err)
+			return
+		}
+
+		data = notification.DefaultPreference(userID)
+	}
+
+	response.OK(w, r, notification.ToPreferenceResponse(data))
+}
+
+// @Summary Save notification preferences
+// @Description Set the address the calling user is notified at, how long before their reservations they are reminded, or opt out of emails
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User id"
+// @Param preference body notification.PreferenceRequest true "Preferences"
+// @Success 200 {object} response.BaseObject{data=notification.PreferenceResponse}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /users/me/notifications [put]
+func (h *ReservationHandler) saveNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	userID := router.IdentityFromContext(r.Context()).UserID
+	if userID == "" {
+		response.Unauthorized(w)
+		return
+	}
+
+	var req notification.PreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data := notification.DefaultPreference(userID)
+	data.Email = req.Email
+	data.OptOut = req.OptOut
+
+	if req.ReminderMinutes != nil {
+		data.ReminderMinutes = *req.ReminderMinutes
+	}
+
+	if err := h.notificationRepo.SavePreference(r.Context(), data); err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	data, err := h.notificationRepo.GetPreference(r.Context(), userID)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, notification.ToPreferenceResponse(data))
+}
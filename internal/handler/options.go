@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/notification"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/domain/webhook"
+	"room-reservation/internal/stream"
+	"room-reservation/pkg/health"
+	"room-reservation/pkg/router"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Option func(*ReservationHandler)
+
+// WithRateLimitStore sets where rate limit buckets are kept, by default they live in process memory.
+func WithRateLimitStore(store router.LimiterStore) Option {
+	return func(h *ReservationHandler) {
+		h.rateLimitStore = store
+	}
+}
+
+// WithAuditRepository enables the reservation history and audit log endpoints.
+func WithAuditRepository(repo audit.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.auditRepo = repo
+	}
+}
+
+// WithAdminAPIKeys sets the API keys allowed to use admin endpoints.
+func WithAdminAPIKeys(keys ...string) Option {
+	return func(h *ReservationHandler) {
+		h.adminAPIKeys = keys
+	}
+}
+
+// WithAPIKeys sets the API keys of clients allowed to follow live boards, along with admin API keys.
+func WithAPIKeys(keys ...string) Option {
+	return func(h *ReservationHandler) {
+		h.apiKeys = keys
+	}
+}
+
+// WithAllowedOrigins sets the origins of web pages allowed to open WebSockets besides the service itself.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(h *ReservationHandler) {
+		h.allowedOrigins = origins
+	}
+}
+
+// WithTrustedProxies sets the addresses or CIDR ranges of the reverse proxies in front of the service,
+// anonymous clients are told apart by the address they forward.
+func WithTrustedProxies(proxies ...string) Option {
+	return func(h *ReservationHandler) {
+		h.trustedProxies = proxies
+	}
+}
+
+// WithWebhookRepository enables the webhook subscription endpoints.
+func WithWebhookRepository(repo webhook.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.webhookRepo = repo
+	}
+}
+
+// WithEventStream enables streaming events published to broker, resuming streams from repo.
+func WithEventStream(repo event.Repository, broker *stream.Broker) Option {
+	return func(h *ReservationHandler) {
+		h.eventRepo = repo
+		h.broker = broker
+	}
+}
+
+// WithRoomRepository enables the room endpoints, the GraphQL endpoint and following rooms of a building on live boards.
+func WithRoomRepository(repo room.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.roomRepo = repo
+	}
+}
+
+// WithCalendarRepository enables secret calendar feed URLs of rooms and users.
+func WithCalendarRepository(repo calendar.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.calendarRepo = repo
+	}
+}
+
+// WithNotificationRepository enables the notification preference endpoints.
+func WithNotificationRepository(repo notification.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.notificationRepo = repo
+	}
+}
+
+// WithChatRepository enables the chat channel endpoints.
+func WithChatRepository(repo chat.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.chatRepo = repo
+	}
+}
+
+// WithScheduleRepository enables the opening hours endpoints and leaves closed times out of availability searches.
+func WithScheduleRepository(repo schedule.Repository) Option {
+	return func(h *ReservationHandler) {
+		h.scheduleRepo = repo
+	}
+}
+
+// WithMetrics enables the /metrics endpoint serving the metrics of reg, and registers HTTP request metrics with it.
+func WithMetrics(reg *prometheus.Registry) Option {
+	return func(h *ReservationHandler) {
+		h.metrics = reg
+	}
+}
+
+// WithHealth enables the /healthz liveness and /readyz readiness endpoints reporting the checks of c.
+func WithHealth(c *health.Checker) Option {
+	return func(h *ReservationHandler) {
+		h.health = c
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) roomRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	if h.roomRepo != nil {
+		r.Get("/", h.listRooms)
+	}
+
+	r.Route("/{roomID}", func(r chi.Router) {
+		if h.roomRepo != nil {
+			r.Get("/", h.getRoom)
+			r.With(router.RequireAPIKey(h.adminAPIKeys...)).Put("/", h.saveRoom)
+		}
+
+		r.Get("/calendar.ics", h.roomCalendar)
+		r.Get("/availability", h.roomAvailability)
+
+		if h.scheduleRepo != nil {
+			r.Get("/schedule", h.getRoomSchedule)
+			r.With(router.RequireAPIKey(h.adminAPIKeys...)).Put("/schedule", h.saveRoomSchedule)
+			r.With(router.RequireAPIKey(h.adminAPIKeys...)).Delete("/schedule", h.deleteRoomSchedule)
+		}
+
+		if h.broker != nil {
+			r.With(router.RequireAPIKey(h.clientAPIKeys()...)).Get("/events", h.streamRoomEvents)
+		}
+	})
+
+	return r
+}
+
+// @Summary List rooms
+// @Description List rooms, optionally of a building
+// @Tags Rooms
+// @Produce json
+// @Param building query string false "Building"
+// @Success 200 {object} response.BaseObject{data=[]room.Response}
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms [get]
+func (h *ReservationHandler) listRooms(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	data, err := h.roomRepo.List(r.Context(), room.Filter{Building: r.URL.Query().Get("building")})
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, room.ToResponseSlice(data))
+}
+
+// @Summary Get room
+// @Description Get room
+// @Tags Rooms
+// @Produce json
+// @Param roomID path string true "Room id"
+// @Success 200 {object} response.BaseObject{data=room.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID} [get]
+func (h *ReservationHandler) getRoom(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "roomID")
+
+	data, err := h.roomRepo.Get(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, room.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, room.ToResponse(data))
+}
+
+// @Summary Save room
+// @Description Create or replace room, requires an admin API key
+// @Tags Rooms
+// @Accept json
+// @Param X-API-Key header string true "Admin API key"
+// @Param roomID path string true "Room id"
+// @Param room body room.Request true "Room"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID} [put]
+func (h *ReservationHandler) saveRoom(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var req room.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data := room.Room{
+		ID:       chi.URLParam(r, "roomID"),
+		Name:     req.Name,
+		Building: req.Building,
+		Capacity: req.Capacity,
+	}
+
+	if err := h.roomRepo.Save(r.Context(), data); err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
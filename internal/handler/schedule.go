@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) buildingRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Route("/{building}/schedule", func(r chi.Router) {
+		r.Get("/", h.getBuildingSchedule)
+		r.With(router.RequireAPIKey(h.adminAPIKeys...)).Put("/", h.saveBuildingSchedule)
+		r.With(router.RequireAPIKey(h.adminAPIKeys...)).Delete("/", h.deleteBuildingSchedule)
+	})
+
+	return r
+}
+
+// @Summary Get room opening hours
+// @Description Get the opening hours of a room, it is also closed whenever its building is
+// @Tags Schedules
+// @Produce json
+// @Param roomID path string true "Room id"
+// @Success 200 {object} response.BaseObject{data=schedule.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID}/schedule [get]
+func (h *ReservationHandler) getRoomSchedule(w http.ResponseWriter, r *http.Request) {
+	h.getSchedule(w, r, schedule.ScopeRoom, chi.URLParam(r, "roomID"))
+}
+
+// @Summary Get building opening hours
+// @Description Get the opening hours of every room of a building
+// @Tags Schedules
+// @Produce json
+// @Param building path string true "Building"
+// @Success 200 {object} response.BaseObject{data=schedule.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /buildings/{building}/schedule [get]
+func (h *ReservationHandler) getBuildingSchedule(w http.ResponseWriter, r *http.Request) {
+	h.getSchedule(w, r, schedule.ScopeBuilding, chi.URLParam(r, "building"))
+}
+
+func (h *ReservationHandler) getSchedule(w http.ResponseWriter, r *http.Request, scope, key string) {
+	logger := log.LoggerFromContext(r.Context())
+
+	data, err := h.scheduleRepo.Get(r.Context(), scope, key)
+	if err != nil {
+		if errors.Is(err, schedule.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, key)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, schedule.ToResponse(data))
+}
+
+// @Summary Save room opening hours
+// @Description Create or replace the opening hours of a room, requires an admin API key. Reservations already made outside of them are kept.
+// @Tags Schedules
+// @Accept json
+// @Param X-API-Key header string true "Admin API key"
+// @Param roomID path string true "Room id"
+// @Param schedule body schedule.Request true "Opening hours"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID}/schedule [put]
+func (h *ReservationHandler) saveRoomSchedule(w http.ResponseWriter, r *http.Request) {
+	h.saveSchedule(w, r, schedule.ScopeRoom, chi.URLParam(r, "roomID"))
+}
+
+// @Summary Save building opening hours
+// @Description Create or replace the opening hours of every room of a building, requires an admin API key. Reservations already made outside of them are kept.
+// @Tags Schedules
+// @Accept json
+// @Param X-API-Key header string true "Admin API key"
+// @Param building path string true "Building"
+// @Param schedule body schedule.Request true "Opening hours"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /buildings/{building}/schedule [put]
+func (h *ReservationHandler) saveBuildingSchedule(w http.ResponseWriter, r *http.Request) {
+	h.saveSchedule(w, r, schedule.ScopeBuilding, chi.URLParam(r, "building"))
+}
+
+func (h *ReservationHandler) saveSchedule(w http.ResponseWriter, r *http.Request, scope, key string) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var req schedule.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := h.scheduleRepo.Save(r.Context(), req.ToSchedule(scope, key)); err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// @Summary Delete room opening hours
+// @Description Delete the opening hours of a room, requires an admin API key. The room then follows the opening hours of its building only.
+// @Tags Schedules
+// @Param X-API-Key header string true "Admin API key"
+// @Param roomID path string true "Room id"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID}/schedule [delete]
+func (h *ReservationHandler) deleteRoomSchedule(w http.ResponseWriter, r *http.Request) {
+	h.deleteSchedule(w, r, schedule.ScopeRoom, chi.URLParam(r, "roomID"))
+}
+
+// @Summary Delete building opening hours
+// @Description Delete the opening hours of a building, requires an admin API key. Its rooms are then open whenever their own opening hours allow.
+// @Tags Schedules
+// @Param X-API-Key header string true "Admin API key"
+// @Param building path string true "Building"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /buildings/{building}/schedule [delete]
+func (h *ReservationHandler) deleteBuildingSchedule(w http.ResponseWriter, r *http.Request) {
+	h.deleteSchedule(w, r, schedule.ScopeBuilding, chi.URLParam(r, "building"))
+}
+
+func (h *ReservationHandler) deleteSchedule(w http.ResponseWriter, r *http.Request, scope, key string) {
+	logger := log.LoggerFromContext(r.Context())
+
+	if err := h.scheduleRepo.Delete(r.Context(), scope, key); err != nil {
+		if errors.Is(err, schedule.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, key)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// @Summary Get room availability
+// @Description List the times between from and to the room is open and not reserved, at most 366 days apart
+// @Tags Rooms
+// @Produce json
+// @Param roomID path string true "Room id"
+// @Param from query string true "Start of the time to search, RFC 3339" example(2024-08-29T08:00:00Z)
+// @Param to query string true "End of the time to search, RFC 3339" example(2024-08-29T18:00:00Z)
+// @Success 200 {object} response.BaseObject{data=schedule.AvailabilityResponse}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /rooms/{roomID}/availability [get]
+func (h *ReservationHandler) roomAvailability(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	roomID := chi.URLParam(r, "roomID")
+
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, errors.New("from must be an RFC 3339 time"), r.URL.Query().Get("from"))
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, errors.New("to must be an RFC 3339 time"), r.URL.Query().Get("to"))
+		return
+	}
+
+	from, to = from.UTC(), to.UTC()
+
+	if !to.After(from) {
+		err := errors.New("to must be after from")
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, r.URL.Query())
+		return
+	}
+
+	if to.Sub(from) > schedule.MaxSearch {
+		err := errors.New("to must be at most 366 days after from")
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, r.URL.Query())
+		return
+	}
+
+	data, err := h.reservationRepo.List(r.Context(), roomID, reservation.ListOptions{From: from, To: to})
+	if err != nil && !errors.Is(err, reservation.ErrorNotFoundForRoom) {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	var hours schedule.Hours
+	if h.scheduleRepo != nil {
+		byRoom, err := h.scheduleRepo.ForRooms(r.Context(), []string{roomID})
+		if err != nil {
+			logger.Err(err).Caller().Send()
+			response.InternalServerError(w, r, err)
+			return
+		}
+
+		hours = byRoom[roomID]
+	}
+
+	response.OK(w, r, schedule.ToAvailabilityResponse(from, to, hours.FreeSlots(data, from, to)))
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"room-reservation/internal/domain/webhook"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server/response"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *ReservationHandler) webhookRoutes() *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(router.RequireAPIKey(h.adminAPIKeys...))
+
+	r.Post("/", h.createWebhook)
+	r.Get("/", h.listWebhooks)
+
+	r.Route("/{webhookID}", func(r chi.Router) {
+		r.Get("/", h.getWebhook)
+		r.Delete("/", h.deleteWebhook)
+		r.Post("/enable", h.enableWebhook)
+		r.Get("/deliveries", h.listWebhookDeliveries)
+		r.Post("/deliveries/{deliveryID}/replay", h.replayWebhookDelivery)
+	})
+
+	return r
+}
+
+// @Summary Create webhook
+// @Description Subscribe an endpoint to reservation events. Deliveries are signed with HMAC-SHA256 of "{timestamp}.{body}" using the secret, sent in the X-Webhook-Signature header as t={timestamp},v1={signature}. The secret is generated unless given and only returned here. URLs pointing to loopback, link-local or private addresses are refused.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param webhook body webhook.CreateRequest true "Webhook subscription"
+// @Success 201 {object} response.BaseObject{data=webhook.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks [post]
+func (h *ReservationHandler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	var req webhook.CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := req.CheckHost(r.Context(), net.DefaultResolver.LookupNetIP); err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	data := webhook.Subscription{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		RoomIDs:    req.RoomIDs,
+		Secret:     req.Secret,
+	}
+
+	if data.Secret == "" {
+		data.Secret = webhook.NewSecret()
+	}
+
+	ID, err := h.webhookRepo.Create(r.Context(), data)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	data, err = h.webhookRepo.Get(r.Context(), ID)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	res := webhook.ToResponse(data)
+	res.Secret = data.Secret
+
+	w.Header().Set("Location", r.URL.Path+"/"+ID)
+	response.Status(w, r, http.StatusCreated, res)
+}
+
+// @Summary List webhooks
+// @Description List webhook subscriptions
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} response.BaseObject{data=[]webhook.Response}
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks [get]
+func (h *ReservationHandler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	data, err := h.webhookRepo.List(r.Context())
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, webhook.ToResponseSlice(data))
+}
+
+// @Summary Get webhook
+// @Description Get webhook subscription
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param webhookID path string true "Webhook id"
+// @Success 200 {object} response.BaseObject{data=webhook.Response}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks/{webhookID} [get]
+func (h *ReservationHandler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "webhookID")
+
+	data, err := h.webhookRepo.Get(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, webhook.ToResponse(data))
+}
+
+// @Summary Delete webhook
+// @Description Delete webhook subscription and its delivery log
+// @Tags Webhooks
+// @Param X-API-Key header string true "Admin API key"
+// @Param webhookID path string true "Webhook id"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks/{webhookID} [delete]
+func (h *ReservationHandler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "webhookID")
+
+	err := h.webhookRepo.Delete(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// @Summary Enable webhook
+// @Description Enable a webhook subscription disabled after failing too many times in a row
+// @Tags Webhooks
+// @Param X-API-Key header string true "Admin API key"
+// @Param webhookID path string true "Webhook id"
+// @Success 204
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks/{webhookID}/enable [post]
+func (h *ReservationHandler) enableWebhook(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "webhookID")
+
+	err := h.webhookRepo.Enable(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// @Summary List webhook deliveries
+// @Description List the latest deliveries of a webhook subscription
+// @Tags Webhooks
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param webhookID path string true "Webhook id"
+// @Success 200 {object} response.BaseObject{data=[]webhook.DeliveryResponse}
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks/{webhookID}/deliveries [get]
+func (h *ReservationHandler) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "webhookID")
+
+	data, err := h.webhookRepo.Deliveries(r.Context(), ID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrorNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, ID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, webhook.ToDeliveryResponseSlice(data))
+}
+
+// @Summary Replay webhook delivery
+// @Description Send a delivery again
+// @Tags Webhooks
+// @Param X-API-Key header string true "Admin API key"
+// @Param webhookID path string true "Webhook id"
+// @Param deliveryID path int true "Delivery id"
+// @Success 202
+// @Failure 400 {object} response.BadRequestResponse
+// @Failure 401
+// @Failure 403
+// @Failure 500 {object} response.InternalServerErrorResponse
+// @Router /webhooks/{webhookID}/deliveries/{deliveryID}/replay [post]
+func (h *ReservationHandler) replayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	logger := log.LoggerFromContext(r.Context())
+
+	ID := chi.URLParam(r, "webhookID")
+
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		logger.Err(err).Caller().Send()
+		response.BadRequest(w, r, err, chi.URLParam(r, "deliveryID"))
+		return
+	}
+
+	err = h.webhookRepo.Replay(r.Context(), ID, deliveryID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrorDeliveryNotFound) {
+			logger.Err(err).Caller().Send()
+			response.BadRequest(w, r, err, deliveryID)
+			return
+		}
+
+		logger.Err(err).Caller().Send()
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.Accepted(w)
+}
@@ -0,0 +1,56 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Request struct {
+	// Calendar is the content of the .ics file.
+	Calendar string  `json:"calendar" example:"BEGIN:VCALENDAR..."`
+	Mapping  Mapping `json:"mapping"`
+	// TimeZone is used for event times without a time zone, UTC by default.
+	TimeZone string `json:"time_zone,omitempty" example:"Europe/Berlin"`
+	// Until limits the occurrences of recurring events, a year from now by default.
+	Until  *time.Time `json:"until,omitempty"`
+	DryRun bool       `json:"dry_run"`
+}
+
+func (r *Request) Validate() error {
+	if r.Calendar == "" {
+		return errors.New("calendar is required")
+	}
+
+	if len(r.Mapping.Rooms) == 0 && r.Mapping.DefaultRoomID == "" {
+		return errors.New("mapping must map locations to rooms or set a default room")
+	}
+
+	if r.TimeZone != "" {
+		if _, err := time.LoadLocation(r.TimeZone); err != nil {
+			return fmt.Errorf("invalid time_zone: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Options assumes the request is valid.
+func (r *Request) Options(now time.Time) Options {
+	opts := Options{
+		Mapping:  r.Mapping,
+		Location: time.UTC,
+		Until:    now.AddDate(1, 0, 0),
+		DryRun:   r.DryRun,
+	}
+
+	if r.TimeZone != "" {
+		opts.Location, _ = time.LoadLocation(r.TimeZone)
+	}
+
+	if r.Until != nil {
+		opts.Until = *r.Until
+	}
+
+	return opts
+}
@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/ical"
+	"strings"
+	"time"
+)
+
+// Mapping maps event locations to rooms, locations are matched case-insensitively.
+// Events at other locations go to DefaultRoomID, or are skipped when it is empty.
+type Mapping struct {
+	Rooms         map[string]string `json:"rooms"`
+	DefaultRoomID string            `json:"default_room_id,omitempty"`
+}
+
+func (m Mapping) RoomID(location string) (string, bool) {
+	location = strings.TrimSpace(location)
+
+	for l, ID := range m.Rooms {
+		if strings.EqualFold(strings.TrimSpace(l), location) {
+			return ID, true
+		}
+	}
+
+	return m.DefaultRoomID, m.DefaultRoomID != ""
+}
+
+type Options struct {
+	Mapping Mapping
+	// Location is used for event times without a time zone.
+	Location *time.Location
+	// Until limits the occurrences of recurring events.
+	Until  time.Time
+	DryRun bool
+}
+
+const (
+	StatusCreated  = "created"
+	StatusSkipped  = "skipped"
+	StatusConflict = "conflict"
+	StatusFailed   = "failed"
+)
+
+// MaxOccurrences is the number of occurrences imported of a single recurring event.
+const MaxOccurrences = 1000
+
+// Item is the outcome of importing an event, or an occurrence of a recurring event.
+type Item struct {
+	UID           string    `json:"uid"`
+	Summary       string    `json:"summary,omitempty"`
+	Location      string    `json:"location,omitempty"`
+	RoomID        string    `json:"room_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Status        string    `json:"status" enums:"created,skipped,conflict,failed"`
+	Reason        string    `json:"reason,omitempty"`
+	ReservationID string    `json:"reservation_id,omitempty"`
+}
+
+// Report describes what an import created, or would create on a dry run.
+type Report struct {
+	DryRun    bool   `json:"dry_run"`
+	Created   int    `json:"created"`
+	Skipped   int    `json:"skipped"`
+	Conflicts int    `json:"conflicts"`
+	Failed    int    `json:"failed"`
+	Items     []Item `json:"items"`
+}
+
+// Importer creates reservations from calendar events,
+// going through the same overlap checks as reservations created through the API.
+type Importer struct {
+	repo reservation.Repository
+}
+
+func New(repo reservation.Repository) *Importer {
+	return &Importer{
+		repo: repo,
+	}
+}
+
+func (i *Importer) Import(ctx context.Context, r io.Reader, opts Options) (Report, error) {
+	c, err := ical.Decoder{Location: opts.Location}.Decode(r)
+	if err != nil {
+		return Report{}, err
+	}
+
+	events, err := c.Expand(opts.Until, MaxOccurrences)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{DryRun: opts.DryRun, Items: make([]Item, 0, len(events))}
+
+	var data []reservation.Reservation
+	var pending []int
+
+	for _, e := range events {
+		item := Item{
+			UID:       eventUID(e),
+			Summary:   e.Summary,
+			Location:  e.Location,
+			StartTime: e.Start.UTC(),
+			EndTime:   e.End.UTC(),
+		}
+
+		roomID, ok := opts.Mapping.RoomID(e.Location)
+
+		switch {
+		case e.Status == ical.StatusCancelled:
+			item.Status, item.Reason = StatusSkipped, "event is cancelled"
+		case e.AllDay:
+			item.Status, item.Reason = StatusSkipped, "all-day event"
+		case !e.Start.Before(e.End):
+			item.Status, item.Reason = StatusSkipped, "event has no duration"
+		case !ok:
+			item.Status, item.Reason = StatusSkipped, "no room mapped for location"
+		default:
+			item.RoomID = roomID
+
+			data = append(data, reservation.Reservation{
+				RoomID:    roomID,
+				StartTime: item.StartTime,
+				EndTime:   item.EndTime,
+				ICalUID:   item.UID,
+			})
+			pending = append(pending, len(report.Items))
+		}
+
+		report.Items = append(report.Items, item)
+	}
+
+	if len(data) > 0 {
+		results, err := i.repo.Import(ctx, data, opts.DryRun)
+		if err != nil {
+			return Report{}, err
+		}
+
+		for n, res := range results {
+			item := &report.Items[pending[n]]
+
+			switch {
+			case res.Err == nil:
+				item.Status, item.ReservationID = StatusCreated, res.ID
+			case errors.Is(res.Err, reservation.ErrorImported):
+				item.Status, item.Reason = StatusSkipped, res.Err.Error()
+			case errors.Is(res.Err, reservation.ErrorOverlaps), errors.Is(res.Err, reservation.ErrorClosed):
+				item.Status, item.Reason = StatusConflict, res.Err.Error()
+			default:
+				item.Status, item.Reason = StatusFailed, res.Err.Error()
+			}
+		}
+	}
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case StatusCreated:
+			report.Created++
+		case StatusSkipped:
+			report.Skipped++
+		case StatusConflict:
+			report.Conflicts++
+		case StatusFailed:
+			report.Failed++
+		}
+	}
+
+	return report, nil
+}
+
+// eventUID identifies occurrences of recurring events by their original start.
+func eventUID(e ical.Event) string {
+	if e.RecurrenceID.IsZero() {
+		return e.UID
+	}
+
+	return e.UID + "/" + e.RecurrenceID.UTC().Format("20060102T150405Z")
+}
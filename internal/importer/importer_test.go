@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"context"
+	"room-reservation/internal/domain/reservation"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type importRepository struct {
+	reservation.Repository
+
+	data   []reservation.Reservation
+	dryRun bool
+}
+
+func (r *importRepository) Import(ctx context.Context, data []reservation.Reservation, dryRun bool) ([]reservation.ImportResult, error) {
+	r.data, r.dryRun = data, dryRun
+
+	results := make([]reservation.ImportResult, len(data))
+	for i, d := range data {
+		switch d.ICalUID {
+		case "taken@example.com":
+			results[i].Err = reservation.ErrorOverlaps
+		case "old@example.com":
+			results[i].Err = reservation.ErrorImported
+		default:
+			results[i].ID = "id-" + d.ICalUID
+		}
+	}
+
+	return results, nil
+}
+
+const calendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:daily@example.com\r\n" +
+	"DTSTART:20241021T090000\r\n" +
+	"DTEND:20241021T093000\r\n" +
+	"RRULE:FREQ=DAILY;COUNT=2\r\n" +
+	"LOCATION:everest \r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:taken@example.com\r\n" +
+	"DTSTART:20241022T120000Z\r\n" +
+	"DTEND:20241022T130000Z\r\n" +
+	"LOCATION:Everest\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:old@example.com\r\n" +
+	"DTSTART:20241022T140000Z\r\n" +
+	"DTEND:20241022T150000Z\r\n" +
+	"LOCATION:Everest\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:elsewhere@example.com\r\n" +
+	"DTSTART:20241023T120000Z\r\n" +
+	"DTEND:20241023T130000Z\r\n" +
+	"LOCATION:Kitchen\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled@example.com\r\n" +
+	"DTSTART:20241023T120000Z\r\n" +
+	"DTEND:20241023T130000Z\r\n" +
+	"LOCATION:Everest\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestImport(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	repo := &importRepository{}
+
+	report, err := New(repo).Import(context.Background(), strings.NewReader(calendar), Options{
+		Mapping:  Mapping{Rooms: map[string]string{"Everest": "1"}},
+		Location: berlin,
+		Until:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+
+	require.True(t, repo.dryRun, "expected dry run to be passed on")
+	require.Len(t, repo.data, 4, "expected only mapped and active events to be imported")
+
+	require.Equal(t, "1", repo.data[0].RoomID)
+	require.Equal(t, time.Date(2024, 10, 21, 7, 0, 0, 0, time.UTC), repo.data[0].StartTime, "expected floating times in the given location")
+	require.Equal(t, "daily@example.com/20241021T070000Z", repo.data[0].ICalUID, "expected occurrences to have their own UID")
+
+	require.Equal(t, 2, report.Created)
+	require.Equal(t, 1, report.Conflicts)
+	require.Equal(t, 3, report.Skipped)
+	require.Equal(t, 0, report.Failed)
+	require.Len(t, report.Items, 6)
+
+	statuses := map[string]string{}
+	for _, item := range report.Items {
+		statuses[item.UID] = item.Status
+	}
+
+	require.Equal(t, StatusConflict, statuses["taken@example.com"])
+	require.Equal(t, StatusSkipped, statuses["old@example.com"])
+	require.Equal(t, StatusSkipped, statuses["elsewhere@example.com"])
+	require.Equal(t, StatusSkipped, statuses["cancelled@example.com"])
+}
+
+func TestMapping(t *testing.T) {
+	m := Mapping{Rooms: map[string]string{"Everest": "1"}}
+
+	ID, ok := m.RoomID(" everest")
+	require.True(t, ok)
+	require.Equal(t, "1", ID)
+
+	_, ok = m.RoomID("Kitchen")
+	require.False(t, ok, "expected unmapped location to be skipped")
+
+	m.DefaultRoomID = "2"
+	ID, ok = m.RoomID("Kitchen")
+	require.True(t, ok)
+	require.Equal(t, "2", ID)
+}
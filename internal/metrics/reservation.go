@@ -0,0 +1,146 @@
+// Package metrics instruments the domain with Prometheus metrics.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"room-reservation/internal/domain/reservation"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type reservationRepository struct {
+	reservation.Repository
+
+	created   prometheus.Counter
+	conflicts *prometheus.CounterVec
+	updated   prometheus.Counter
+	cancelled prometheus.Counter
+	restored  prometheus.Counter
+	purged    prometheus.Counter
+}
+
+// Reservations counts changes made through repo, whichever API they come from, registering the counters with reg.
+func Reservations(repo reservation.Repository, reg prometheus.Registerer) reservation.Repository {
+	r := &reservationRepository{
+		Repository: repo,
+		created: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "reservations_created_total",
+			Help: "Number of reservations created, including imported ones.",
+		}),
+		conflicts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "reservation_overlap_conflicts_total",
+			Help: "Number of reservations refused for overlapping another, by operation.",
+		}, []string{"operation"}),
+		updated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "reservations_updated_total",
+			Help: "Number of reservations updated.",
+		}),
+		cancelled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "reservations_cancelled_total",
+			Help: "Number of reservations cancelled.",
+		}),
+		restored: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "reservations_restored_total",
+			Help: "Number of cancelled reservations restored.",
+		}),
+		purged: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "reservations_purged_total",
+			Help: "Number of cancelled reservations deleted for good.",
+		}),
+	}
+
+	reg.MustRegister(r.created, r.conflicts, r.updated, r.cancelled, r.restored, r.purged)
+
+	return r
+}
+
+func (r *reservationRepository) conflict(operation string, err error) {
+	if errors.Is(err, reservation.ErrorOverlaps) {
+		r.conflicts.WithLabelValues(operation).Inc()
+	}
+}
+
+func (r *reservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	ID, err := r.Repository.Create(ctx, data)
+	if err != nil {
+		r.conflict("create", err)
+		return ID, err
+	}
+
+	r.created.Inc()
+
+	return ID, nil
+}
+
+func (r *reservationRepository) Import(ctx context.Context, data []reservation.Reservation, dryRun bool) ([]reservation.ImportResult, error) {
+	results, err := r.Repository.Import(ctx, data, dryRun)
+	if err != nil || dryRun {
+		return results, err
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			r.conflict("import", res.Err)
+			continue
+		}
+
+		r.created.Inc()
+	}
+
+	return results, nil
+}
+
+func (r *reservationRepository) Update(ctx context.Context, ID string, data reservation.Reservation) error {
+	err := r.Repository.Update(ctx, ID, data)
+	if err != nil {
+		r.conflict("update", err)
+		return err
+	}
+
+	r.updated.Inc()
+
+	return nil
+}
+
+func (r *reservationRepository) Cancel(ctx context.Context, ID string, reason string) error {
+	err := r.Repository.Cancel(ctx, ID, reason)
+	if err == nil {
+		r.cancelled.Inc()
+	}
+
+	return err
+}
+
+func (r *reservationRepository) Restore(ctx context.Context, ID string) error {
+	err := r.Repository.Restore(ctx, ID)
+	if err != nil {
+		r.conflict("restore", err)
+		return err
+	}
+
+	r.restored.Inc()
+
+	return nil
+}
+
+func (r *reservationRepository) RestoreWith(ctx context.Context, ID string, data reservation.Reservation) error {
+	err := r.Repository.RestoreWith(ctx, ID, data)
+	if err != nil {
+		r.conflict("restore", err)
+		return err
+	}
+
+	r.restored.Inc()
+
+	return nil
+}
+
+func (r *reservationRepository) Purge(ctx context.Context, cancelledBefore time.Time) (int64, error) {
+	n, err := r.Repository.Purge(ctx, cancelledBefore)
+	r.purged.Add(float64(n))
+
+	return n, err
+}
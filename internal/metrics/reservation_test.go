@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"room-reservation/internal/domain/reservation"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	reservation.Repository
+
+	err error
+}
+
+func (r *fakeRepository) Create(context.Context, reservation.Reservation) (string, error) {
+	return "1", r.err
+}
+
+func (r *fakeRepository) Import(_ context.Context, data []reservation.Reservation, _ bool) ([]reservation.ImportResult, error) {
+	return []reservation.ImportResult{{ID: "2"}, {Err: reservation.ErrorOverlaps}}, nil
+}
+
+func (r *fakeRepository) Cancel(context.Context, string, string) error {
+	return r.err
+}
+
+func TestReservations(t *testing.T) {
+	fake := &fakeRepository{}
+	reg := prometheus.NewPedanticRegistry()
+
+	repo := Reservations(fake, reg)
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, reservation.Reservation{})
+	require.NoError(t, err)
+
+	_, err = repo.Import(ctx, make([]reservation.Reservation, 2), false)
+	require.NoError(t, err)
+
+	_, err = repo.Import(ctx, make([]reservation.Reservation, 2), true)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Cancel(ctx, "1", ""))
+
+	fake.err = reservation.ErrorOverlaps
+
+	_, err = repo.Create(ctx, reservation.Reservation{})
+	require.ErrorIs(t, err, reservation.ErrorOverlaps)
+
+	fake.err = reservation.ErrorNotFound
+	require.Error(t, repo.Cancel(ctx, "1", ""))
+
+	r := repo.(*reservationRepository)
+	assert.Equal(t, 2.0, testutil.ToFloat64(r.created), "expected dry runs and failures not to be counted")
+	assert.Equal(t, 1.0, testutil.ToFloat64(r.conflicts.WithLabelValues("create")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(r.conflicts.WithLabelValues("import")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(r.cancelled))
+
+	_, err = reg.Gather()
+	require.NoError(t, err, "expected metrics to be valid")
+}
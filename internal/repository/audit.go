@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/repository/postgres"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type AuditRepository struct {
+	db *postgres.DB
+}
+
+func NewAuditRepository(db *postgres.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) History(ctx context.Context, reservationID string) ([]audit.Entry, error) {
+	return r.Query(ctx, audit.Filter{ReservationID: reservationID})
+}
+
+func (r *AuditRepository) Query(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	conds, args := r.prepareConds(filter)
+
+	q := `
+		SELECT id, reservation_id, action, actor, request_id, before, after, created_at
+		FROM reservation_audit
+	`
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+	q += " ORDER BY id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		q += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []audit.Entry{}
+	for rows.Next() {
+		var e audit.Entry
+
+		err := rows.Scan(&e.ID, &e.ReservationID, &e.Action, &e.Actor, &e.RequestID, &e.Before, &e.After, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *AuditRepository) prepareConds(filter audit.Filter) (conds []string, args []any) {
+	if filter.ReservationID != "" {
+		args = append(args, filter.ReservationID)
+		conds = append(conds, fmt.Sprintf("reservation_id=$%d", len(args)))
+	}
+
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		conds = append(conds, fmt.Sprintf("actor=$%d", len(args)))
+	}
+
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conds = append(conds, fmt.Sprintf("action=$%d", len(args)))
+	}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("created_at>=$%d", len(args)))
+	}
+
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("created_at<$%d", len(args)))
+	}
+
+	return
+}
+
+// recordAudit appends a change of a reservation to the audit log within tx, the actor is taken from ctx.
+func recordAudit(ctx context.Context, tx pgx.Tx, action, reservationID string, before, after *reservation.Reservation) error {
+	actor := audit.ActorFromContext(ctx)
+
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+
+	q := `
+		INSERT INTO reservation_audit (reservation_id, action, actor, request_id, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	args := []any{reservationID, action, actor.ID, actor.RequestID, beforeJSON, afterJSON}
+
+	_, err = tx.Exec(ctx, q, args...)
+	return err
+}
+
+func snapshot(data *reservation.Reservation) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(data)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/reservation"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository(t *testing.T) {
+	ctx := audit.WithActor(context.Background(), audit.Actor{ID: "john", RequestID: "req-1"})
+
+	repo := &ReservationRepository{
+		db: db,
+	}
+
+	auditRepo := &AuditRepository{
+		db: db,
+	}
+
+	data := reservation.Reservation{
+		RoomID:    "audit",
+		StartTime: time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 8, 29, 14, 0, 0, 0, time.UTC),
+	}
+
+	ID, err := repo.Create(ctx, data)
+	require.NoError(t, err, "could not create reservation")
+
+	err = repo.Update(ctx, ID, reservation.Reservation{EndTime: data.EndTime.Add(time.Hour)})
+	require.NoError(t, err, "could not update reservation")
+
+	err = repo.Cancel(ctx, ID, "moved online")
+	require.NoError(t, err, "could not cancel reservation")
+
+	history, err := auditRepo.History(ctx, ID)
+	require.NoError(t, err, "could not get reservation history")
+	require.Len(t, history, 3, "expected an entry for every change")
+
+	actions := []string{audit.ActionCreate, audit.ActionUpdate, audit.ActionCancel}
+	for i, e := range history {
+		require.Equal(t, actions[i], e.Action)
+		require.Equal(t, "john", e.Actor)
+		require.Equal(t, "req-1", e.RequestID)
+	}
+
+	require.Empty(t, history[0].Before, "expected no snapshot before creation")
+	require.NotEmpty(t, history[1].Before, "expected snapshot before update")
+	require.NotEmpty(t, history[1].After, "expected snapshot after update")
+	require.NotEmpty(t, history[2].After, "expected snapshot after cancellation")
+
+	entries, err := auditRepo.Query(ctx, audit.Filter{Actor: "john", Action: audit.ActionCancel})
+	require.NoError(t, err, "could not query audit log")
+	require.NotEmpty(t, entries, "expected cancellation to be found")
+
+	_, err = db.Exec(ctx, "DELETE FROM reservation_audit WHERE reservation_id = $1", ID)
+	require.Error(t, err, "expected audit log to be append-only")
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type CalendarRepository struct {
+	db *postgres.DB
+}
+
+func NewCalendarRepository(db *postgres.DB) *CalendarRepository {
+	return &CalendarRepository{
+		db: db,
+	}
+}
+
+func (r *CalendarRepository) CreateFeed(ctx context.Context, data calendar.Feed) error {
+	q := `
+		INSERT INTO calendar_feed (token, room_id, user_id, created_by)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.Exec(ctx, q, data.Token, data.RoomID, data.UserID, data.CreatedBy)
+	return err
+}
+
+func (r *CalendarRepository) GetFeed(ctx context.Context, token string) (calendar.Feed, error) {
+	q := `
+		SELECT token, room_id, user_id, created_by, created_at
+		FROM calendar_feed
+		WHERE token = $1
+	`
+
+	res := calendar.Feed{}
+
+	err := r.db.QueryRow(ctx, q, token).Scan(&res.Token, &res.RoomID, &res.UserID, &res.CreatedBy, &res.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return calendar.Feed{}, calendar.ErrorNotFound
+		}
+
+		return calendar.Feed{}, err
+	}
+
+	return res, nil
+}
+
+func (r *CalendarRepository) DeleteFeed(ctx context.Context, token string) error {
+	q := `
+		DELETE FROM calendar_feed
+		WHERE token = $1
+	`
+
+	tag, err := r.db.Exec(ctx, q, token)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return calendar.ErrorNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/internal/domain/calendar"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &CalendarRepository{
+		db: db,
+	}
+
+	data := calendar.Feed{Token: calendar.NewToken(), UserID: "alice", CreatedBy: "alice"}
+
+	err := repo.CreateFeed(ctx, data)
+	require.NoError(t, err, "could not create feed")
+
+	res, err := repo.GetFeed(ctx, data.Token)
+	require.NoError(t, err, "could not get feed")
+	require.Equal(t, data.UserID, res.UserID)
+	require.False(t, res.CreatedAt.IsZero())
+
+	err = repo.CreateFeed(ctx, calendar.Feed{Token: calendar.NewToken(), RoomID: "1", UserID: "alice", CreatedBy: "alice"})
+	require.Error(t, err, "expected feed of both a room and a user to be rejected")
+
+	err = repo.DeleteFeed(ctx, data.Token)
+	require.NoError(t, err, "could not delete feed")
+
+	_, err = repo.GetFeed(ctx, data.Token)
+	require.ErrorIs(t, err, calendar.ErrorNotFound)
+
+	err = repo.DeleteFeed(ctx, data.Token)
+	require.ErrorIs(t, err, calendar.ErrorNotFound)
+}
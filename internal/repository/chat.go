@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/repository/postgres"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ChatRepository struct {
+	db *postgres.DB
+}
+
+func NewChatRepository(db *postgres.DB) *ChatRepository {
+	return &ChatRepository{
+		db: db,
+	}
+}
+
+const channelColumns = "id, name, url, room_ids, buildings, event_types, created_at"
+
+func scanChannel(row pgx.Row) (chat.Channel, error) {
+	c := chat.Channel{}
+
+	err := row.Scan(&c.ID, &c.Name, &c.URL, &c.RoomIDs, &c.Buildings, &c.EventTypes, &c.CreatedAt)
+
+	return c, err
+}
+
+func (r *ChatRepository) Create(ctx context.Context, data chat.Channel) (string, error) {
+	q := `
+		INSERT INTO chat_channel (id, name, url, room_ids, buildings, event_types)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	data.ID = generateID()
+	args := []any{data.ID, data.Name, data.URL, nonNil(data.RoomIDs), nonNil(data.Buildings), nonNil(data.EventTypes)}
+
+	_, err := r.db.Exec(ctx, q, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return data.ID, nil
+}
+
+func (r *ChatRepository) Get(ctx context.Context, ID string) (chat.Channel, error) {
+	q := `
+		SELECT ` + channelColumns + `
+		FROM chat_channel
+		WHERE id = $1
+	`
+
+	c, err := scanChannel(r.db.QueryRow(ctx, q, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return chat.Channel{}, chat.ErrorNotFound
+		}
+
+		return chat.Channel{}, err
+	}
+
+	return c, nil
+}
+
+func (r *ChatRepository) List(ctx context.Context) ([]chat.Channel, error) {
+	q := `
+		SELECT ` + channelColumns + `
+		FROM chat_channel
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []chat.Channel{}
+	for rows.Next() {
+		c, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
+
+func (r *ChatRepository) Delete(ctx context.Context, ID string) error {
+	q := `
+		DELETE FROM chat_channel
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return chat.ErrorNotFound
+	}
+
+	return nil
+}
+
+func (r *ChatRepository) Enqueue(ctx context.Context, e event.Event, building string, payload []byte) error {
+	channels, err := r.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	q := `
+		INSERT INTO chat_message (channel_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ON CONSTRAINT chat_message_event_unique DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, c := range channels {
+		if c.Matches(e, building) {
+			batch.Queue(q, c.ID, e.ID, e.Type, payload)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+// Deliver claims up to limit due messages and posts them, see leaseDuration.
+func (r *ChatRepository) Deliver(ctx context.Context, limit int, send func(context.Context, chat.Channel, chat.Message) chat.Result) (int, error) {
+	batch, err := r.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sentQuery := `
+		UPDATE chat_message
+		SET status = 'sent', attempts = attempts + 1, response_status = $1, last_error = '', sent_at = now()
+		WHERE id = $2
+	`
+
+	// Rate limited messages are postponed without using up an attempt.
+	postponedQuery := `
+		UPDATE chat_message
+		SET status = $1, rejections = $2, response_status = $3, last_error = $4,
+			next_attempt_at = now() + make_interval(secs => $5)
+		WHERE id = $6
+	`
+
+	failedQuery := `
+		UPDATE chat_message
+		SET status = $1, attempts = attempts + 1, response_status = $2, last_error = $3,
+			next_attempt_at = now() + make_interval(secs => $4)
+		WHERE id = $5
+	`
+
+	for _, p := range batch {
+		res := send(ctx, p.channel, p.message)
+
+		if res.Err == nil {
+			if _, err := r.db.Exec(ctx, sentQuery, res.ResponseStatus, p.message.ID); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if errors.Is(res.Err, chat.ErrorRateLimited) {
+			retryAfter := max(res.RetryAfter, time.Second)
+
+			rejections := p.message.Rejections
+			if res.ResponseStatus == http.StatusTooManyRequests {
+				rejections++
+			}
+
+			status := chat.StatusPending
+			if rejections >= chat.MaxRejections {
+				status = chat.StatusFailed
+			}
+
+			args := []any{status, rejections, res.ResponseStatus, res.Err.Error(), retryAfter.Seconds(), p.message.ID}
+			if _, err := r.db.Exec(ctx, postponedQuery, args...); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		attempt := p.message.Attempts + 1
+
+		status := chat.StatusPending
+		if attempt >= chat.MaxAttempts {
+			status = chat.StatusFailed
+		}
+
+		args := []any{status, res.ResponseStatus, res.Err.Error(), chat.Backoff.Delay(attempt).Seconds(), p.message.ID}
+		if _, err := r.db.Exec(ctx, failedQuery, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+type pendingMessage struct {
+	message chat.Message
+	channel chat.Channel
+}
+
+// claim picks up to limit due messages and leases them for leaseDuration.
+func (r *ChatRepository) claim(ctx context.Context, limit int) ([]pendingMessage, error) {
+	q := `
+		UPDATE chat_message m
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM chat_channel c
+		WHERE c.id = m.channel_id
+		AND m.id IN (
+			SELECT id
+			FROM chat_message
+			WHERE status = 'pending'
+			AND next_attempt_at <= now()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING m.id, m.channel_id, m.event_id, m.event_type, m.payload, m.status, m.attempts, m.rejections,
+			m.response_status, m.last_error, m.next_attempt_at, m.sent_at, m.created_at,
+			c.id, c.name, c.url, c.room_ids, c.buildings, c.event_types, c.created_at
+	`
+
+	rows, err := r.db.Query(ctx, q, limit, leaseDuration.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := []pendingMessage{}
+	for rows.Next() {
+		var p pendingMessage
+		m, c := &p.message, &p.channel
+
+		err := rows.Scan(&m.ID, &m.ChannelID, &m.EventID, &m.EventType, &m.Payload, &m.Status, &m.Attempts, &m.Rejections,
+			&m.ResponseStatus, &m.LastError, &m.NextAttemptAt, &m.SentAt, &m.CreatedAt,
+			&c.ID, &c.Name, &c.URL, &c.RoomIDs, &c.Buildings, &c.EventTypes, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(batch, func(i, j int) bool {
+		return batch[i].message.ID < batch[j].message.ID
+	})
+
+	return batch, nil
+}
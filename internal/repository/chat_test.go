@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/event"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &ChatRepository{
+		db: db,
+	}
+
+	ID, err := repo.Create(ctx, chat.Channel{
+		Name:      "#facilities",
+		URL:       "http://localhost/hooks/facilities",
+		Buildings: []string{"HQ"},
+	})
+	require.NoError(t, err, "could not create channel")
+
+	channel, err := repo.Get(ctx, ID)
+	require.NoError(t, err, "could not get channel")
+	require.Equal(t, []string{"HQ"}, channel.Buildings)
+	require.Empty(t, channel.RoomIDs)
+
+	hq := event.Event{ID: 201, Type: event.TypeReservationCreated, ReservationID: "1", RoomID: "1", Data: []byte(`{}`)}
+	annex := event.Event{ID: 202, Type: event.TypeReservationCreated, ReservationID: "2", RoomID: "2", Data: []byte(`{}`)}
+
+	for _, e := range []event.Event{hq, hq} {
+		err = repo.Enqueue(ctx, e, "HQ", []byte(`{"text":"Everest booked"}`))
+		require.NoError(t, err, "could not enqueue event")
+	}
+
+	err = repo.Enqueue(ctx, annex, "Annex", []byte(`{"text":"Kilimanjaro booked"}`))
+	require.NoError(t, err, "could not enqueue event")
+
+	sent := []chat.Message{}
+
+	n, err := repo.Deliver(ctx, 10, func(ctx context.Context, c chat.Channel, m chat.Message) chat.Result {
+		sent = append(sent, m)
+
+		// another dispatcher running meanwhile leaves the claimed message alone
+		n, err := repo.Deliver(ctx, 10, func(context.Context, chat.Channel, chat.Message) chat.Result { return chat.Result{} })
+		require.NoError(t, err, "could not deliver messages")
+		require.Zero(t, n, "expected claimed messages not to be posted twice")
+
+		return chat.Result{ResponseStatus: 429, RetryAfter: 30 * time.Second, Err: chat.ErrorRateLimited}
+	})
+	require.NoError(t, err, "could not deliver messages")
+	require.Equal(t, 1, n, "expected a single message of the followed building")
+	require.Equal(t, int64(201), sent[0].EventID)
+
+	var attempts int
+	var nextAttemptAt time.Time
+	err = db.QueryRow(ctx, "SELECT attempts, next_attempt_at FROM chat_message WHERE id = $1", sent[0].ID).Scan(&attempts, &nextAttemptAt)
+	require.NoError(t, err)
+	require.Equal(t, 0, attempts, "expected rate limited message not to use up an attempt")
+	require.True(t, nextAttemptAt.After(time.Now().Add(20*time.Second)), "expected message to be postponed by retry after")
+
+	_, err = db.Exec(ctx, "UPDATE chat_message SET next_attempt_at = now() WHERE id = $1", sent[0].ID)
+	require.NoError(t, err)
+
+	n, err = repo.Deliver(ctx, 10, func(context.Context, chat.Channel, chat.Message) chat.Result {
+		return chat.Result{ResponseStatus: 500, Err: errors.New("unexpected response status 500")}
+	})
+	require.NoError(t, err, "could not deliver messages")
+	require.Equal(t, 1, n)
+
+	err = db.QueryRow(ctx, "SELECT attempts FROM chat_message WHERE id = $1", sent[0].ID).Scan(&attempts)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempts)
+
+	_, err = db.Exec(ctx, "UPDATE chat_message SET next_attempt_at = now() WHERE id = $1", sent[0].ID)
+	require.NoError(t, err)
+
+	n, err = repo.Deliver(ctx, 10, func(context.Context, chat.Channel, chat.Message) chat.Result {
+		return chat.Result{ResponseStatus: 200}
+	})
+	require.NoError(t, err, "could not deliver messages")
+	require.Equal(t, 1, n)
+
+	var status string
+	err = db.QueryRow(ctx, "SELECT status FROM chat_message WHERE id = $1", sent[0].ID).Scan(&status)
+	require.NoError(t, err)
+	require.Equal(t, chat.StatusSent, status)
+
+	rejected := event.Event{ID: 203, Type: event.TypeReservationCreated, ReservationID: "3", RoomID: "3", Data: []byte(`{}`)}
+	err = repo.Enqueue(ctx, rejected, "HQ", []byte(`{"text":"Everest booked"}`))
+	require.NoError(t, err, "could not enqueue event")
+
+	_, err = db.Exec(ctx, "UPDATE chat_message SET rejections = $1 WHERE event_id = $2", chat.MaxRejections-1, rejected.ID)
+	require.NoError(t, err)
+
+	n, err = repo.Deliver(ctx, 10, func(context.Context, chat.Channel, chat.Message) chat.Result {
+		return chat.Result{ResponseStatus: 429, Err: chat.ErrorRateLimited}
+	})
+	require.NoError(t, err, "could not deliver messages")
+	require.Equal(t, 1, n)
+
+	err = db.QueryRow(ctx, "SELECT status FROM chat_message WHERE event_id = $1", rejected.ID).Scan(&status)
+	require.NoError(t, err)
+	require.Equal(t, chat.StatusFailed, status, "expected messages rejected too many times to be given up on")
+
+	err = repo.Delete(ctx, ID)
+	require.NoError(t, err, "could not delete channel")
+
+	_, err = repo.Get(ctx, ID)
+	require.ErrorIs(t, err, chat.ErrorNotFound)
+}
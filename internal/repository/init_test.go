@@ -8,14 +8,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
-
-	_ "github.com/jackc/pgx/v5/stdlib"
-
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 var db *postgres.DB
@@ -65,7 +59,9 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not connect to database: %s", err)
 	}
 
-	startMigration(connURL)
+	if _, err := db.Migrate(context.Background()); err != nil {
+		log.Fatalf("could not apply migration: %s", err)
+	}
 
 	defer func() {
 		if err := pool.Purge(resource); err != nil {
@@ -76,14 +72,3 @@ func TestMain(m *testing.M) {
 
 	m.Run()
 }
-
-func startMigration(databaseUrl string) {
-	migrate, err := migrate.New("file://postgres/migrations", databaseUrl)
-	if err != nil {
-		log.Fatalf("could not create migrate instance: %s", err)
-	}
-
-	if err = migrate.Up(); err != nil {
-		log.Fatalf("could not apply migration: %s", err)
-	}
-}
@@ -0,0 +1,10 @@
+package repository
+
+import "time"
+
+// Workers claim rows in a transaction of their own and send them once it is committed, so that no lock is
+// held while calling out of the service. The result of every row is recorded as soon as it is known.
+//
+// leaseDuration is how long rows claimed by a worker are kept from other workers while it sends them.
+// Rows of a worker stopping half way are picked up again after it.
+const leaseDuration = 5 * time.Minute
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/internal/repository/postgres"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+
+	migrations, err := postgres.Migrations()
+	require.NoError(t, err, "could not read migrations")
+
+	latest := migrations[len(migrations)-1].Version
+
+	require.NoError(t, db.CheckSchema(ctx), "expected the schema to be up to date")
+
+	n, err := db.Migrate(ctx)
+	require.NoError(t, err, "could not migrate an up to date schema")
+	require.Zero(t, n)
+
+	n, err = db.MigrateDown(ctx, 1)
+	require.NoError(t, err, "could not revert migration")
+	require.Equal(t, 1, n)
+
+	version, dirty, err := db.SchemaVersion(ctx)
+	require.NoError(t, err, "could not get schema version")
+	require.Equal(t, latest-1, version)
+	require.False(t, dirty)
+
+	require.ErrorIs(t, db.CheckSchema(ctx), postgres.ErrorSchemaOutdated)
+
+	// instances starting together apply the migration once
+	var (
+		wg      sync.WaitGroup
+		applied [3]int
+		errs    [3]error
+	)
+	for i := range applied {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			applied[i], errs[i] = db.Migrate(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err, "could not migrate concurrently")
+	}
+	require.Equal(t, 1, applied[0]+applied[1]+applied[2])
+
+	require.NoError(t, db.CheckSchema(ctx), "expected the schema to be up to date")
+}
+
+func TestMigrateOverlapping(t *testing.T) {
+	ctx := context.Background()
+
+	migrations, err := postgres.Migrations()
+	require.NoError(t, err, "could not read migrations")
+
+	// back to before the exclusion constraint, which refuses overlapping reservations
+	steps := int(migrations[len(migrations)-1].Version) - 13
+	_, err = db.MigrateDown(ctx, steps)
+	require.NoError(t, err, "could not revert migrations")
+
+	start := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+	for i, ID := range []string{"overlap1", "overlap2", "overlap3"} {
+		_, err = db.Exec(ctx, "INSERT INTO reservation (id, room_id, start_time, end_time) VALUES ($1, 'overlap', $2, $3)",
+			ID, start.Add(time.Duration(i)*time.Hour), start.Add(time.Duration(i+2)*time.Hour))
+		require.NoError(t, err, "could not insert reservation")
+	}
+
+	n, err := db.Migrate(ctx)
+	require.NoError(t, err, "could not migrate overlapping reservations")
+	require.Equal(t, steps, n)
+
+	cancelled := map[string]bool{}
+	rows, err := db.Query(ctx, "SELECT id, cancelled_at IS NOT NULL FROM reservation WHERE room_id = 'overlap'")
+	require.NoError(t, err)
+	for rows.Next() {
+		var ID string
+		var isCancelled bool
+		require.NoError(t, rows.Scan(&ID, &isCancelled))
+		cancelled[ID] = isCancelled
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, map[string]bool{"overlap1": false, "overlap2": true, "overlap3": false}, cancelled,
+		"expected only the reservation overlapping the earlier one cancelled")
+
+	var actor string
+	err = db.QueryRow(ctx, "SELECT actor FROM reservation_audit WHERE reservation_id = 'overlap2' AND action = 'cancel'").Scan(&actor)
+	require.NoError(t, err, "expected the cancellation to be audited")
+	require.Equal(t, "migration", actor)
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/notification"
+	"room-reservation/internal/repository/postgres"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type NotificationRepository struct {
+	db *postgres.DB
+}
+
+func NewNotificationRepository(db *postgres.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+const notificationColumns = "id, kind, reservation_id, event_id, user_id, recipient, status, attempts, last_error, send_at, sent_at, created_at"
+
+func scanNotification(row pgx.Row) (notification.Notification, error) {
+	n := notification.Notification{}
+
+	err := row.Scan(&n.ID, &n.Kind, &n.ReservationID, &n.EventID, &n.UserID, &n.Recipient, &n.Status, &n.Attempts,
+		&n.LastError, &n.SendAt, &n.SentAt, &n.CreatedAt)
+
+	return n, err
+}
+
+func (r *NotificationRepository) GetPreference(ctx context.Context, userID string) (notification.Preference, error) {
+	q := `
+		SELECT user_id, email, opt_out, reminder_minutes, updated_at
+		FROM notification_preference
+		WHERE user_id = $1
+	`
+
+	p := notification.Preference{}
+
+	err := r.db.QueryRow(ctx, q, userID).Scan(&p.UserID, &p.Email, &p.OptOut, &p.ReminderMinutes, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return notification.Preference{}, notification.ErrorNotFound
+		}
+
+		return notification.Preference{}, err
+	}
+
+	return p, nil
+}
+
+func (r *NotificationRepository) SavePreference(ctx context.Context, data notification.Preference) error {
+	q := `
+		INSERT INTO notification_preference (user_id, email, opt_out, reminder_minutes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = excluded.email, opt_out = excluded.opt_out, reminder_minutes = excluded.reminder_minutes, updated_at = now()
+	`
+
+	_, err := r.db.Exec(ctx, q, data.UserID, data.Email, data.OptOut, data.ReminderMinutes)
+
+	return err
+}
+
+func (r *NotificationRepository) Enqueue(ctx context.Context, data notification.Notification) error {
+	q := `
+		INSERT INTO notification (kind, reservation_id, event_id, user_id, recipient, send_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT ON CONSTRAINT notification_event_unique DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, q, data.Kind, data.ReservationID, data.EventID, data.UserID, data.Recipient, data.SendAt)
+
+	return err
+}
+
+func (r *NotificationRepository) ScheduleReminder(ctx context.Context, data notification.Notification) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Reminders queued by the same event are kept, events may be published more than once.
+	skipQuery := `
+		UPDATE notification
+		SET status = 'skipped'
+		WHERE reservation_id = $1 AND kind = 'reminder' AND status = 'pending' AND event_id <> $2
+	`
+
+	if _, err := tx.Exec(ctx, skipQuery, data.ReservationID, data.EventID); err != nil {
+		return err
+	}
+
+	q := `
+		INSERT INTO notification (kind, reservation_id, event_id, user_id, recipient, send_at)
+		VALUES ('reminder', $1, $2, $3, $4, $5)
+		ON CONFLICT ON CONSTRAINT notification_event_unique DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, q, data.ReservationID, data.EventID, data.UserID, data.Recipient, data.SendAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *NotificationRepository) SkipReminders(ctx context.Context, reservationID string) error {
+	q := `
+		UPDATE notification
+		SET status = 'skipped'
+		WHERE reservation_id = $1 AND kind = 'reminder' AND status = 'pending'
+	`
+
+	_, err := r.db.Exec(ctx, q, reservationID)
+
+	return err
+}
+
+// Send claims up to limit due notifications and sends them, see leaseDuration.
+func (r *NotificationRepository) Send(ctx context.Context, limit int, send func(context.Context, notification.Notification) error) (int, error) {
+	batch, err := r.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sentQuery := `
+		UPDATE notification
+		SET status = 'sent', attempts = attempts + 1, last_error = '', sent_at = now()
+		WHERE id = $1
+	`
+
+	skippedQuery := `
+		UPDATE notification
+		SET status = 'skipped', last_error = $1
+		WHERE id = $2
+	`
+
+	failedQuery := `
+		UPDATE notification
+		SET status = $1, attempts = attempts + 1, last_error = $2, send_at = now() + make_interval(secs => $3)
+		WHERE id = $4
+	`
+
+	for _, n := range batch {
+		err := send(ctx, n)
+
+		if err == nil {
+			if _, err := r.db.Exec(ctx, sentQuery, n.ID); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if errors.Is(err, notification.ErrorSkipped) {
+			if _, err := r.db.Exec(ctx, skippedQuery, err.Error(), n.ID); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		attempt := n.Attempts + 1
+
+		status := notification.StatusPending
+		if attempt >= notification.MaxAttempts {
+			status = notification.StatusFailed
+		}
+
+		if _, err := r.db.Exec(ctx, failedQuery, status, err.Error(), notification.Backoff.Delay(attempt).Seconds(), n.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+// claim picks up to limit due notifications and leases them for leaseDuration by postponing them. They are
+// returned with the time they were due.
+func (r *NotificationRepository) claim(ctx context.Context, limit int) ([]notification.Notification, error) {
+	q := `
+		UPDATE notification n
+		SET send_at = now() + make_interval(secs => $2)
+		FROM (
+			SELECT id, send_at
+			FROM notification
+			WHERE status = 'pending'
+			AND send_at <= now()
+			ORDER BY send_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE n.id = due.id
+		RETURNING n.id, n.kind, n.reservation_id, n.event_id, n.user_id, n.recipient, n.status, n.attempts,
+			n.last_error, due.send_at, n.sent_at, n.created_at
+	`
+
+	rows, err := r.db.Query(ctx, q, limit, leaseDuration.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := []notification.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(batch, func(i, j int) bool {
+		if !batch[i].SendAt.Equal(batch[j].SendAt) {
+			return batch[i].SendAt.Before(batch[j].SendAt)
+		}
+
+		return batch[i].ID < batch[j].ID
+	})
+
+	return batch, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/notification"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &NotificationRepository{
+		db: db,
+	}
+
+	_, err := repo.GetPreference(ctx, "alice")
+	require.ErrorIs(t, err, notification.ErrorNotFound)
+
+	err = repo.SavePreference(ctx, notification.Preference{UserID: "alice", Email: "alice@example.com", ReminderMinutes: 30})
+	require.NoError(t, err, "could not save preference")
+
+	err = repo.SavePreference(ctx, notification.Preference{UserID: "alice", Email: "alice@example.com", OptOut: true, ReminderMinutes: 30})
+	require.NoError(t, err, "could not replace preference")
+
+	pref, err := repo.GetPreference(ctx, "alice")
+	require.NoError(t, err, "could not get preference")
+	require.True(t, pref.OptOut)
+	require.Equal(t, 30, pref.ReminderMinutes)
+
+	now := time.Now()
+
+	confirmation := notification.Notification{Kind: notification.KindConfirmation, ReservationID: "n1", EventID: 101,
+		UserID: "alice", Recipient: "alice@example.com", SendAt: now}
+
+	for range 2 {
+		err = repo.Enqueue(ctx, confirmation)
+		require.NoError(t, err, "could not enqueue notification")
+	}
+
+	reminder := notification.Notification{ReservationID: "n1", EventID: 101, UserID: "alice",
+		Recipient: "alice@example.com", SendAt: now.Add(time.Hour)}
+
+	err = repo.ScheduleReminder(ctx, reminder)
+	require.NoError(t, err, "could not schedule reminder")
+
+	reminder.EventID = 102
+	reminder.SendAt = now.Add(-time.Minute)
+
+	for range 2 {
+		err = repo.ScheduleReminder(ctx, reminder)
+		require.NoError(t, err, "could not reschedule reminder")
+	}
+
+	var pending int
+	err = db.QueryRow(ctx, "SELECT count(*) FROM notification WHERE reservation_id = 'n1' AND status = 'pending'").Scan(&pending)
+	require.NoError(t, err)
+	require.Equal(t, 2, pending, "expected the confirmation and the latest reminder to be pending")
+
+	sent := []notification.Notification{}
+
+	n, err := repo.Send(ctx, 10, func(ctx context.Context, n notification.Notification) error {
+		sent = append(sent, n)
+
+		// another notifier running meanwhile leaves the claimed notifications alone
+		count, err := repo.Send(ctx, 10, func(context.Context, notification.Notification) error { return nil })
+		require.NoError(t, err, "could not send notifications")
+		require.Zero(t, count, "expected claimed notifications not to be sent twice")
+
+		if n.Kind == notification.KindReminder {
+			return errors.New("connection refused")
+		}
+
+		return nil
+	})
+	require.NoError(t, err, "could not send notifications")
+	require.Equal(t, 2, n)
+	require.Equal(t, notification.KindConfirmation, sent[0].Kind)
+	require.Equal(t, int64(102), sent[1].EventID)
+
+	var status, lastError string
+	var sendAt time.Time
+	err = db.QueryRow(ctx, "SELECT status, last_error, send_at FROM notification WHERE event_id = 102").Scan(&status, &lastError, &sendAt)
+	require.NoError(t, err)
+	require.Equal(t, notification.StatusPending, status, "expected failed reminder to be retried")
+	require.Equal(t, "connection refused", lastError)
+	require.True(t, sendAt.After(now), "expected retry to be delayed")
+
+	err = repo.SkipReminders(ctx, "n1")
+	require.NoError(t, err, "could not skip reminders")
+
+	err = db.QueryRow(ctx, "SELECT status FROM notification WHERE event_id = 102").Scan(&status)
+	require.NoError(t, err)
+	require.Equal(t, notification.StatusSkipped, status)
+
+	err = db.QueryRow(ctx, "SELECT status FROM notification WHERE event_id = 101 AND kind = 'confirmation'").Scan(&status)
+	require.NoError(t, err)
+	require.Equal(t, notification.StatusSent, status)
+
+	err = repo.Enqueue(ctx, notification.Notification{Kind: notification.KindCancellation, ReservationID: "n1", EventID: 103,
+		UserID: "alice", Recipient: "alice@example.com", SendAt: now})
+	require.NoError(t, err, "could not enqueue notification")
+
+	n, err = repo.Send(ctx, 10, func(context.Context, notification.Notification) error {
+		return notification.ErrorSkipped
+	})
+	require.NoError(t, err, "could not send notifications")
+	require.Equal(t, 1, n)
+
+	err = db.QueryRow(ctx, "SELECT status FROM notification WHERE event_id = 103").Scan(&status)
+	require.NoError(t, err)
+	require.Equal(t, notification.StatusSkipped, status, "expected skipped notification not to be retried")
+}
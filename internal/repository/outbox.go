@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/repository/postgres"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type OutboxRepository struct {
+	db *postgres.DB
+}
+
+func NewOutboxRepository(db *postgres.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+const eventColumns = "id, event_type, reservation_id, room_id, actor, data, created_at, attempts"
+
+func scanEvent(row pgx.Row) (event.Event, error) {
+	e := event.Event{}
+
+	err := row.Scan(&e.ID, &e.Type, &e.ReservationID, &e.RoomID, &e.Actor, &e.Data, &e.OccurredAt, &e.Attempts)
+
+	return e, err
+}
+
+// Relay claims up to limit pending events and publishes them, see leaseDuration.
+func (r *OutboxRepository) Relay(ctx context.Context, limit int, publish func(context.Context, event.Event) error) (int, error) {
+	events, err := r.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	publishedQuery := `
+		UPDATE outbox
+		SET published_at = now(), attempts = attempts + 1, last_error = ''
+		WHERE id = $1
+	`
+
+	failedQuery := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $1, next_attempt_at = now() + make_interval(secs => $2)
+		WHERE id = $3
+	`
+
+	for _, e := range events {
+		if err := publish(ctx, e); err != nil {
+			backoff := event.PublishBackoff.Delay(e.Attempts + 1)
+
+			_, err = r.db.Exec(ctx, failedQuery, err.Error(), backoff.Seconds(), e.ID)
+			if err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if _, err := r.db.Exec(ctx, publishedQuery, e.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// claim picks up to limit pending events and leases them for leaseDuration. Only the oldest pending event of
+// every reservation is picked, later ones wait until it is published.
+func (r *OutboxRepository) claim(ctx context.Context, limit int) ([]event.Event, error) {
+	q := `
+		UPDATE outbox
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM outbox o
+			WHERE published_at IS NULL
+			AND next_attempt_at <= now()
+			AND NOT EXISTS (
+				SELECT 1
+				FROM outbox p
+				WHERE p.reservation_id = o.reservation_id
+				AND p.published_at IS NULL
+				AND p.id < o.id
+			)
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + eventColumns
+
+	rows, err := r.db.Query(ctx, q, limit, leaseDuration.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []event.Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events, nil
+}
+
+// Prune deletes events published before publishedBefore, pending events are kept whatever their age.
+func (r *OutboxRepository) Prune(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	q := `
+		DELETE FROM outbox
+		WHERE published_at < $1
+	`
+
+	result, err := r.db.Exec(ctx, q, publishedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// resumeOverlap is how long before an event other events are looked for when resuming after it. Ids are taken
+// when events are recorded, so an event with a lower id may commit after it as long as its transaction runs.
+const resumeOverlap = time.Minute
+
+// Since lists events after the event with ID afterID, of a room or of every room when roomID is empty. Events
+// with lower ids recorded up to resumeOverlap before it are listed too, in case they were committed after it.
+func (r *OutboxRepository) Since(ctx context.Context, afterID int64, roomID string, limit int) ([]event.Event, error) {
+	q := `
+		SELECT ` + eventColumns + `
+		FROM outbox
+		WHERE (
+			id > $1
+			OR (id < $1 AND created_at >= (SELECT created_at FROM outbox WHERE id = $1) - make_interval(secs => $4))
+		)
+		AND ($2 = '' OR room_id = $2)
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, q, afterID, roomID, limit, resumeOverlap.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []event.Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Listen holds a connection listening for events committed by any instance of the service.
+func (r *OutboxRepository) Listen(ctx context.Context, fn func(event.Event)) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+eventChannel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// the connection may be left in the middle of waiting, don't put it back into the pool
+			conn.Conn().Close(context.Background())
+			return err
+		}
+
+		var e event.Event
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			continue
+		}
+
+		fn(e)
+	}
+}
+
+// eventChannel is notified with every event recorded in the outbox once its transaction commits.
+const eventChannel = "reservation_events"
+
+// recordEvent adds an event about the reservation to the outbox within tx, the actor is taken from ctx.
+func recordEvent(ctx context.Context, tx pgx.Tx, eventType string, data reservation.Reservation) error {
+	snapshot, err := snapshot(&data)
+	if err != nil {
+		return err
+	}
+
+	e := event.Event{
+		Type:          eventType,
+		ReservationID: data.ID,
+		RoomID:        data.RoomID,
+		Actor:         audit.ActorFromContext(ctx).ID,
+		Data:          snapshot,
+	}
+
+	q := `
+		INSERT INTO outbox (event_type, reservation_id, room_id, actor, data)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	args := []any{e.Type, e.ReservationID, e.RoomID, e.Actor, e.Data}
+
+	err = tx.QueryRow(ctx, q, args...).Scan(&e.ID, &e.OccurredAt)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "SELECT pg_notify($1, $2)", eventChannel, string(payload))
+	return err
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &ReservationRepository{
+		db: db,
+	}
+
+	outbox := &OutboxRepository{
+		db: db,
+	}
+
+	// publish whatever earlier tests left in the outbox
+	_, err := outbox.Relay(ctx, 1000, func(context.Context, event.Event) error { return nil })
+	require.NoError(t, err, "could not relay events")
+
+	ID, err := repo.Create(ctx, reservation.Reservation{
+		RoomID:    "outbox",
+		StartTime: time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 8, 29, 14, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err, "could not create reservation")
+
+	err = repo.Cancel(ctx, ID, "")
+	require.NoError(t, err, "could not cancel reservation")
+
+	var published []event.Event
+
+	n, err := outbox.Relay(ctx, 10, func(ctx context.Context, e event.Event) error {
+		// another relay running meanwhile leaves the claimed event alone
+		n, err := outbox.Relay(ctx, 10, func(context.Context, event.Event) error { return nil })
+		require.NoError(t, err, "could not relay events")
+		require.Zero(t, n, "expected claimed events not to be relayed twice")
+
+		return errors.New("unavailable")
+	})
+	require.NoError(t, err, "could not relay events")
+	require.Equal(t, 1, n, "expected only the first event of the reservation to be relayed")
+
+	_, err = db.Exec(ctx, "UPDATE outbox SET next_attempt_at = now() WHERE reservation_id = $1", ID)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = outbox.Relay(ctx, 10, func(ctx context.Context, e event.Event) error {
+			published = append(published, e)
+			return nil
+		})
+		require.NoError(t, err, "could not relay events")
+	}
+
+	require.Len(t, published, 2, "expected every event to be published")
+	require.Equal(t, event.TypeReservationCreated, published[0].Type)
+	require.Equal(t, event.TypeReservationCancelled, published[1].Type)
+	require.Equal(t, 1, published[0].Attempts, "expected failed attempt to be counted")
+
+	events, err := outbox.Since(ctx, published[0].ID, "outbox", 10)
+	require.NoError(t, err, "could not list events")
+	require.Len(t, events, 1, "expected events after the given one")
+	require.Equal(t, published[1].ID, events[0].ID)
+
+	events, err = outbox.Since(ctx, published[1].ID, "outbox", 10)
+	require.NoError(t, err, "could not list events")
+	require.Len(t, events, 1, "expected events recorded just before the given one to be listed again")
+	require.Equal(t, published[0].ID, events[0].ID)
+
+	pruned, err := outbox.Prune(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err, "could not prune events")
+	require.GreaterOrEqual(t, pruned, int64(2), "expected published events to be pruned")
+
+	events, err = outbox.Since(ctx, 0, "outbox", 10)
+	require.NoError(t, err, "could not list events")
+	require.Empty(t, events, "expected pruned events to be deleted")
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	acquiredConnsDesc = prometheus.NewDesc("db_pool_acquired_connections",
+		"Number of connections currently in use.", nil, nil)
+	idleConnsDesc = prometheus.NewDesc("db_pool_idle_connections",
+		"Number of connections currently idle.", nil, nil)
+	totalConnsDesc = prometheus.NewDesc("db_pool_total_connections",
+		"Number of open connections, including ones being opened.", nil, nil)
+	maxConnsDesc = prometheus.NewDesc("db_pool_max_connections",
+		"Maximum number of connections of the pool.", nil, nil)
+	acquiresDesc = prometheus.NewDesc("db_pool_acquires_total",
+		"Number of connections acquired from the pool.", nil, nil)
+	emptyAcquiresDesc = prometheus.NewDesc("db_pool_empty_acquires_total",
+		"Number of acquires that waited for a connection as none was idle.", nil, nil)
+	canceledAcquiresDesc = prometheus.NewDesc("db_pool_canceled_acquires_total",
+		"Number of acquires canceled while waiting for a connection.", nil, nil)
+	acquireWaitDesc = prometheus.NewDesc("db_pool_acquire_wait_seconds_total",
+		"Time spent waiting for connections to be acquired.", nil, nil)
+)
+
+// Collector reports statistics of the connection pool to Prometheus.
+func (db *DB) Collector() prometheus.Collector {
+	return poolCollector{db}
+}
+
+type poolCollector struct {
+	db *DB
+}
+
+func (c poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		acquiredConnsDesc, idleConnsDesc, totalConnsDesc, maxConnsDesc,
+		acquiresDesc, emptyAcquiresDesc, canceledAcquiresDesc, acquireWaitDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.db.Stat()
+
+	ch <- prometheus.MustNewConstMetric(acquiredConnsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(idleConnsDesc, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(totalConnsDesc, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(maxConnsDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(acquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(emptyAcquiresDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(canceledAcquiresDesc, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(acquireWaitDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
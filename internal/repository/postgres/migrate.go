@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration changes the schema with Up, and reverts the change with Down.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationLockID identifies the advisory lock held while migrating, so that instances starting at the same time
+// apply migrations one after the other.
+const migrationLockID = 8_231_420_117
+
+var ErrorSchemaDirty error = errors.New("database schema is dirty")
+var ErrorSchemaOutdated error = errors.New("database schema is out of date")
+
+// Migrations lists the migrations embedded in the binary ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+
+	for _, file := range files {
+		match := migrationFile.FindStringSubmatch(file[len("migrations/"):])
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", file)
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s: %w", file, err)
+		}
+
+		b, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[m.Version] = m
+		}
+
+		if match[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	res := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have an up and a down file", m.Version, m.Name)
+		}
+
+		res = append(res, *m)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version < res[j].Version
+	})
+
+	return res, nil
+}
+
+// SchemaVersion returns the version of the last applied migration, 0 when none was applied. The schema is dirty
+// when a migration failed half way, which only happens with migrations applied by other tools.
+func (db *DB) SchemaVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	err = db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+
+	var pgErr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "42P01") {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to get schema version: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// CheckSchema fails with ErrorSchemaOutdated when migrations are pending and ErrorSchemaDirty when the schema is
+// dirty. A schema newer than the binary passes, so that instances of the previous release keep running during
+// a rollout.
+func (db *DB) CheckSchema(ctx context.Context) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := db.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("%w at version %d", ErrorSchemaDirty, version)
+	}
+
+	if latest := migrations[len(migrations)-1].Version; version < latest {
+		return fmt.Errorf("%w: at version %d, %d is required", ErrorSchemaOutdated, version, latest)
+	}
+
+	return nil
+}
+
+// Migrate applies pending migrations and returns how many were applied. Each migration is applied in a transaction
+// along with the new version, so a failing migration leaves the schema as it was.
+func (db *DB) Migrate(ctx context.Context) (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+
+	err = db.withMigrationLock(ctx, func(conn *pgxpool.Conn, version uint) error {
+		for _, m := range migrations {
+			if m.Version <= version {
+				continue
+			}
+
+			if err := migrate(ctx, conn, m.Up, m.Version); err != nil {
+				return fmt.Errorf("unable to apply migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// MigrateDown reverts the last steps migrations and returns how many were reverted.
+func (db *DB) MigrateDown(ctx context.Context, steps int) (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+
+	err = db.withMigrationLock(ctx, func(conn *pgxpool.Conn, version uint) error {
+		for i := len(migrations) - 1; i >= 0 && reverted < steps; i-- {
+			m := migrations[i]
+			if m.Version > version {
+				continue
+			}
+
+			var previous uint
+			if i > 0 {
+				previous = migrations[i-1].Version
+			}
+
+			if err := migrate(ctx, conn, m.Down, previous); err != nil {
+				return fmt.Errorf("unable to revert migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// withMigrationLock calls fn with the current version while holding the migration lock.
+func (db *DB) withMigrationLock(ctx context.Context, fn func(conn *pgxpool.Conn, version uint) error) error {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// advisory locks are held by the session, so the lock is taken and released on the same connection
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("unable to lock migrations: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("unable to create schema_migrations: %w", err)
+	}
+
+	var (
+		version uint
+		dirty   bool
+	)
+
+	err = conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("unable to get schema version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("%w at version %d, it must be fixed by hand", ErrorSchemaDirty, version)
+	}
+
+	return fn(conn, version)
+}
+
+// migrate runs a migration and sets the version in a transaction, version 0 means no migration is applied.
+func migrate(ctx context.Context, conn *pgxpool.Conn, sql string, version uint) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+			return err
+		}
+
+		if version == 0 {
+			return nil
+		}
+
+		_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", version)
+		return err
+	})
+}
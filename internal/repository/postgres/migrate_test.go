@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err, "could not read migrations")
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		require.Equal(t, uint(i+1), m.Version, "expected consecutive versions")
+		require.NotEmpty(t, m.Name)
+		require.NotEmpty(t, m.Up)
+		require.NotEmpty(t, m.Down)
+	}
+}
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+	key VARCHAR PRIMARY KEY,
+	tokens DOUBLE PRECISION NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reservation_audit;
+
+DROP FUNCTION IF EXISTS reservation_audit_append_only;
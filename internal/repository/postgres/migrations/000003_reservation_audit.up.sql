@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS reservation_audit (
+	id BIGSERIAL PRIMARY KEY,
+	reservation_id VARCHAR(12) NOT NULL,
+	action VARCHAR NOT NULL,
+	actor VARCHAR NOT NULL,
+	request_id VARCHAR NOT NULL DEFAULT '',
+	before JSONB,
+	after JSONB,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS reservation_audit_reservation_id_idx ON reservation_audit(reservation_id);
+
+CREATE INDEX IF NOT EXISTS reservation_audit_created_at_idx ON reservation_audit(created_at);
+
+CREATE OR REPLACE FUNCTION reservation_audit_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'reservation_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reservation_audit_append_only
+BEFORE UPDATE OR DELETE ON reservation_audit
+FOR EACH ROW EXECUTE FUNCTION reservation_audit_append_only();
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS reservation_cancelled_at_idx;
+
+ALTER TABLE reservation
+	DROP COLUMN IF EXISTS cancelled_at,
+	DROP COLUMN IF EXISTS cancel_reason,
+	DROP COLUMN IF EXISTS cancelled_by;
@@ -0,0 +1,6 @@
+ALTER TABLE reservation
+	ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS cancel_reason VARCHAR NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS cancelled_by VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS reservation_cancelled_at_idx ON reservation(cancelled_at) WHERE cancelled_at IS NOT NULL;
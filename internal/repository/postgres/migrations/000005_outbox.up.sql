@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox (
+	id BIGSERIAL PRIMARY KEY,
+	event_type VARCHAR NOT NULL,
+	reservation_id VARCHAR(12) NOT NULL,
+	room_id VARCHAR NOT NULL,
+	actor VARCHAR NOT NULL,
+	data JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	published_at TIMESTAMPTZ,
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	last_error VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox(reservation_id, id) WHERE published_at IS NULL;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS webhook_subscription (
+	id VARCHAR(12) PRIMARY KEY,
+	url VARCHAR NOT NULL,
+	event_types VARCHAR[] NOT NULL DEFAULT '{}',
+	room_ids VARCHAR[] NOT NULL DEFAULT '{}',
+	secret VARCHAR NOT NULL,
+	failure_count INT NOT NULL DEFAULT 0,
+	disabled_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+	id BIGSERIAL PRIMARY KEY,
+	subscription_id VARCHAR(12) NOT NULL REFERENCES webhook_subscription(id) ON DELETE CASCADE,
+	event_id BIGINT NOT NULL,
+	event_type VARCHAR NOT NULL,
+	payload JSONB NOT NULL,
+	status VARCHAR NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	response_status INT NOT NULL DEFAULT 0,
+	last_error VARCHAR NOT NULL DEFAULT '',
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	delivered_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT webhook_delivery_event_unique UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_pending_idx ON webhook_delivery(next_attempt_at) WHERE status = 'pending';
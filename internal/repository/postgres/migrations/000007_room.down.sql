@@ -0,0 +1 @@
+DROP TABLE IF EXISTS room;
\ No newline at end of file
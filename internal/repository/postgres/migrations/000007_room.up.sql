@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS room (
+	id VARCHAR PRIMARY KEY,
+	name VARCHAR NOT NULL,
+	building VARCHAR NOT NULL DEFAULT '',
+	capacity INT NOT NULL DEFAULT 0,
+	CONSTRAINT capacity_not_negative CHECK (capacity >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS room_building_idx ON room(building);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS calendar_feed;
+
+DROP INDEX IF EXISTS reservation_user_id_idx;
+
+ALTER TABLE reservation
+	DROP COLUMN IF EXISTS user_id,
+	DROP COLUMN IF EXISTS sequence,
+	DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,15 @@
+ALTER TABLE reservation
+	ADD COLUMN IF NOT EXISTS user_id VARCHAR NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS sequence INT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS reservation_user_id_idx ON reservation(user_id) WHERE user_id <> '';
+
+CREATE TABLE IF NOT EXISTS calendar_feed (
+	token VARCHAR PRIMARY KEY,
+	room_id VARCHAR NOT NULL DEFAULT '',
+	user_id VARCHAR NOT NULL DEFAULT '',
+	created_by VARCHAR NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT feed_of_room_or_user CHECK ((room_id = '') <> (user_id = ''))
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS reservation_ical_uid_idx;
+
+ALTER TABLE reservation
+	DROP COLUMN IF EXISTS ical_uid;
@@ -0,0 +1,4 @@
+ALTER TABLE reservation
+	ADD COLUMN IF NOT EXISTS ical_uid VARCHAR NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS reservation_ical_uid_idx ON reservation(ical_uid) WHERE ical_uid <> '';
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS notification_preference (
+	user_id VARCHAR PRIMARY KEY,
+	email VARCHAR NOT NULL DEFAULT '',
+	opt_out BOOLEAN NOT NULL DEFAULT false,
+	reminder_minutes INT NOT NULL DEFAULT 15,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT notification_preference_reminder_check CHECK (reminder_minutes >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS notification (
+	id BIGSERIAL PRIMARY KEY,
+	kind VARCHAR NOT NULL,
+	reservation_id VARCHAR(12) NOT NULL,
+	event_id BIGINT NOT NULL,
+	user_id VARCHAR NOT NULL,
+	recipient VARCHAR NOT NULL,
+	status VARCHAR NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	last_error VARCHAR NOT NULL DEFAULT '',
+	send_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	sent_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT notification_event_unique UNIQUE (event_id, kind)
+);
+
+CREATE INDEX IF NOT EXISTS notification_pending_idx ON notification(send_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS notification_reservation_id_idx ON notification(reservation_id);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS chat_channel (
+	id VARCHAR(12) PRIMARY KEY,
+	name VARCHAR NOT NULL,
+	url VARCHAR NOT NULL,
+	room_ids VARCHAR[] NOT NULL DEFAULT '{}',
+	buildings VARCHAR[] NOT NULL DEFAULT '{}',
+	event_types VARCHAR[] NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS chat_message (
+	id BIGSERIAL PRIMARY KEY,
+	channel_id VARCHAR(12) NOT NULL REFERENCES chat_channel(id) ON DELETE CASCADE,
+	event_id BIGINT NOT NULL,
+	event_type VARCHAR NOT NULL,
+	payload JSONB NOT NULL,
+	status VARCHAR NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	response_status INT NOT NULL DEFAULT 0,
+	last_error VARCHAR NOT NULL DEFAULT '',
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	sent_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT chat_message_event_unique UNIQUE (channel_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS chat_message_pending_idx ON chat_message(next_attempt_at) WHERE status = 'pending';
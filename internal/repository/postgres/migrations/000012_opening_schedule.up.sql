@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS opening_schedule (
+	scope VARCHAR NOT NULL,
+	key VARCHAR NOT NULL,
+	time_zone VARCHAR NOT NULL,
+	weekly JSONB NOT NULL,
+	exceptions JSONB NOT NULL DEFAULT '[]',
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (scope, key)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE rate_limit_bucket ADD COLUMN IF NOT EXISTS full_at TIMESTAMP NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS rate_limit_bucket_full_at_idx ON rate_limit_bucket (full_at);
@@ -0,0 +1 @@
+ALTER TABLE reservation DROP CONSTRAINT IF EXISTS reservation_no_overlap;
@@ -0,0 +1,35 @@
+ALTER TABLE reservation DROP CONSTRAINT IF EXISTS reservation_no_overlap;
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+DO $$
+DECLARE
+	overlapping reservation%ROWTYPE;
+BEGIN
+	LOOP
+		SELECT later.* INTO overlapping
+		FROM reservation later
+		JOIN reservation earlier ON earlier.room_id = later.room_id
+			AND earlier.cancelled_at IS NULL
+			AND (earlier.start_time, earlier.id) < (later.start_time, later.id)
+			AND tsrange(earlier.start_time, earlier.end_time) && tsrange(later.start_time, later.end_time)
+		WHERE later.cancelled_at IS NULL
+		ORDER BY later.start_time, later.id
+		LIMIT 1;
+
+		EXIT WHEN NOT FOUND;
+
+		UPDATE reservation
+		SET cancelled_at = now(), cancel_reason = 'overlaps another reservation', cancelled_by = 'migration',
+			sequence = sequence + 1, updated_at = now()
+		WHERE id = overlapping.id;
+
+		INSERT INTO reservation_audit (reservation_id, action, actor, before, after)
+		SELECT id, 'cancel', 'migration', to_jsonb(overlapping), to_jsonb(reservation)
+		FROM reservation
+		WHERE id = overlapping.id;
+	END LOOP;
+END $$;
+
+ALTER TABLE reservation ADD CONSTRAINT reservation_no_overlap
+	EXCLUDE USING gist (room_id WITH =, tsrange(start_time, end_time) WITH &&)
+	WHERE (cancelled_at IS NULL);
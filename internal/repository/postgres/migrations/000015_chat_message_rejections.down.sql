@@ -0,0 +1 @@
+ALTER TABLE chat_message DROP COLUMN IF EXISTS rejections;
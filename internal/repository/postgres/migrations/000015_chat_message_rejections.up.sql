@@ -0,0 +1 @@
+ALTER TABLE chat_message ADD COLUMN IF NOT EXISTS rejections INT NOT NULL DEFAULT 0;
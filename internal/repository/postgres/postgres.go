@@ -12,7 +12,43 @@ type DB struct {
 	*pgxpool.Pool
 }
 
-func New(ctx context.Context, connString string) (*DB, error) {
+// PoolConfig sizes the connection pool, zero fields keep the defaults of 5 connections at most, closed after an hour
+// and checked every minute.
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+type Option func(*pgxpool.Config)
+
+func WithPoolConfig(c PoolConfig) Option {
+	return func(config *pgxpool.Config) {
+		if c.MaxConns > 0 {
+			config.MaxConns = c.MaxConns
+		}
+
+		if c.MinConns > 0 {
+			config.MinConns = c.MinConns
+		}
+
+		if c.MaxConnLifetime > 0 {
+			config.MaxConnLifetime = c.MaxConnLifetime
+		}
+
+		if c.MaxConnIdleTime > 0 {
+			config.MaxConnIdleTime = c.MaxConnIdleTime
+		}
+
+		if c.HealthCheckPeriod > 0 {
+			config.HealthCheckPeriod = c.HealthCheckPeriod
+		}
+	}
+}
+
+func New(ctx context.Context, connString string, opts ...Option) (*DB, error) {
 	// postgres://{username}:{password}@{localhost}:{5432}/{dbname}?sslmode=disable
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
@@ -22,6 +58,11 @@ func New(ctx context.Context, connString string) (*DB, error) {
 	config.MaxConns = 5
 	config.MaxConnLifetime = time.Hour
 	config.HealthCheckPeriod = time.Minute
+	config.ConnConfig.Tracer = queryTracer{}
+
+	for _, opt := range opts {
+		opt(config)
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
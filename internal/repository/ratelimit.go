@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/internal/repository/postgres"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/router"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps token buckets in postgres so that every instance of the service shares them.
+type RateLimitStore struct {
+	db *postgres.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewRateLimitStore(db *postgres.DB) *RateLimitStore {
+	return &RateLimitStore{
+		db: db,
+	}
+}
+
+const rateLimitSweepInterval = time.Minute
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit router.Limit) (router.Result, error) {
+	if s.sweepDue() {
+		if err := s.Sweep(ctx); err != nil {
+			log.LoggerFromContext(ctx).Err(err).Caller().Msg("could not sweep rate limit buckets")
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return router.Result{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	insertQuery := `
+		INSERT INTO rate_limit_bucket (key, tokens, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (key) DO NOTHING
+	`
+
+	_, err = tx.Exec(ctx, insertQuery, key, limit.Burst)
+	if err != nil {
+		return router.Result{}, err
+	}
+
+	selectQuery := `
+		SELECT tokens, EXTRACT(EPOCH FROM now() - updated_at)
+		FROM rate_limit_bucket
+		WHERE key = $1
+		FOR UPDATE
+	`
+
+	var tokens, elapsed float64
+
+	err = tx.QueryRow(ctx, selectQuery, key).Scan(&tokens, &elapsed)
+	if err != nil {
+		return router.Result{}, err
+	}
+
+	tokens, res := limit.Take(tokens, time.Duration(elapsed*float64(time.Second)))
+
+	updateQuery := `
+		UPDATE rate_limit_bucket
+		SET tokens = $1, updated_at = now(), full_at = now() + make_interval(secs => $2)
+		WHERE key = $3
+	`
+
+	_, err = tx.Exec(ctx, updateQuery, tokens, res.Reset.Seconds(), key)
+	if err != nil {
+		return router.Result{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return router.Result{}, err
+	}
+
+	return res, nil
+}
+
+// sweepDue tells whether buckets were last swept long enough ago, at most one caller at a time is told so.
+func (s *RateLimitStore) sweepDue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) <= rateLimitSweepInterval {
+		return false
+	}
+
+	s.lastSweep = now
+	return true
+}
+
+// Sweep deletes the buckets that have refilled since they were last used, they are the same as new ones.
+func (s *RateLimitStore) Sweep(ctx context.Context) error {
+	q := `
+		DELETE FROM rate_limit_bucket
+		WHERE full_at <= now()
+	`
+
+	_, err := s.db.Exec(ctx, q)
+	return err
+}
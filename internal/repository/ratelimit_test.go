@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/pkg/router"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+
+	store := NewRateLimitStore(db)
+
+	limit := router.Limit{Rate: 0.001, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		res, err := store.Take(ctx, "test", limit)
+		require.NoError(t, err, "could not take token")
+		require.True(t, res.Allowed, "expected request to be allowed")
+	}
+
+	res, err := store.Take(ctx, "test", limit)
+	require.NoError(t, err, "could not take token")
+	require.False(t, res.Allowed, "expected request to be limited")
+	require.NotZero(t, res.RetryAfter, "expected retry after to be set")
+
+	_, err = store.Take(ctx, "test-full", router.Limit{Rate: 1000, Burst: 1})
+	require.NoError(t, err, "could not take token")
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, store.Sweep(ctx), "could not sweep buckets")
+
+	var kept bool
+	err = db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM rate_limit_bucket WHERE key = 'test')").Scan(&kept)
+	require.NoError(t, err)
+	require.True(t, kept, "expected buckets still refilling to be kept")
+
+	err = db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM rate_limit_bucket WHERE key = 'test-full')").Scan(&kept)
+	require.NoError(t, err)
+	require.False(t, kept, "expected full buckets to be deleted")
+}
@@ -6,43 +6,154 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/internal/domain/event"
 	"room-reservation/internal/domain/reservation"
 	"room-reservation/internal/repository/postgres"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type ReservationRepository struct {
 	db *postgres.DB
 }
 
-func NewReservationRepository(ctx context.Context, connString string) (*ReservationRepository, error) {
-	db, err := postgres.New(ctx, connString)
+func NewReservationRepository(db *postgres.DB) *ReservationRepository {
+	return &ReservationRepository{
+		db: db,
+	}
+}
+
+const reservationColumns = "id, room_id, user_id, start_time, end_time, cancelled_at, cancel_reason, cancelled_by, sequence, updated_at, ical_uid"
+
+func scanReservation(row pgx.Row) (reservation.Reservation, error) {
+	res := reservation.Reservation{}
+
+	err := row.Scan(&res.ID, &res.RoomID, &res.UserID, &res.StartTime, &res.EndTime, &res.CancelledAt, &res.CancelReason, &res.CancelledBy, &res.Sequence, &res.UpdatedAt, &res.ICalUID)
+
+	return res, err
+}
+
+func (r *ReservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	defer tx.Rollback(ctx)
 
-	repo := &ReservationRepository{
-		db: db,
+	ID, err := r.create(ctx, tx, data)
+	if err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", err
 	}
 
-	return repo, nil
+	return ID, nil
 }
 
-func (r *ReservationRepository) Close() {
-	if r.db != nil {
-		r.db.Close()
+func (r *ReservationRepository) create(ctx context.Context, tx pgx.Tx, data reservation.Reservation) (string, error) {
+	if err := r.checkImported(ctx, tx, data.ICalUID); err != nil {
+		return "", err
+	}
+
+	if err := r.checkOverlap(ctx, tx, data); err != nil {
+		return "", err
+	}
+
+	insertQuery := `
+		INSERT INTO reservation (id, room_id, user_id, start_time, end_time, ical_uid)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + reservationColumns
+
+	args := []any{generateID(), data.RoomID, data.UserID, data.StartTime, data.EndTime, data.ICalUID}
+
+	data, err := scanReservation(tx.QueryRow(ctx, insertQuery, args...))
+	if err != nil {
+		return "", overlapError(err)
+	}
+
+	if err = recordAudit(ctx, tx, audit.ActionCreate, data.ID, nil, &data); err != nil {
+		return "", err
+	}
+
+	if err = recordEvent(ctx, tx, event.TypeReservationCreated, data); err != nil {
+		return "", err
 	}
+
+	return data.ID, nil
 }
 
-func (r *ReservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+// Import creates every reservation in a savepoint of its own so that the failing ones don't abort the others.
+func (r *ReservationRepository) Import(ctx context.Context, data []reservation.Reservation, dryRun bool) ([]reservation.ImportResult, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
+	results := make([]reservation.ImportResult, len(data))
+
+	for i, d := range data {
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ID, err := r.create(ctx, sp, d)
+		if err != nil {
+			if err := sp.Rollback(ctx); err != nil {
+				return nil, err
+			}
+
+			results[i].Err = err
+			continue
+		}
+
+		if err = sp.Commit(ctx); err != nil {
+			return nil, err
+		}
+
+		results[i].ID = ID
+	}
+
+	if dryRun {
+		return results, nil
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// checkImported checks whether a calendar event has been imported already.
+func (r *ReservationRepository) checkImported(ctx context.Context, tx pgx.Tx, uid string) error {
+	if uid == "" {
+		return nil
+	}
+
+	var exists bool
+	q := `SELECT EXISTS (SELECT 1 FROM reservation WHERE ical_uid = $1)`
+
+	if err := tx.QueryRow(ctx, q, uid).Scan(&exists); err != nil {
+		return err
+	}
+
+	if exists {
+		return reservation.ErrorImported
+	}
+
+	return nil
+}
+
+// checkOverlap checks whether data overlaps with another active reservation of the room.
+func (r *ReservationRepository) checkOverlap(ctx context.Context, tx pgx.Tx, data reservation.Reservation) error {
 	var existingID string
 	checkOverlapQuery := `
 		SELECT id 
@@ -50,50 +161,66 @@ func (r *ReservationRepository) Create(ctx context.Context, data reservation.Res
 		WHERE room_id = @roomID 
 		AND start_time < @endTime
 		AND end_time > @startTime
+		AND cancelled_at IS NULL
+		AND id <> @ID
+		LIMIT 1
 	`
 
-	err = tx.QueryRow(ctx, checkOverlapQuery, pgx.NamedArgs{
+	err := tx.QueryRow(ctx, checkOverlapQuery, pgx.NamedArgs{
 		"roomID":    data.RoomID,
 		"startTime": data.StartTime,
 		"endTime":   data.EndTime,
+		"ID":        data.ID,
 	}).Scan(&existingID)
 	if err != nil && err != pgx.ErrNoRows {
-		return "", err
+		return err
 	}
 
 	if existingID != "" {
-		return "", reservation.ErrorOverlaps
+		return reservation.ErrorOverlaps
 	}
 
-	insertQuery := `
-		INSERT INTO reservation (id, room_id, start_time, end_time)
-		VALUES ($1, $2, $3, $4)
-	`
-	data.ID = r.generateID()
-	args := []any{data.ID, data.RoomID, data.StartTime, data.EndTime}
-
-	_, err = tx.Exec(ctx, insertQuery, args...)
-	if err != nil {
-		return "", err
-	}
+	return nil
+}
 
-	if err = tx.Commit(ctx); err != nil {
-		return "", err
+// overlapError turns violations of the reservation_no_overlap constraint into reservation.ErrorOverlaps. The
+// constraint catches the overlaps checkOverlap misses when concurrent transactions take the same slot.
+func overlapError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23P01" {
+		return reservation.ErrorOverlaps
 	}
 
-	return data.ID, nil
+	return err
 }
 
 func (r *ReservationRepository) Get(ctx context.Context, ID string) (reservation.Reservation, error) {
 	q := `
-		SELECT id, room_id, start_time, end_time
+		SELECT ` + reservationColumns + `
 		FROM reservation
 		WHERE id = $1
 	`
 
-	res := reservation.Reservation{}
+	res, err := scanReservation(r.db.QueryRow(ctx, q, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return reservation.Reservation{}, reservation.ErrorNotFound
+		}
+
+		return reservation.Reservation{}, err
+	}
 
-	err := r.db.QueryRow(ctx, q, ID).Scan(&res.ID, &res.RoomID, &res.StartTime, &res.EndTime)
+	return res, nil
+}
+
+func (r *ReservationRepository) GetByICalUID(ctx context.Context, uid string) (reservation.Reservation, error) {
+	q := `
+		SELECT ` + reservationColumns + `
+		FROM reservation
+		WHERE ical_uid = $1
+	`
+
+	res, err := scanReservation(r.db.QueryRow(ctx, q, uid))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return reservation.Reservation{}, reservation.ErrorNotFound
@@ -105,14 +232,72 @@ func (r *ReservationRepository) Get(ctx context.Context, ID string) (reservation
 	return res, nil
 }
 
-func (r *ReservationRepository) List(ctx context.Context, roomID string) ([]reservation.Reservation, error) {
+func (r *ReservationRepository) List(ctx context.Context, roomID string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
 	q := `
-		SELECT id, room_id, start_time, end_time
+		SELECT ` + reservationColumns + `
 		FROM reservation
 		WHERE room_id = $1
 	`
+	q, args := listFilter(q, []any{roomID}, opts)
 
-	rows, err := r.db.Query(ctx, q, roomID)
+	reservations, err := r.query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reservations) == 0 {
+		return nil, reservation.ErrorNotFoundForRoom
+	}
+
+	return reservations, nil
+}
+
+// ListForUser lists reservations made by a user, ordered by start time.
+func (r *ReservationRepository) ListForUser(ctx context.Context, userID string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	q := `
+		SELECT ` + reservationColumns + `
+		FROM reservation
+		WHERE user_id = $1
+	`
+	q, args := listFilter(q, []any{userID}, opts)
+	q += " ORDER BY start_time"
+
+	return r.query(ctx, q, args...)
+}
+
+func (r *ReservationRepository) ListForRooms(ctx context.Context, roomIDs []string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	q := `
+		SELECT ` + reservationColumns + `
+		FROM reservation
+		WHERE room_id = ANY($1)
+	`
+	q, args := listFilter(q, []any{roomIDs}, opts)
+	q += " ORDER BY start_time"
+
+	return r.query(ctx, q, args...)
+}
+
+// listFilter adds the conditions of opts to q, numbering their arguments after args.
+func listFilter(q string, args []any, opts reservation.ListOptions) (string, []any) {
+	if !opts.IncludeCancelled {
+		q += " AND cancelled_at IS NULL"
+	}
+
+	if !opts.From.IsZero() {
+		args = append(args, opts.From)
+		q += fmt.Sprintf(" AND end_time > $%d", len(args))
+	}
+
+	if !opts.To.IsZero() {
+		args = append(args, opts.To)
+		q += fmt.Sprintf(" AND start_time < $%d", len(args))
+	}
+
+	return q, args
+}
+
+func (r *ReservationRepository) query(ctx context.Context, q string, args ...any) ([]reservation.Reservation, error) {
+	rows, err := r.db.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -120,9 +305,7 @@ func (r *ReservationRepository) List(ctx context.Context, roomID string) ([]rese
 
 	reservations := []reservation.Reservation{}
 	for rows.Next() {
-		var res reservation.Reservation
-
-		err := rows.Scan(&res.ID, &res.RoomID, &res.StartTime, &res.EndTime)
+		res, err := scanReservation(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -134,50 +317,230 @@ func (r *ReservationRepository) List(ctx context.Context, roomID string) ([]rese
 		return nil, err
 	}
 
-	if len(reservations) == 0 {
-		return nil, reservation.ErrorNotFoundForRoom
+	return reservations, nil
+}
+
+func (r *ReservationRepository) Update(ctx context.Context, ID string, data reservation.Reservation) error {
+	sets, args := r.prepareArgs(data)
+	if len(sets) == 0 {
+		return nil
 	}
 
-	return reservations, nil
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.getForUpdate(ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	if before.Cancelled() {
+		return reservation.ErrorCancelled
+	}
+
+	// a single changed time may end up on the wrong side of the other one
+	merged := before.Merge(data)
+	if !merged.StartTime.Before(merged.EndTime) {
+		return reservation.ErrorInvalidTime
+	}
+
+	if err = r.checkOverlap(ctx, tx, merged); err != nil {
+		return err
+	}
+
+	sets = append(sets, "sequence = sequence + 1", "updated_at = now()")
+
+	args = append(args, ID)
+	q := fmt.Sprintf(`
+		UPDATE reservation SET %s WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(sets, ", "), len(args), reservationColumns)
+
+	after, err := scanReservation(tx.QueryRow(ctx, q, args...))
+	if err != nil {
+		return overlapError(err)
+	}
+
+	if err = recordAudit(ctx, tx, audit.ActionUpdate, ID, &before, &after); err != nil {
+		return err
+	}
+
+	if err = recordEvent(ctx, tx, event.TypeReservationUpdated, after); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *ReservationRepository) Delete(ctx context.Context, ID string) error {
+// Cancel marks a reservation as cancelled by the actor in ctx, freeing its time slot.
+func (r *ReservationRepository) Cancel(ctx context.Context, ID string, reason string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.getForUpdate(ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	if before.Cancelled() {
+		return reservation.ErrorCancelled
+	}
+
 	q := `
-		DELETE FROM reservation
-		WHERE id = $1
-	`
+		UPDATE reservation
+		SET cancelled_at = now(), cancel_reason = $1, cancelled_by = $2,
+			sequence = sequence + 1, updated_at = now()
+		WHERE id = $3
+		RETURNING ` + reservationColumns
 
-	args := []any{ID}
+	args := []any{reason, audit.ActorFromContext(ctx).ID, ID}
 
-	result, err := r.db.Exec(ctx, q, args...)
+	after, err := scanReservation(tx.QueryRow(ctx, q, args...))
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
-		return reservation.ErrorNotFound
+	if err = recordAudit(ctx, tx, audit.ActionCancel, ID, &before, &after); err != nil {
+		return err
+	}
+
+	if err = recordEvent(ctx, tx, event.TypeReservationCancelled, after); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
-func (r *ReservationRepository) Update(ctx context.Context, ID string, data reservation.Reservation) error {
+// Restore undoes the cancellation of a reservation as long as its time slot is still free.
+func (r *ReservationRepository) Restore(ctx context.Context, ID string) error {
+	return r.RestoreWith(ctx, ID, reservation.Reservation{})
+}
+
+// RestoreWith restores a cancelled reservation and changes it in the same transaction,
+// so only the time it is moved to has to be free.
+func (r *ReservationRepository) RestoreWith(ctx context.Context, ID string, data reservation.Reservation) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.getForUpdate(ctx, tx, ID)
+	if err != nil {
+		return err
+	}
+
+	if !before.Cancelled() {
+		return reservation.ErrorNotCancelled
+	}
+
+	merged := before.Merge(data)
+	if !merged.StartTime.Before(merged.EndTime) {
+		return reservation.ErrorInvalidTime
+	}
+
+	if err = r.checkOverlap(ctx, tx, merged); err != nil {
+		return err
+	}
+
 	sets, args := r.prepareArgs(data)
-	if len(sets) > 0 {
-		args = append(args, ID)
-		q := fmt.Sprintf("UPDATE reservation SET %s WHERE id = $%d", strings.Join(sets, ", "), len(args))
+	sets = append(sets, "cancelled_at = NULL", "cancel_reason = ''", "cancelled_by = ''",
+		"sequence = sequence + 1", "updated_at = now()")
+
+	args = append(args, ID)
+	q := fmt.Sprintf(`
+		UPDATE reservation SET %s WHERE id = $%d
+		RETURNING %s
+	`, strings.Join(sets, ", "), len(args), reservationColumns)
+
+	after, err := scanReservation(tx.QueryRow(ctx, q, args...))
+	if err != nil {
+		return overlapError(err)
+	}
+
+	if err = recordAudit(ctx, tx, audit.ActionRestore, ID, &before, &after); err != nil {
+		return err
+	}
+
+	if err = recordEvent(ctx, tx, event.TypeReservationRestored, after); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Purge permanently deletes reservations cancelled before the given time.
+func (r *ReservationRepository) Purge(ctx context.Context, cancelledBefore time.Time) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
 
-		result, err := r.db.Exec(ctx, q, args...)
+	q := `
+		DELETE FROM reservation
+		WHERE cancelled_at < $1
+		RETURNING ` + reservationColumns
+
+	rows, err := tx.Query(ctx, q, cancelledBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := []reservation.Reservation{}
+	for rows.Next() {
+		res, err := scanReservation(rows)
 		if err != nil {
-			return err
+			rows.Close()
+			return 0, err
 		}
 
-		if result.RowsAffected() == 0 {
-			return reservation.ErrorNotFound
+		purged = append(purged, res)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, res := range purged {
+		if err = recordAudit(ctx, tx, audit.ActionDelete, res.ID, &res, nil); err != nil {
+			return 0, err
 		}
 	}
 
-	return nil
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(len(purged)), nil
+}
+
+// getForUpdate gets a reservation and locks it until tx ends.
+func (r *ReservationRepository) getForUpdate(ctx context.Context, tx pgx.Tx, ID string) (reservation.Reservation, error) {
+	q := `
+		SELECT ` + reservationColumns + `
+		FROM reservation
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	res, err := scanReservation(tx.QueryRow(ctx, q, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return reservation.Reservation{}, reservation.ErrorNotFound
+		}
+
+		return reservation.Reservation{}, err
+	}
+
+	return res, nil
 }
 
 func (r *ReservationRepository) prepareArgs(data reservation.Reservation) (sets []string, args []any) {
@@ -199,7 +562,7 @@ func (r *ReservationRepository) prepareArgs(data reservation.Reservation) (sets
 	return
 }
 
-func (r *ReservationRepository) generateID() string {
+func generateID() string {
 	bytes := make([]byte, 6)
 	rand.Read(bytes)
 	return hex.EncodeToString(bytes)
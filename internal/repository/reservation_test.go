@@ -38,17 +38,34 @@ func TestReservationRepository(t *testing.T) {
 		testListReservation(ctx, repo, t)
 	})
 
+	t.Run("List reservations for user", func(t *testing.T) {
+		testListReservationForUser(ctx, repo, t)
+	})
+
 	t.Run("Update reservation", func(t *testing.T) {
 		testUpdateReservation(ctx, repo, t)
 	})
 
-	t.Run("Delete reservation", func(t *testing.T) {
-		testDeleteReservation(ctx, repo, t)
+	t.Run("Cancel reservation", func(t *testing.T) {
+		testCancelReservation(ctx, repo, t)
+	})
+
+	t.Run("Restore reservation", func(t *testing.T) {
+		testRestoreReservation(ctx, repo, t)
+	})
+
+	t.Run("Import reservations", func(t *testing.T) {
+		testImportReservations(ctx, repo, t)
+	})
+
+	t.Run("Purge reservation", func(t *testing.T) {
+		testPurgeReservation(ctx, repo, t)
 	})
 }
 
 var testData = reservation.Reservation{
 	RoomID:    "1",
+	UserID:    "alice",
 	StartTime: time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC),
 	EndTime:   time.Date(2024, 8, 29, 14, 0, 0, 0, time.UTC),
 }
@@ -71,6 +88,10 @@ func testCreateReservationShouldOverlap(ctx context.Context, repo *ReservationRe
 
 	_, err := repo.Create(ctx, overlapping)
 	require.ErrorIs(t, err, reservation.ErrorOverlaps, "expected overlap")
+
+	_, err = db.Exec(ctx, "INSERT INTO reservation (id, room_id, start_time, end_time) VALUES ('overlapping', $1, $2, $3)",
+		overlapping.RoomID, overlapping.StartTime, overlapping.EndTime)
+	require.ErrorIs(t, overlapError(err), reservation.ErrorOverlaps, "expected the constraint to refuse overlaps")
 }
 
 func testCreateReservationShouldNotOverlap(ctx context.Context, repo *ReservationRepository, t *testing.T) {
@@ -88,11 +109,14 @@ func testGetReservation(ctx context.Context, repo *ReservationRepository, t *tes
 	res, err := repo.Get(ctx, testData.ID)
 	require.NoError(t, err, "failed to get reservation")
 
+	require.False(t, res.UpdatedAt.IsZero(), "expected updated at to be set")
+	res.UpdatedAt = testData.UpdatedAt
+
 	require.Equalf(t, testData, res, "expected %v, got %v", testData, res)
 }
 
 func testListReservation(ctx context.Context, repo *ReservationRepository, t *testing.T) {
-	reservations, err := repo.List(ctx, testData.RoomID)
+	reservations, err := repo.List(ctx, testData.RoomID, reservation.ListOptions{})
 	require.NoError(t, err, "failed to list reservations")
 
 	require.NotEmpty(t, reservations, "expected at least one reservation")
@@ -100,25 +124,164 @@ func testListReservation(ctx context.Context, repo *ReservationRepository, t *te
 	require.Equalf(t, testData.RoomID, reservations[0].RoomID, "expected room ID %s, got %s", testData.RoomID, reservations[0].RoomID)
 }
 
+func testListReservationForUser(ctx context.Context, repo *ReservationRepository, t *testing.T) {
+	reservations, err := repo.ListForUser(ctx, testData.UserID, reservation.ListOptions{})
+	require.NoError(t, err, "failed to list reservations for user")
+
+	require.Len(t, reservations, 1, "expected only reservations of the user")
+	require.Equal(t, testData.ID, reservations[0].ID)
+
+	reservations, err = repo.ListForUser(ctx, "nobody", reservation.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, reservations)
+
+	reservations, err = repo.ListForRooms(ctx, []string{testData.RoomID, "missing"}, reservation.ListOptions{})
+	require.NoError(t, err, "failed to list reservations for rooms")
+	require.NotEmpty(t, reservations)
+
+	opts := reservation.ListOptions{From: testData.EndTime, To: testData.EndTime.Add(time.Hour)}
+	reservations, err = repo.ListForRooms(ctx, []string{testData.RoomID}, opts)
+	require.NoError(t, err)
+	for _, res := range reservations {
+		require.NotEqual(t, testData.ID, res.ID, "expected reservations ending before From to be left out")
+	}
+}
+
 func testUpdateReservation(ctx context.Context, repo *ReservationRepository, t *testing.T) {
-	updatedEndTime := testData.EndTime.Add(time.Hour)
+	err := repo.Update(ctx, testData.ID, reservation.Reservation{EndTime: testData.EndTime.Add(time.Hour)})
+	require.ErrorIs(t, err, reservation.ErrorOverlaps, "expected update onto another reservation to fail")
+
+	err = repo.Update(ctx, testData.ID, reservation.Reservation{EndTime: testData.StartTime.Add(-time.Hour)})
+	require.ErrorIs(t, err, reservation.ErrorInvalidTime, "expected end time before the kept start time to fail")
+
+	updatedEndTime := testData.EndTime.Add(-30 * time.Minute)
 	toUpdate := reservation.Reservation{
 		EndTime: updatedEndTime,
 	}
 
-	err := repo.Update(ctx, testData.ID, toUpdate)
+	err = repo.Update(ctx, testData.ID, toUpdate)
 	require.NoError(t, err, "failed to update reservation")
 
 	updated, err := repo.Get(ctx, testData.ID)
 	require.NoError(t, err, "failed to get updated reservation")
 
 	require.Equalf(t, updatedEndTime, updated.EndTime, "expected end time %v, got %v", updatedEndTime, updated.EndTime)
+	require.Equal(t, 1, updated.Sequence, "expected sequence to be incremented")
+}
+
+func testCancelReservation(ctx context.Context, repo *ReservationRepository, t *testing.T) {
+	err := repo.Cancel(ctx, testData.ID, "moved online")
+	require.NoError(t, err, "failed to cancel reservation")
+
+	cancelled, err := repo.Get(ctx, testData.ID)
+	require.NoError(t, err, "failed to get cancelled reservation")
+	require.True(t, cancelled.Cancelled(), "expected reservation to be cancelled")
+	require.Equal(t, "moved online", cancelled.CancelReason)
+
+	err = repo.Cancel(ctx, testData.ID, "")
+	require.ErrorIs(t, err, reservation.ErrorCancelled, "expected reservation to be cancelled once")
+
+	reservations, err := repo.List(ctx, testData.RoomID, reservation.ListOptions{})
+	require.NoError(t, err, "failed to list reservations")
+	for _, res := range reservations {
+		require.NotEqual(t, testData.ID, res.ID, "expected cancelled reservation not to be listed")
+	}
+
+	reservations, err = repo.List(ctx, testData.RoomID, reservation.ListOptions{IncludeCancelled: true})
+	require.NoError(t, err, "failed to list reservations")
+	require.Len(t, reservations, 2, "expected cancelled reservation to be listed")
 }
 
-func testDeleteReservation(ctx context.Context, repo *ReservationRepository, t *testing.T) {
-	err := repo.Delete(ctx, testData.ID)
-	require.NoError(t, err, "failed to delete reservation")
+func testRestoreReservation(ctx context.Context, repo *ReservationRepository, t *testing.T) {
+	data := reservation.Reservation{
+		RoomID:    "restore",
+		StartTime: testData.StartTime,
+		EndTime:   testData.EndTime,
+	}
+
+	ID, err := repo.Create(ctx, data)
+	require.NoError(t, err, "could not create reservation")
+
+	err = repo.Cancel(ctx, ID, "")
+	require.NoError(t, err, "failed to cancel reservation")
+
+	takenID, err := repo.Create(ctx, data)
+	require.NoError(t, err, "expected slot of cancelled reservation to be free")
+
+	err = repo.Restore(ctx, ID)
+	require.ErrorIs(t, err, reservation.ErrorOverlaps, "expected restore to fail when slot is taken")
+
+	err = repo.Cancel(ctx, takenID, "")
+	require.NoError(t, err, "failed to cancel reservation")
+
+	err = repo.Restore(ctx, ID)
+	require.NoError(t, err, "failed to restore reservation")
+
+	restored, err := repo.Get(ctx, ID)
+	require.NoError(t, err, "failed to get restored reservation")
+	require.False(t, restored.Cancelled(), "expected reservation not to be cancelled")
+
+	err = repo.Restore(ctx, ID)
+	require.ErrorIs(t, err, reservation.ErrorNotCancelled)
+
+	err = repo.Cancel(ctx, ID, "")
+	require.NoError(t, err, "failed to cancel reservation")
+
+	_, err = repo.Create(ctx, data)
+	require.NoError(t, err, "expected slot of cancelled reservation to be free")
+
+	moved := reservation.Reservation{StartTime: testData.EndTime, EndTime: testData.EndTime.Add(time.Hour)}
+	err = repo.RestoreWith(ctx, ID, moved)
+	require.NoError(t, err, "expected restore to a free slot to succeed while the old one is taken")
+
+	restored, err = repo.Get(ctx, ID)
+	require.NoError(t, err, "failed to get restored reservation")
+	require.False(t, restored.Cancelled(), "expected reservation not to be cancelled")
+	require.True(t, moved.StartTime.Equal(restored.StartTime), "expected reservation to be moved")
+}
+
+func testImportReservations(ctx context.Context, repo *ReservationRepository, t *testing.T) {
+	data := []reservation.Reservation{
+		{RoomID: "import", ICalUID: "a@example.com", StartTime: testData.StartTime, EndTime: testData.EndTime},
+		{RoomID: "import", ICalUID: "b@example.com", StartTime: testData.StartTime, EndTime: testData.EndTime},
+		{RoomID: "import", ICalUID: "c@example.com", StartTime: testData.EndTime, EndTime: testData.StartTime},
+	}
+
+	results, err := repo.Import(ctx, data, true)
+	require.NoError(t, err, "failed to import reservations")
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.ErrorIs(t, results[1].Err, reservation.ErrorOverlaps, "expected overlap with imported reservations")
+	require.Error(t, results[2].Err, "expected invalid reservation to fail on its own")
+
+	_, err = repo.List(ctx, "import", reservation.ListOptions{})
+	require.ErrorIs(t, err, reservation.ErrorNotFoundForRoom, "expected dry run not to create reservations")
+
+	results, err = repo.Import(ctx, data[:1], false)
+	require.NoError(t, err, "failed to import reservations")
+	require.NoError(t, results[0].Err)
+
+	imported, err := repo.GetByICalUID(ctx, "a@example.com")
+	require.NoError(t, err, "failed to get imported reservation")
+	require.Equal(t, results[0].ID, imported.ID)
+
+	_, err = repo.GetByICalUID(ctx, "b@example.com")
+	require.ErrorIs(t, err, reservation.ErrorNotFound)
+
+	results, err = repo.Import(ctx, data[:1], false)
+	require.NoError(t, err, "failed to import reservations")
+	require.ErrorIs(t, results[0].Err, reservation.ErrorImported, "expected events to be imported once")
+}
+
+func testPurgeReservation(ctx context.Context, repo *ReservationRepository, t *testing.T) {
+	n, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err, "failed to purge reservations")
+	require.Zero(t, n, "expected recently cancelled reservations to be kept")
+
+	n, err = repo.Purge(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err, "failed to purge reservations")
+	require.NotZero(t, n, "expected cancelled reservations to be purged")
 
 	_, err = repo.Get(ctx, testData.ID)
-	require.Error(t, err, "expected an error when getting deleted reservation")
+	require.ErrorIs(t, err, reservation.ErrorNotFound, "expected purged reservation to be deleted")
 }
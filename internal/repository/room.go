@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type RoomRepository struct {
+	db *postgres.DB
+}
+
+func NewRoomRepository(db *postgres.DB) *RoomRepository {
+	return &RoomRepository{
+		db: db,
+	}
+}
+
+func (r *RoomRepository) Get(ctx context.Context, ID string) (room.Room, error) {
+	q := `
+		SELECT id, name, building, capacity
+		FROM room
+		WHERE id = $1
+	`
+
+	res := room.Room{}
+
+	err := r.db.QueryRow(ctx, q, ID).Scan(&res.ID, &res.Name, &res.Building, &res.Capacity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return room.Room{}, room.ErrorNotFound
+		}
+
+		return room.Room{}, err
+	}
+
+	return res, nil
+}
+
+func (r *RoomRepository) List(ctx context.Context, filter room.Filter) ([]room.Room, error) {
+	q := `
+		SELECT id, name, building, capacity
+		FROM room
+		WHERE ($1 = '' OR building = $1)
+		ORDER BY id
+	`
+
+	return r.query(ctx, q, filter.Building)
+}
+
+func (r *RoomRepository) GetMany(ctx context.Context, IDs []string) ([]room.Room, error) {
+	q := `
+		SELECT id, name, building, capacity
+		FROM room
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+
+	return r.query(ctx, q, IDs)
+}
+
+func (r *RoomRepository) query(ctx context.Context, q string, args ...any) ([]room.Room, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := []room.Room{}
+	for rows.Next() {
+		var res room.Room
+
+		err := rows.Scan(&res.ID, &res.Name, &res.Building, &res.Capacity)
+		if err != nil {
+			return nil, err
+		}
+
+		rooms = append(rooms, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+func (r *RoomRepository) Save(ctx context.Context, data room.Room) error {
+	q := `
+		INSERT INTO room (id, name, building, capacity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, building = EXCLUDED.building, capacity = EXCLUDED.capacity
+	`
+
+	_, err := r.db.Exec(ctx, q, data.ID, data.Name, data.Building, data.Capacity)
+	return err
+}
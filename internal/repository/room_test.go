@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/internal/domain/room"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &RoomRepository{
+		db: db,
+	}
+
+	data := room.Room{ID: "room-test", Name: "Everest", Building: "HQ", Capacity: 8}
+
+	err := repo.Save(ctx, data)
+	require.NoError(t, err, "could not save room")
+
+	data.Capacity = 10
+	err = repo.Save(ctx, data)
+	require.NoError(t, err, "could not replace room")
+
+	res, err := repo.Get(ctx, data.ID)
+	require.NoError(t, err, "could not get room")
+	require.Equal(t, data, res)
+
+	rooms, err := repo.List(ctx, room.Filter{Building: "HQ"})
+	require.NoError(t, err, "could not list rooms")
+	require.Contains(t, rooms, data)
+
+	rooms, err = repo.List(ctx, room.Filter{Building: "Annex"})
+	require.NoError(t, err, "could not list rooms")
+	require.NotContains(t, rooms, data)
+
+	rooms, err = repo.GetMany(ctx, []string{data.ID, "missing"})
+	require.NoError(t, err, "could not get rooms")
+	require.Equal(t, []room.Room{data}, rooms, "expected unknown rooms to be left out")
+
+	_, err = repo.Get(ctx, "missing")
+	require.ErrorIs(t, err, room.ErrorNotFound)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ScheduleRepository struct {
+	db *postgres.DB
+}
+
+func NewScheduleRepository(db *postgres.DB) *ScheduleRepository {
+	return &ScheduleRepository{
+		db: db,
+	}
+}
+
+const scheduleColumns = "scope, key, time_zone, weekly, exceptions, updated_at"
+
+func scanSchedule(row pgx.Row) (schedule.Schedule, error) {
+	s := schedule.Schedule{}
+
+	err := row.Scan(&s.Scope, &s.Key, &s.TimeZone, &s.Weekly, &s.Exceptions, &s.UpdatedAt)
+
+	return s, err
+}
+
+func (r *ScheduleRepository) Get(ctx context.Context, scope, key string) (schedule.Schedule, error) {
+	q := `
+		SELECT ` + scheduleColumns + `
+		FROM opening_schedule
+		WHERE scope = $1 AND key = $2
+	`
+
+	s, err := scanSchedule(r.db.QueryRow(ctx, q, scope, key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return schedule.Schedule{}, schedule.ErrorNotFound
+		}
+
+		return schedule.Schedule{}, err
+	}
+
+	return s, nil
+}
+
+func (r *ScheduleRepository) Save(ctx context.Context, data schedule.Schedule) error {
+	q := `
+		INSERT INTO opening_schedule (scope, key, time_zone, weekly, exceptions)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET time_zone = EXCLUDED.time_zone, weekly = EXCLUDED.weekly, exceptions = EXCLUDED.exceptions,
+			updated_at = now()
+	`
+
+	exceptions := data.Exceptions
+	if exceptions == nil {
+		exceptions = []schedule.Exception{}
+	}
+
+	_, err := r.db.Exec(ctx, q, data.Scope, data.Key, data.TimeZone, data.Weekly, exceptions)
+	return err
+}
+
+func (r *ScheduleRepository) Delete(ctx context.Context, scope, key string) error {
+	q := `
+		DELETE FROM opening_schedule
+		WHERE scope = $1 AND key = $2
+	`
+
+	result, err := r.db.Exec(ctx, q, scope, key)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return schedule.ErrorNotFound
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) ForRooms(ctx context.Context, roomIDs []string) (map[string]schedule.Hours, error) {
+	q := `
+		SELECT r.id, s.scope, s.key, s.time_zone, s.weekly, s.exceptions, s.updated_at
+		FROM (SELECT DISTINCT unnest($1::varchar[]) AS id) AS r
+		LEFT JOIN room ON room.id = r.id
+		JOIN opening_schedule s
+			ON (s.scope = 'room' AND s.key = r.id) OR (s.scope = 'building' AND s.key = room.building)
+		ORDER BY r.id, s.scope = 'room'
+	`
+
+	rows, err := r.db.Query(ctx, q, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hours := map[string]schedule.Hours{}
+	for rows.Next() {
+		var (
+			roomID string
+			s      schedule.Schedule
+		)
+
+		err := rows.Scan(&roomID, &s.Scope, &s.Key, &s.TimeZone, &s.Weekly, &s.Exceptions, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		hours[roomID] = append(hours[roomID], s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
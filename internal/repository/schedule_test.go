@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"room-reservation/internal/domain/room"
+	"room-reservation/internal/domain/schedule"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &ScheduleRepository{
+		db: db,
+	}
+	rooms := &RoomRepository{
+		db: db,
+	}
+
+	require.NoError(t, rooms.Save(ctx, room.Room{ID: "room-schedule", Name: "Kilimanjaro", Building: "Schedule HQ"}))
+
+	building := schedule.Schedule{
+		Scope:    schedule.ScopeBuilding,
+		Key:      "Schedule HQ",
+		TimeZone: "Europe/Paris",
+		Exceptions: []schedule.Exception{
+			{Date: "2024-12-25", Periods: []schedule.Period{}, Reason: "Christmas"},
+		},
+	}
+	building.Weekly[time.Monday] = []schedule.Period{{Open: 8 * 60, Close: 18 * 60}}
+
+	err := repo.Save(ctx, building)
+	require.NoError(t, err, "could not save schedule")
+
+	res, err := repo.Get(ctx, building.Scope, building.Key)
+	require.NoError(t, err, "could not get schedule")
+	require.Equal(t, building.Weekly, res.Weekly)
+	require.Equal(t, building.Exceptions, res.Exceptions)
+	require.Equal(t, building.TimeZone, res.TimeZone)
+
+	own := schedule.Schedule{Scope: schedule.ScopeRoom, Key: "room-schedule", TimeZone: "UTC"}
+	own.Weekly[time.Monday] = []schedule.Period{{Open: 12 * 60, Close: 14 * 60}}
+
+	require.NoError(t, repo.Save(ctx, own), "could not save schedule")
+
+	hours, err := repo.ForRooms(ctx, []string{"room-schedule", "room-schedule", "room-without-schedule"})
+	require.NoError(t, err, "could not get hours")
+	require.Len(t, hours, 1, "expected rooms without schedule to be left out")
+	require.Len(t, hours["room-schedule"], 2)
+	require.Equal(t, schedule.ScopeBuilding, hours["room-schedule"][0].Scope, "expected the building schedule first")
+	require.Equal(t, schedule.ScopeRoom, hours["room-schedule"][1].Scope)
+
+	require.NoError(t, repo.Delete(ctx, own.Scope, own.Key), "could not delete schedule")
+	require.ErrorIs(t, repo.Delete(ctx, own.Scope, own.Key), schedule.ErrorNotFound)
+
+	_, err = repo.Get(ctx, own.Scope, own.Key)
+	require.ErrorIs(t, err, schedule.ErrorNotFound)
+}
@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/webhook"
+	"room-reservation/internal/repository/postgres"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type WebhookRepository struct {
+	db *postgres.DB
+}
+
+func NewWebhookRepository(db *postgres.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+const subscriptionColumns = "id, url, event_types, room_ids, secret, failure_count, disabled_at, created_at"
+
+func scanSubscription(row pgx.Row) (webhook.Subscription, error) {
+	s := webhook.Subscription{}
+
+	err := row.Scan(&s.ID, &s.URL, &s.EventTypes, &s.RoomIDs, &s.Secret, &s.FailureCount, &s.DisabledAt, &s.CreatedAt)
+
+	return s, err
+}
+
+const deliveryColumns = "id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at"
+
+func scanDelivery(row pgx.Row) (webhook.Delivery, error) {
+	d := webhook.Delivery{}
+
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt)
+
+	return d, err
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, data webhook.Subscription) (string, error) {
+	q := `
+		INSERT INTO webhook_subscription (id, url, event_types, room_ids, secret)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	data.ID = generateID()
+	args := []any{data.ID, data.URL, nonNil(data.EventTypes), nonNil(data.RoomIDs), data.Secret}
+
+	_, err := r.db.Exec(ctx, q, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return data.ID, nil
+}
+
+func (r *WebhookRepository) Get(ctx context.Context, ID string) (webhook.Subscription, error) {
+	q := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscription
+		WHERE id = $1
+	`
+
+	s, err := scanSubscription(r.db.QueryRow(ctx, q, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhook.Subscription{}, webhook.ErrorNotFound
+		}
+
+		return webhook.Subscription{}, err
+	}
+
+	return s, nil
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]webhook.Subscription, error) {
+	q := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscription
+		ORDER BY created_at
+	`
+
+	return r.list(ctx, q)
+}
+
+func (r *WebhookRepository) list(ctx context.Context, q string, args ...any) ([]webhook.Subscription, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []webhook.Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, ID string) error {
+	q := `
+		DELETE FROM webhook_subscription
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return webhook.ErrorNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) Enable(ctx context.Context, ID string) error {
+	q := `
+		UPDATE webhook_subscription
+		SET disabled_at = NULL, failure_count = 0
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return webhook.ErrorNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) Enqueue(ctx context.Context, e event.Event) error {
+	q := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscription
+		WHERE disabled_at IS NULL
+	`
+
+	subscriptions, err := r.list(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ON CONSTRAINT webhook_delivery_event_unique DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, s := range subscriptions {
+		if s.Matches(e) {
+			batch.Queue(insertQuery, s.ID, e.ID, e.Type, payload)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+// Deliver claims up to limit due deliveries and sends them, see leaseDuration.
+func (r *WebhookRepository) Deliver(ctx context.Context, limit int, send func(context.Context, webhook.Subscription, webhook.Delivery) webhook.Result) (int, error) {
+	batch, err := r.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range batch {
+		res := send(ctx, p.subscription, p.delivery)
+
+		if err := r.record(ctx, p.subscription, p.delivery, res); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), nil
+}
+
+type pendingDelivery struct {
+	delivery     webhook.Delivery
+	subscription webhook.Subscription
+}
+
+// claim picks up to limit due deliveries of enabled subscriptions and leases them for leaseDuration.
+func (r *WebhookRepository) claim(ctx context.Context, limit int) ([]pendingDelivery, error) {
+	q := `
+		UPDATE webhook_delivery d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM webhook_subscription s
+		WHERE s.id = d.subscription_id
+		AND d.id IN (
+			SELECT p.id
+			FROM webhook_delivery p
+			JOIN webhook_subscription ps ON ps.id = p.subscription_id
+			WHERE p.status = 'pending'
+			AND p.next_attempt_at <= now()
+			AND ps.disabled_at IS NULL
+			ORDER BY p.id
+			LIMIT $1
+			FOR UPDATE OF p SKIP LOCKED
+		)
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.response_status,
+			d.last_error, d.next_attempt_at, d.delivered_at, d.created_at,
+			s.id, s.url, s.event_types, s.room_ids, s.secret, s.failure_count, s.disabled_at, s.created_at
+	`
+
+	rows, err := r.db.Query(ctx, q, limit, leaseDuration.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := []pendingDelivery{}
+	for rows.Next() {
+		var p pendingDelivery
+		d, s := &p.delivery, &p.subscription
+
+		err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt,
+			&s.ID, &s.URL, &s.EventTypes, &s.RoomIDs, &s.Secret, &s.FailureCount, &s.DisabledAt, &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(batch, func(i, j int) bool {
+		return batch[i].delivery.ID < batch[j].delivery.ID
+	})
+
+	return batch, nil
+}
+
+// record stores the result of sending d along with the failure count of its subscription.
+func (r *WebhookRepository) record(ctx context.Context, s webhook.Subscription, d webhook.Delivery, res webhook.Result) error {
+	deliveredQuery := `
+		UPDATE webhook_delivery
+		SET status = 'delivered', attempts = attempts + 1, response_status = $1, last_error = '', delivered_at = now()
+		WHERE id = $2
+	`
+
+	succeededQuery := `
+		UPDATE webhook_subscription
+		SET failure_count = 0
+		WHERE id = $1
+	`
+
+	failedQuery := `
+		UPDATE webhook_delivery
+		SET status = $1, attempts = attempts + 1, response_status = $2, last_error = $3,
+			next_attempt_at = now() + make_interval(secs => $4)
+		WHERE id = $5
+	`
+
+	// Subscriptions failing too many times in a row are disabled until they are enabled again.
+	failingQuery := `
+		UPDATE webhook_subscription
+		SET failure_count = failure_count + 1,
+			disabled_at = CASE WHEN failure_count + 1 >= $1 THEN now() ELSE disabled_at END
+		WHERE id = $2
+	`
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if res.Err == nil {
+			if _, err := tx.Exec(ctx, deliveredQuery, res.ResponseStatus, d.ID); err != nil {
+				return err
+			}
+
+			_, err := tx.Exec(ctx, succeededQuery, s.ID)
+			return err
+		}
+
+		attempt := d.Attempts + 1
+
+		status := webhook.StatusPending
+		if attempt >= webhook.MaxAttempts {
+			status = webhook.StatusFailed
+		}
+
+		args := []any{status, res.ResponseStatus, res.Err.Error(), webhook.Backoff.Delay(attempt).Seconds(), d.ID}
+		if _, err := tx.Exec(ctx, failedQuery, args...); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, failingQuery, webhook.DisableAfterFailures, s.ID)
+		return err
+	})
+}
+
+func (r *WebhookRepository) Deliveries(ctx context.Context, subscriptionID string) ([]webhook.Delivery, error) {
+	if _, err := r.Get(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	q := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_delivery
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT 100
+	`
+
+	rows, err := r.db.Query(ctx, q, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []webhook.Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) Replay(ctx context.Context, subscriptionID string, deliveryID int64) error {
+	q := `
+		UPDATE webhook_delivery
+		SET status = 'pending', attempts = 0, next_attempt_at = now()
+		WHERE id = $1 AND subscription_id = $2
+	`
+
+	result, err := r.db.Exec(ctx, q, deliveryID, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return webhook.ErrorDeliveryNotFound
+	}
+
+	return nil
+}
+
+// nonNil keeps postgres arrays empty instead of NULL.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/webhook"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &WebhookRepository{
+		db: db,
+	}
+
+	ID, err := repo.Create(ctx, webhook.Subscription{
+		URL:        "http://localhost/hook",
+		EventTypes: []string{event.TypeReservationCreated},
+		Secret:     "secret",
+	})
+	require.NoError(t, err, "could not create webhook")
+
+	created := event.Event{ID: 1, Type: event.TypeReservationCreated, ReservationID: "1", RoomID: "1", Data: []byte(`{}`)}
+	cancelled := event.Event{ID: 2, Type: event.TypeReservationCancelled, ReservationID: "1", RoomID: "1", Data: []byte(`{}`)}
+
+	for _, e := range []event.Event{created, created, cancelled} {
+		err = repo.Enqueue(ctx, e)
+		require.NoError(t, err, "could not enqueue event")
+	}
+
+	deliveries, err := repo.Deliveries(ctx, ID)
+	require.NoError(t, err, "could not list deliveries")
+	require.Len(t, deliveries, 1, "expected a single delivery of the subscribed event")
+
+	failing := func(context.Context, webhook.Subscription, webhook.Delivery) webhook.Result {
+		return webhook.Result{ResponseStatus: 500, Err: errors.New("unexpected response status 500")}
+	}
+
+	n, err := repo.Deliver(ctx, 10, func(ctx context.Context, s webhook.Subscription, d webhook.Delivery) webhook.Result {
+		// another dispatcher running meanwhile leaves the claimed delivery alone
+		n, err := repo.Deliver(ctx, 10, failing)
+		require.NoError(t, err, "could not deliver webhooks")
+		require.Zero(t, n, "expected claimed deliveries not to be sent twice")
+
+		return failing(ctx, s, d)
+	})
+	require.NoError(t, err, "could not deliver webhooks")
+	require.Equal(t, 1, n)
+
+	deliveries, err = repo.Deliveries(ctx, ID)
+	require.NoError(t, err, "could not list deliveries")
+	require.Equal(t, webhook.StatusPending, deliveries[0].Status, "expected failed delivery to be retried")
+	require.Equal(t, 500, deliveries[0].ResponseStatus)
+
+	_, err = db.Exec(ctx, "UPDATE webhook_subscription SET failure_count = $1 WHERE id = $2", webhook.DisableAfterFailures-1, ID)
+	require.NoError(t, err)
+
+	err = repo.Replay(ctx, ID, deliveries[0].ID)
+	require.NoError(t, err, "could not replay delivery")
+
+	_, err = repo.Deliver(ctx, 10, failing)
+	require.NoError(t, err, "could not deliver webhooks")
+
+	sub, err := repo.Get(ctx, ID)
+	require.NoError(t, err, "could not get webhook")
+	require.True(t, sub.Disabled(), "expected webhook to be disabled after failing too many times")
+
+	err = repo.Enable(ctx, ID)
+	require.NoError(t, err, "could not enable webhook")
+
+	err = repo.Replay(ctx, ID, deliveries[0].ID)
+	require.NoError(t, err, "could not replay delivery")
+
+	n, err = repo.Deliver(ctx, 10, func(context.Context, webhook.Subscription, webhook.Delivery) webhook.Result {
+		return webhook.Result{ResponseStatus: 200}
+	})
+	require.NoError(t, err, "could not deliver webhooks")
+	require.Equal(t, 1, n)
+
+	deliveries, err = repo.Deliveries(ctx, ID)
+	require.NoError(t, err, "could not list deliveries")
+	require.Equal(t, webhook.StatusDelivered, deliveries[0].Status)
+
+	err = repo.Delete(ctx, ID)
+	require.NoError(t, err, "could not delete webhook")
+}
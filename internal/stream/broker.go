@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"context"
+	"room-reservation/internal/domain/event"
+	"sync"
+)
+
+// Broker fans out events to subscribers within this instance.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+
+	buffer int
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subs:   make(map[*Subscription]struct{}),
+		buffer: 64,
+	}
+}
+
+// Subscription receives events of a room, or of every room when roomID is empty.
+// C is closed when the subscription is closed, either by its owner or by the broker
+// because the subscriber could not keep up or the broker was closed.
+type Subscription struct {
+	C <-chan event.Event
+
+	ch     chan event.Event
+	roomID string
+	broker *Broker
+	once   sync.Once
+}
+
+func (b *Broker) Subscribe(roomID string) *Subscription {
+	ch := make(chan event.Event, b.buffer)
+
+	s := &Subscription{
+		C:      ch,
+		ch:     ch,
+		roomID: roomID,
+		broker: b,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		// closed from the start, Close must not close it again
+		close(ch)
+		s.once.Do(func() {})
+
+		return s
+	}
+
+	b.subs[s] = struct{}{}
+
+	return s
+}
+
+// Close closes every subscription, as well as the ones made afterwards, so that streams end when the service
+// shuts down. Subscribers are expected to resume from the last event they received on another instance.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for s := range b.subs {
+		s.close()
+	}
+}
+
+// Closed reports whether the broker was closed.
+func (b *Broker) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.closed
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		delete(s.broker.subs, s)
+		close(s.ch)
+	})
+}
+
+// Publish hands e to every matching subscriber without blocking. Subscribers whose buffer is full
+// are dropped, they are expected to resume from the last event they received.
+func (b *Broker) Publish(_ context.Context, e event.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		if s.roomID != "" && s.roomID != e.RoomID {
+			continue
+		}
+
+		select {
+		case s.ch <- e:
+		default:
+			s.close()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"room-reservation/internal/domain/event"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewBroker()
+
+	room := b.Subscribe("1")
+	all := b.Subscribe("")
+
+	b.Publish(context.Background(), event.Event{ID: 1, RoomID: "1"})
+	b.Publish(context.Background(), event.Event{ID: 2, RoomID: "2"})
+
+	require.Equal(t, int64(1), (<-room.C).ID)
+	require.Empty(t, room.C, "expected events of other rooms to be filtered")
+
+	require.Equal(t, int64(1), (<-all.C).ID)
+	require.Equal(t, int64(2), (<-all.C).ID)
+
+	room.Close()
+	_, ok := <-room.C
+	require.False(t, ok, "expected closed subscription channel to be closed")
+
+	room.Close()
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	b := NewBroker()
+	b.buffer = 1
+
+	s := b.Subscribe("")
+
+	b.Publish(context.Background(), event.Event{ID: 1})
+	b.Publish(context.Background(), event.Event{ID: 2})
+
+	require.Equal(t, int64(1), (<-s.C).ID)
+
+	_, ok := <-s.C
+	require.False(t, ok, "expected slow subscriber to be dropped")
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := NewBroker()
+
+	s := b.Subscribe("")
+
+	b.Close()
+	require.True(t, b.Closed())
+
+	_, ok := <-s.C
+	require.False(t, ok, "expected subscriptions to be closed")
+
+	late := b.Subscribe("1")
+
+	_, ok = <-late.C
+	require.False(t, ok, "expected subscriptions made after closing to be closed")
+
+	s.Close()
+	late.Close()
+}
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteEvent(&buf, event.Event{ID: 7, Type: event.TypeReservationCreated, RoomID: "1", Data: []byte(`{}`)})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "id: 7\nevent: ReservationCreated\ndata: {")
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\n\n")))
+}
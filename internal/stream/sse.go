@@ -0,0 +1,26 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"room-reservation/internal/domain/event"
+)
+
+// WriteEvent writes e in the Server-Sent Events format, named by its type and identified by its id
+// so that clients can resume with the Last-Event-ID header.
+func WriteEvent(w io.Writer, e event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// WriteHeartbeat writes a comment keeping idle connections open.
+func WriteHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": heartbeat\n\n")
+	return err
+}
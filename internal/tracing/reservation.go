@@ -0,0 +1,151 @@
+// Package tracing records OpenTelemetry spans around the domain repositories.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"room-reservation/internal/domain/reservation"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("room-reservation/internal/tracing")
+
+// start starts a span named after a repository method.
+func start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// end ends span, recording err if any.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+type reservationRepository struct {
+	repo reservation.Repository
+}
+
+// Reservations records a span for every call to repo.
+func Reservations(repo reservation.Repository) reservation.Repository {
+	return reservationRepository{repo}
+}
+
+func (r reservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	ctx, span := start(ctx, "ReservationRepository.Create", attribute.String("room.id", data.RoomID))
+
+	ID, err := r.repo.Create(ctx, data)
+	span.SetAttributes(attribute.String("reservation.id", ID))
+	end(span, err)
+
+	return ID, err
+}
+
+func (r reservationRepository) Import(ctx context.Context, data []reservation.Reservation, dryRun bool) ([]reservation.ImportResult, error) {
+	ctx, span := start(ctx, "ReservationRepository.Import",
+		attribute.Int("reservation.count", len(data)), attribute.Bool("dry_run", dryRun))
+
+	results, err := r.repo.Import(ctx, data, dryRun)
+	end(span, err)
+
+	return results, err
+}
+
+func (r reservationRepository) Get(ctx context.Context, ID string) (reservation.Reservation, error) {
+	ctx, span := start(ctx, "ReservationRepository.Get", attribute.String("reservation.id", ID))
+
+	res, err := r.repo.Get(ctx, ID)
+	end(span, err)
+
+	return res, err
+}
+
+func (r reservationRepository) GetByICalUID(ctx context.Context, uid string) (reservation.Reservation, error) {
+	ctx, span := start(ctx, "ReservationRepository.GetByICalUID")
+
+	res, err := r.repo.GetByICalUID(ctx, uid)
+	end(span, err)
+
+	return res, err
+}
+
+func (r reservationRepository) List(ctx context.Context, roomID string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	ctx, span := start(ctx, "ReservationRepository.List", attribute.String("room.id", roomID))
+
+	list, err := r.repo.List(ctx, roomID, opts)
+	end(span, err)
+
+	return list, err
+}
+
+func (r reservationRepository) ListForUser(ctx context.Context, userID string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	ctx, span := start(ctx, "ReservationRepository.ListForUser", attribute.String("user.id", userID))
+
+	list, err := r.repo.ListForUser(ctx, userID, opts)
+	end(span, err)
+
+	return list, err
+}
+
+func (r reservationRepository) ListForRooms(ctx context.Context, roomIDs []string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	ctx, span := start(ctx, "ReservationRepository.ListForRooms", attribute.StringSlice("room.ids", roomIDs))
+
+	list, err := r.repo.ListForRooms(ctx, roomIDs, opts)
+	end(span, err)
+
+	return list, err
+}
+
+func (r reservationRepository) Update(ctx context.Context, ID string, data reservation.Reservation) error {
+	ctx, span := start(ctx, "ReservationRepository.Update", attribute.String("reservation.id", ID))
+
+	err := r.repo.Update(ctx, ID, data)
+	end(span, err)
+
+	return err
+}
+
+func (r reservationRepository) Cancel(ctx context.Context, ID string, reason string) error {
+	ctx, span := start(ctx, "ReservationRepository.Cancel", attribute.String("reservation.id", ID))
+
+	err := r.repo.Cancel(ctx, ID, reason)
+	end(span, err)
+
+	return err
+}
+
+func (r reservationRepository) Restore(ctx context.Context, ID string) error {
+	ctx, span := start(ctx, "ReservationRepository.Restore", attribute.String("reservation.id", ID))
+
+	err := r.repo.Restore(ctx, ID)
+	end(span, err)
+
+	return err
+}
+
+func (r reservationRepository) RestoreWith(ctx context.Context, ID string, data reservation.Reservation) error {
+	ctx, span := start(ctx, "ReservationRepository.RestoreWith", attribute.String("reservation.id", ID))
+
+	err := r.repo.RestoreWith(ctx, ID, data)
+	end(span, err)
+
+	return err
+}
+
+func (r reservationRepository) Purge(ctx context.Context, cancelledBefore time.Time) (int64, error) {
+	ctx, span := start(ctx, "ReservationRepository.Purge")
+
+	n, err := r.repo.Purge(ctx, cancelledBefore)
+	span.SetAttributes(attribute.Int64("reservation.count", n))
+	end(span, err)
+
+	return n, err
+}
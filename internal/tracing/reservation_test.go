@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"room-reservation/internal/domain/reservation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeRepository struct {
+	reservation.Repository
+}
+
+func (fakeRepository) Create(ctx context.Context, _ reservation.Reservation) (string, error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return "", nil
+	}
+
+	return "1", nil
+}
+
+func (fakeRepository) Update(context.Context, string, reservation.Reservation) error {
+	return reservation.ErrorOverlaps
+}
+
+func TestReservations(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	repo := Reservations(fakeRepository{})
+
+	ID, err := repo.Create(context.Background(), reservation.Reservation{RoomID: "2"})
+	require.NoError(t, err)
+	require.Equal(t, "1", ID, "expected the span to be passed on")
+
+	require.ErrorIs(t, repo.Update(context.Background(), "1", reservation.Reservation{}), reservation.ErrorOverlaps)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "ReservationRepository.Create", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("room.id", "2"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("reservation.id", "1"))
+
+	assert.Equal(t, "ReservationRepository.Update", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
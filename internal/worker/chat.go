@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/router"
+	"strconv"
+	"time"
+)
+
+// ChatSink queues published events, formatted as chat messages, to the chat channels following their room.
+type ChatSink struct {
+	repo     chat.Repository
+	roomRepo room.Repository
+	baseURL  string
+}
+
+// NewChatSink creates a sink linking messages to baseURL. roomRepo may be nil, rooms are then
+// named after their id and channels only follow them by id.
+func NewChatSink(repo chat.Repository, roomRepo room.Repository, baseURL string) *ChatSink {
+	return &ChatSink{
+		repo:     repo,
+		roomRepo: roomRepo,
+		baseURL:  baseURL,
+	}
+}
+
+func (s *ChatSink) Publish(ctx context.Context, e event.Event) error {
+	var data reservation.Reservation
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return err
+	}
+
+	rm := room.Room{ID: e.RoomID}
+	if s.roomRepo != nil {
+		if res, err := s.roomRepo.Get(ctx, e.RoomID); err == nil {
+			rm = res
+		}
+	}
+
+	payload, err := json.Marshal(chat.Format(e, data, rm, s.baseURL))
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Enqueue(ctx, e, rm.Building, payload)
+}
+
+// ChatRateLimit is how often messages are posted to a single channel, incoming webhooks
+// allow about one message per second with short bursts.
+var ChatRateLimit = router.Limit{Rate: 1, Burst: 3}
+
+// ChatDispatcher posts queued messages to chat channels, rate limited per channel.
+type ChatDispatcher struct {
+	repo     chat.Repository
+	limiter  router.LimiterStore
+	client   *http.Client
+	interval time.Duration
+	batch    int
+}
+
+// NewChatDispatcher creates a dispatcher keeping the rate limits of channels in limiter, which is shared
+// between instances when it is backed by Postgres.
+func NewChatDispatcher(repo chat.Repository, limiter router.LimiterStore, interval time.Duration) *ChatDispatcher {
+	return &ChatDispatcher{
+		repo:     repo,
+		limiter:  limiter,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: interval,
+		batch:    20,
+	}
+}
+
+// Run posts due messages every interval until ctx is done.
+func (d *ChatDispatcher) Run(ctx context.Context) {
+	poll(ctx, d.interval, d.batch, "error posting chat messages", func(ctx context.Context, limit int) (int, error) {
+		return d.repo.Deliver(ctx, limit, d.Send)
+	})
+}
+
+// Send posts a message to the channel. Messages over the rate limit of the channel, or rejected with
+// 429 Too Many Requests, are rate limited rather than failed.
+func (d *ChatDispatcher) Send(ctx context.Context, c chat.Channel, m chat.Message) chat.Result {
+	limit, err := d.limiter.Take(ctx, "chat:"+c.ID, ChatRateLimit)
+	if err != nil {
+		return chat.Result{Err: err}
+	}
+
+	if !limit.Allowed {
+		return chat.Result{RetryAfter: limit.RetryAfter, Err: chat.ErrorRateLimited}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(m.Payload))
+	if err != nil {
+		return chat.Result{Err: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "room-reservation-chat")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return chat.Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+
+		return chat.Result{
+			ResponseStatus: resp.StatusCode,
+			RetryAfter:     time.Duration(retryAfter) * time.Second,
+			Err:            chat.ErrorRateLimited,
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return chat.Result{ResponseStatus: resp.StatusCode, Err: fmt.Errorf("unexpected response status %d", resp.StatusCode)}
+	}
+
+	return chat.Result{ResponseStatus: resp.StatusCode}
+}
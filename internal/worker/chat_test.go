@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"room-reservation/internal/domain/chat"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/slack"
+	"room-reservation/pkg/slack/slacktest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chatRepository struct {
+	chat.Repository
+
+	building string
+	payload  []byte
+}
+
+func (r *chatRepository) Enqueue(ctx context.Context, e event.Event, building string, payload []byte) error {
+	r.building = building
+	r.payload = payload
+	return nil
+}
+
+type chatRoomRepository struct {
+	room.Repository
+}
+
+func (r *chatRoomRepository) Get(ctx context.Context, ID string) (room.Room, error) {
+	return room.Room{ID: ID, Name: "Everest", Building: "HQ"}, nil
+}
+
+func TestChatSink(t *testing.T) {
+	data, err := json.Marshal(reservation.Reservation{
+		ID:        "abc",
+		RoomID:    "1",
+		StartTime: time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 9, 2, 11, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	repo := &chatRepository{}
+	s := NewChatSink(repo, &chatRoomRepository{}, "https://rooms.example.com")
+
+	err = s.Publish(context.Background(), event.Event{ID: 1, Type: event.TypeReservationCreated, RoomID: "1", Data: data})
+	require.NoError(t, err, "could not publish event")
+
+	assert.Equal(t, "HQ", repo.building, "expected channels to be matched by the building of the room")
+
+	var m slack.Message
+	require.NoError(t, json.Unmarshal(repo.payload, &m))
+	assert.Equal(t, "Everest booked", m.Attachments[0].Title)
+}
+
+func TestChatDispatcherSend(t *testing.T) {
+	receiver := slacktest.NewServer()
+	defer receiver.Close()
+
+	d := NewChatDispatcher(nil, router.NewMemoryStore(), time.Second)
+
+	c := chat.Channel{ID: "1", URL: receiver.URL + "/hooks/1"}
+	m := chat.Message{ID: 7, Payload: []byte(`{"text":"Everest booked"}`)}
+
+	res := d.Send(context.Background(), c, m)
+	require.NoError(t, res.Err, "expected message to be posted")
+
+	received := <-receiver.Messages
+	assert.Equal(t, "Everest booked", received.Text)
+
+	receiver.RateLimit(30 * time.Second)
+
+	res = d.Send(context.Background(), c, m)
+	assert.ErrorIs(t, res.Err, chat.ErrorRateLimited, "expected 429 to rate limit the channel")
+	assert.Equal(t, 30*time.Second, res.RetryAfter)
+
+	receiver.RateLimit(0)
+
+	for range ChatRateLimit.Burst - 2 {
+		res = d.Send(context.Background(), c, m)
+		require.NoError(t, res.Err, "expected message to be posted")
+		<-receiver.Messages
+	}
+
+	res = d.Send(context.Background(), c, m)
+	assert.ErrorIs(t, res.Err, chat.ErrorRateLimited, "expected channel to be rate limited after its burst")
+	assert.Positive(t, res.RetryAfter)
+
+	res = d.Send(context.Background(), chat.Channel{ID: "2", URL: receiver.URL + "/hooks/2"}, m)
+	require.NoError(t, res.Err, "expected other channels not to be rate limited")
+	<-receiver.Messages
+
+	res = d.Send(context.Background(), chat.Channel{ID: "3", URL: receiver.URL}, chat.Message{Payload: []byte(`{}`)})
+	assert.Error(t, res.Err, "expected empty message to be rejected")
+	assert.Equal(t, 400, res.ResponseStatus)
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"room-reservation/internal/domain/audit"
+	"room-reservation/pkg/log"
+	"time"
+)
+
+// Cleaner periodically deletes rows kept for longer than the retention period, such as cancelled
+// reservations or published events.
+type Cleaner struct {
+	what      string
+	delete    func(ctx context.Context, before time.Time) (int64, error)
+	retention time.Duration
+	interval  time.Duration
+
+	now func() time.Time
+}
+
+// NewCleaner creates a cleaner deleting what was done before the retention period with delete,
+// what naming the deleted rows in logs.
+func NewCleaner(what string, delete func(ctx context.Context, before time.Time) (int64, error), retention, interval time.Duration) *Cleaner {
+	return &Cleaner{
+		what:      what,
+		delete:    delete,
+		retention: retention,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Run cleans up every interval until ctx is done. Deletions are audited as made by the retention actor.
+func (c *Cleaner) Run(ctx context.Context) {
+	ctx = audit.WithActor(ctx, audit.Actor{ID: "retention"})
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.Clean(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Cleaner) Clean(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx)
+
+	n, err := c.delete(ctx, c.now().Add(-c.retention))
+	if err != nil {
+		logger.Err(err).Caller().Msg("error deleting " + c.what)
+		return
+	}
+
+	if n > 0 {
+		logger.Info().Int64("count", n).Msg("deleted " + c.what)
+	}
+}
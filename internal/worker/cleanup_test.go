@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleaner(t *testing.T) {
+	now := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		retention time.Duration
+		expected  time.Time
+	}{
+		"Cancelled reservations": {retention: 30 * 24 * time.Hour, expected: time.Date(2024, 7, 30, 13, 0, 0, 0, time.UTC)},
+		"Published events":       {retention: 7 * 24 * time.Hour, expected: time.Date(2024, 8, 22, 13, 0, 0, 0, time.UTC)},
+	}
+
+	for name, test := range tests {
+		var before time.Time
+		deleteBefore := func(ctx context.Context, at time.Time) (int64, error) {
+			before = at
+			return 1, nil
+		}
+
+		c := NewCleaner(name, deleteBefore, test.retention, time.Hour)
+		c.now = func() time.Time { return now }
+
+		c.Clean(context.Background())
+
+		require.Equal(t, test.expected, before, "%s: expected retention period to be kept", name)
+	}
+}
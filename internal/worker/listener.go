@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"room-reservation/internal/domain/event"
+	"room-reservation/pkg/log"
+	"time"
+)
+
+// Listener forwards events committed by any instance of the service to a sink, reconnecting when listening fails.
+type Listener struct {
+	repo  event.Repository
+	sink  event.Sink
+	retry time.Duration
+}
+
+func NewListener(repo event.Repository, sink event.Sink) *Listener {
+	return &Listener{
+		repo:  repo,
+		sink:  sink,
+		retry: 5 * time.Second,
+	}
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	logger := log.LoggerFromContext(ctx)
+
+	for {
+		err := l.repo.Listen(ctx, func(e event.Event) {
+			if err := l.sink.Publish(ctx, e); err != nil {
+				logger.Err(err).Caller().Int64("event_id", e.ID).Msg("error forwarding event")
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retry):
+		}
+
+		logger.Err(err).Caller().Msg("stopped listening for events, reconnecting")
+	}
+}
@@ -0,0 +1,193 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"room-reservation/internal/domain/calendar"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/notification"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/room"
+	"room-reservation/pkg/mail"
+	"time"
+)
+
+// NotificationSink queues emails to the owners of reservations changed by published events
+// and schedules reminders before their reservations start.
+type NotificationSink struct {
+	repo notification.Repository
+
+	now func() time.Time
+}
+
+func NewNotificationSink(repo notification.Repository) *NotificationSink {
+	return &NotificationSink{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *NotificationSink) Publish(ctx context.Context, e event.Event) error {
+	var kind string
+
+	switch e.Type {
+	case event.TypeReservationCreated, event.TypeReservationRestored:
+		kind = notification.KindConfirmation
+	case event.TypeReservationUpdated:
+		kind = notification.KindChange
+	case event.TypeReservationCancelled:
+		kind = notification.KindCancellation
+	default:
+		return nil
+	}
+
+	var data reservation.Reservation
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return err
+	}
+
+	if data.UserID == "" {
+		return nil
+	}
+
+	pref, err := s.repo.GetPreference(ctx, data.UserID)
+	if errors.Is(err, notification.ErrorNotFound) {
+		pref = notification.DefaultPreference(data.UserID)
+	} else if err != nil {
+		return err
+	}
+
+	recipient := pref.Recipient()
+	if recipient == "" {
+		return s.repo.SkipReminders(ctx, data.ID)
+	}
+
+	n := notification.Notification{
+		Kind:          kind,
+		ReservationID: data.ID,
+		EventID:       e.ID,
+		UserID:        data.UserID,
+		Recipient:     recipient,
+		SendAt:        s.now(),
+	}
+
+	if err := s.repo.Enqueue(ctx, n); err != nil {
+		return err
+	}
+
+	if kind == notification.KindCancellation {
+		return s.repo.SkipReminders(ctx, data.ID)
+	}
+
+	// Reservations starting too soon to be reminded of drop the reminder of their previous time.
+	at, ok := pref.Reminder(data.StartTime)
+	if !ok || !at.After(s.now()) {
+		return s.repo.SkipReminders(ctx, data.ID)
+	}
+
+	n.Kind = notification.KindReminder
+	n.SendAt = at
+
+	return s.repo.ScheduleReminder(ctx, n)
+}
+
+// Notifier sends queued notifications by email, describing reservations as they are when sent.
+type Notifier struct {
+	repo            notification.Repository
+	reservationRepo reservation.Repository
+	roomRepo        room.Repository
+	sender          mail.Sender
+	from            string
+	interval        time.Duration
+	batch           int
+
+	now func() time.Time
+}
+
+// NewNotifier creates a notifier sending from the from address. roomRepo may be nil,
+// rooms are then named after their id.
+func NewNotifier(repo notification.Repository, reservationRepo reservation.Repository, roomRepo room.Repository,
+	sender mail.Sender, from string, interval time.Duration) *Notifier {
+	return &Notifier{
+		repo:            repo,
+		reservationRepo: reservationRepo,
+		roomRepo:        roomRepo,
+		sender:          sender,
+		from:            from,
+		interval:        interval,
+		batch:           20,
+		now:             time.Now,
+	}
+}
+
+// Run sends due notifications every interval until ctx is done.
+func (n *Notifier) Run(ctx context.Context) {
+	poll(ctx, n.interval, n.batch, "error sending notifications", func(ctx context.Context, limit int) (int, error) {
+		return n.repo.Send(ctx, limit, n.Send)
+	})
+}
+
+// Send emails a notification with the reservation attached as an iCalendar event.
+// Notifications outdated by later changes, and those of users who opted out since they were queued, are skipped.
+func (n *Notifier) Send(ctx context.Context, data notification.Notification) error {
+	r, err := n.reservationRepo.Get(ctx, data.ReservationID)
+	if err != nil {
+		if errors.Is(err, reservation.ErrorNotFound) {
+			return fmt.Errorf("%w: %w", notification.ErrorSkipped, err)
+		}
+
+		return err
+	}
+
+	if data.Kind != notification.KindCancellation && r.Cancelled() {
+		return fmt.Errorf("%w: %w", notification.ErrorSkipped, reservation.ErrorCancelled)
+	}
+
+	if data.Kind == notification.KindReminder && !r.StartTime.After(n.now()) {
+		return fmt.Errorf("%w: reservation has started", notification.ErrorSkipped)
+	}
+
+	pref, err := n.repo.GetPreference(ctx, data.UserID)
+	if errors.Is(err, notification.ErrorNotFound) {
+		pref = notification.DefaultPreference(data.UserID)
+	} else if err != nil {
+		return err
+	}
+
+	recipient := pref.Recipient()
+	if recipient == "" {
+		return fmt.Errorf("%w: user is not notified", notification.ErrorSkipped)
+	}
+
+	roomName := "Room " + r.RoomID
+	if n.roomRepo != nil {
+		if data, err := n.roomRepo.Get(ctx, r.RoomID); err == nil && data.Name != "" {
+			roomName = data.Name
+		}
+	}
+
+	content, err := notification.Render(data.Kind, notification.TemplateData{Reservation: r, Room: roomName})
+	if err != nil {
+		return err
+	}
+
+	var ics bytes.Buffer
+	if err := calendar.New(roomName, []reservation.Reservation{r}).Encode(&ics); err != nil {
+		return err
+	}
+
+	return n.sender.Send(ctx, mail.Message{
+		From:    n.from,
+		To:      recipient,
+		Subject: content.Subject,
+		Text:    content.Text,
+		HTML:    content.HTML,
+		Attachments: []mail.Attachment{
+			{Filename: "reservation.ics", ContentType: "text/calendar; charset=utf-8", Data: ics.Bytes()},
+		},
+		Date: n.now(),
+	})
+}
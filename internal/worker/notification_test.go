@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	netmail "net/mail"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/notification"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/pkg/mail"
+	"room-reservation/pkg/mail/mailtest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notificationRepository struct {
+	notification.Repository
+
+	preferences map[string]notification.Preference
+	queued      []notification.Notification
+	skipped     []string
+}
+
+func (r *notificationRepository) GetPreference(ctx context.Context, userID string) (notification.Preference, error) {
+	p, ok := r.preferences[userID]
+	if !ok {
+		return notification.Preference{}, notification.ErrorNotFound
+	}
+
+	return p, nil
+}
+
+func (r *notificationRepository) Enqueue(ctx context.Context, data notification.Notification) error {
+	r.queued = append(r.queued, data)
+	return nil
+}
+
+func (r *notificationRepository) ScheduleReminder(ctx context.Context, data notification.Notification) error {
+	r.queued = append(r.queued, data)
+	return nil
+}
+
+func (r *notificationRepository) SkipReminders(ctx context.Context, reservationID string) error {
+	r.skipped = append(r.skipped, reservationID)
+	return nil
+}
+
+type notifierReservationRepository struct {
+	reservation.Repository
+
+	data reservation.Reservation
+}
+
+func (r *notifierReservationRepository) Get(ctx context.Context, ID string) (reservation.Reservation, error) {
+	if ID != r.data.ID {
+		return reservation.Reservation{}, reservation.ErrorNotFound
+	}
+
+	return r.data, nil
+}
+
+func TestNotificationSink(t *testing.T) {
+	now := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+
+	data := reservation.Reservation{
+		ID:        "abc",
+		RoomID:    "1",
+		UserID:    "alice@example.com",
+		StartTime: time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 9, 2, 11, 0, 0, 0, time.UTC),
+	}
+
+	publish := func(t *testing.T, repo *notificationRepository, eventType string, data reservation.Reservation) {
+		payload, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		s := NewNotificationSink(repo)
+		s.now = func() time.Time { return now }
+
+		err = s.Publish(context.Background(), event.Event{ID: 1, Type: eventType, ReservationID: data.ID, Data: payload})
+		require.NoError(t, err, "could not publish event")
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		repo := &notificationRepository{}
+		publish(t, repo, event.TypeReservationCreated, data)
+
+		require.Len(t, repo.queued, 2)
+		assert.Equal(t, notification.KindConfirmation, repo.queued[0].Kind)
+		assert.Equal(t, "alice@example.com", repo.queued[0].Recipient)
+		assert.Equal(t, notification.KindReminder, repo.queued[1].Kind)
+		assert.Equal(t, data.StartTime.Add(-notification.DefaultReminderMinutes*time.Minute), repo.queued[1].SendAt)
+	})
+
+	t.Run("Starting soon", func(t *testing.T) {
+		repo := &notificationRepository{}
+
+		soon := data
+		soon.StartTime = now.Add(5 * time.Minute)
+		publish(t, repo, event.TypeReservationUpdated, soon)
+
+		require.Len(t, repo.queued, 1)
+		assert.Equal(t, notification.KindChange, repo.queued[0].Kind)
+		assert.Equal(t, []string{"abc"}, repo.skipped, "expected previous reminder to be skipped")
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		repo := &notificationRepository{}
+		publish(t, repo, event.TypeReservationCancelled, data)
+
+		require.Len(t, repo.queued, 1)
+		assert.Equal(t, notification.KindCancellation, repo.queued[0].Kind)
+		assert.Equal(t, []string{"abc"}, repo.skipped)
+	})
+
+	t.Run("Preferences", func(t *testing.T) {
+		repo := &notificationRepository{preferences: map[string]notification.Preference{
+			"alice@example.com": {UserID: "alice@example.com", Email: "a@example.com", ReminderMinutes: 30},
+		}}
+		publish(t, repo, event.TypeReservationCreated, data)
+
+		require.Len(t, repo.queued, 2)
+		assert.Equal(t, "a@example.com", repo.queued[0].Recipient)
+		assert.Equal(t, data.StartTime.Add(-30*time.Minute), repo.queued[1].SendAt)
+	})
+
+	t.Run("Opted out", func(t *testing.T) {
+		repo := &notificationRepository{preferences: map[string]notification.Preference{
+			"alice@example.com": {UserID: "alice@example.com", OptOut: true},
+		}}
+		publish(t, repo, event.TypeReservationCreated, data)
+
+		assert.Empty(t, repo.queued)
+	})
+
+	t.Run("Without user", func(t *testing.T) {
+		repo := &notificationRepository{}
+
+		anonymous := data
+		anonymous.UserID = ""
+		publish(t, repo, event.TypeReservationCreated, anonymous)
+
+		assert.Empty(t, repo.queued)
+	})
+}
+
+func TestNotifierSend(t *testing.T) {
+	now := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+
+	server := mailtest.NewServer()
+	defer server.Close()
+
+	sender, err := mail.NewSMTPSender(server.Addr, "", "")
+	require.NoError(t, err, "could not create sender")
+
+	reservations := &notifierReservationRepository{data: reservation.Reservation{
+		ID:        "abc",
+		RoomID:    "1",
+		UserID:    "alice@example.com",
+		StartTime: time.Date(2024, 9, 2, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 9, 2, 11, 0, 0, 0, time.UTC),
+		UpdatedAt: now,
+	}}
+
+	n := NewNotifier(&notificationRepository{}, reservations, nil, sender, "rooms@example.com", time.Second)
+	n.now = func() time.Time { return now }
+
+	confirmation := notification.Notification{Kind: notification.KindConfirmation, ReservationID: "abc", UserID: "alice@example.com"}
+
+	err = n.Send(context.Background(), confirmation)
+	require.NoError(t, err, "could not send notification")
+
+	received := <-server.Messages
+	assert.Equal(t, []string{"alice@example.com"}, received.To)
+
+	msg, err := netmail.ReadMessage(bytes.NewReader(received.Data))
+	require.NoError(t, err, "could not read message")
+	assert.Contains(t, msg.Header.Get("Subject"), "Room 1")
+	assert.Contains(t, string(received.Data), `filename=reservation.ics`)
+
+	err = n.Send(context.Background(), notification.Notification{Kind: notification.KindReminder, ReservationID: "other", UserID: "alice@example.com"})
+	assert.ErrorIs(t, err, notification.ErrorSkipped, "expected notification of missing reservation to be skipped")
+
+	cancelledAt := now
+	reservations.data.CancelledAt = &cancelledAt
+
+	err = n.Send(context.Background(), notification.Notification{Kind: notification.KindReminder, ReservationID: "abc", UserID: "alice@example.com"})
+	assert.ErrorIs(t, err, notification.ErrorSkipped, "expected reminder of cancelled reservation to be skipped")
+
+	err = n.Send(context.Background(), notification.Notification{Kind: notification.KindCancellation, ReservationID: "abc", UserID: "alice@example.com"})
+	require.NoError(t, err, "could not send cancellation")
+
+	received = <-server.Messages
+	assert.Contains(t, string(received.Data), "Reservation cancelled")
+
+	select {
+	case <-server.Messages:
+		t.Fatal("expected skipped notifications not to be sent")
+	default:
+	}
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"room-reservation/pkg/log"
+	"time"
+)
+
+// poll calls handle every interval until ctx is done, handle taking up to batch rows at a time.
+// Full batches are followed up right away, more rows are likely to be waiting. Errors are logged with msg.
+func poll(ctx context.Context, interval time.Duration, batch int, msg string, handle func(ctx context.Context, limit int) (int, error)) {
+	logger := log.LoggerFromContext(ctx)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := handle(ctx, batch)
+		if err != nil && ctx.Err() == nil {
+			logger.Err(err).Caller().Msg(msg)
+		}
+
+		if n == batch {
+			timer.Reset(0)
+		} else {
+			timer.Reset(interval)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"room-reservation/internal/domain/event"
+	"room-reservation/pkg/log"
+	"time"
+)
+
+// Relay publishes events recorded in the outbox to a sink.
+type Relay struct {
+	outbox   event.Outbox
+	sink     event.Sink
+	interval time.Duration
+	batch    int
+}
+
+func NewRelay(outbox event.Outbox, sink event.Sink, interval time.Duration) *Relay {
+	return &Relay{
+		outbox:   outbox,
+		sink:     sink,
+		interval: interval,
+		batch:    100,
+	}
+}
+
+// Run relays pending events every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	poll(ctx, r.interval, r.batch, "error relaying outbox events", func(ctx context.Context, limit int) (int, error) {
+		return r.outbox.Relay(ctx, limit, r.sink.Publish)
+	})
+}
+
+// LogSink writes published events to the log.
+type LogSink struct{}
+
+func (LogSink) Publish(ctx context.Context, e event.Event) error {
+	log.LoggerFromContext(ctx).Info().
+		Int64("event_id", e.ID).
+		Str("event_type", e.Type).
+		Str("reservation_id", e.ReservationID).
+		Msg("reservation event")
+
+	return nil
+}
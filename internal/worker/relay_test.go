@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"room-reservation/internal/domain/event"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryOutbox struct {
+	mu        sync.Mutex
+	pending   []event.Event
+	published []event.Event
+}
+
+func (o *memoryOutbox) Relay(ctx context.Context, limit int, publish func(context.Context, event.Event) error) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.pending) == 0 {
+		return 0, nil
+	}
+
+	e := o.pending[0]
+	e.Attempts++
+
+	if err := publish(ctx, e); err != nil {
+		o.pending[0] = e
+		return 1, nil
+	}
+
+	o.pending = o.pending[1:]
+	o.published = append(o.published, e)
+
+	return 1, nil
+}
+
+func (o *memoryOutbox) Prune(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestRelay(t *testing.T) {
+	outbox := &memoryOutbox{
+		pending: []event.Event{
+			{ID: 1, Type: event.TypeReservationCreated, ReservationID: "1"},
+			{ID: 2, Type: event.TypeReservationCancelled, ReservationID: "1"},
+		},
+	}
+
+	failures := 2
+	sink := event.SinkFunc(func(ctx context.Context, e event.Event) error {
+		if failures > 0 {
+			failures--
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	relay := NewRelay(outbox, sink, time.Millisecond)
+	relay.batch = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go relay.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		return len(outbox.published) == 2
+	}, time.Second, time.Millisecond, "expected events to be published")
+
+	require.Equal(t, int64(1), outbox.published[0].ID, "expected events to be published in order")
+	require.Equal(t, 3, outbox.published[0].Attempts, "expected failed event to be retried")
+}
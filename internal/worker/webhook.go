@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/webhook"
+	"strconv"
+	"time"
+)
+
+// WebhookSink queues deliveries of published events to matching webhook subscriptions.
+type WebhookSink struct {
+	repo webhook.Repository
+}
+
+func NewWebhookSink(repo webhook.Repository) *WebhookSink {
+	return &WebhookSink{
+		repo: repo,
+	}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, e event.Event) error {
+	return s.repo.Enqueue(ctx, e)
+}
+
+// WebhookDispatcher sends queued webhook deliveries signed with the secret of their subscription.
+type WebhookDispatcher struct {
+	repo     webhook.Repository
+	client   *http.Client
+	interval time.Duration
+	batch    int
+
+	now func() time.Time
+}
+
+func NewWebhookDispatcher(repo webhook.Repository, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:     repo,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: interval,
+		batch:    20,
+		now:      time.Now,
+	}
+}
+
+// Run sends due deliveries every interval until ctx is done.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	poll(ctx, d.interval, d.batch, "error delivering webhooks", func(ctx context.Context, limit int) (int, error) {
+		return d.repo.Deliver(ctx, limit, d.Send)
+	})
+}
+
+// Send posts a delivery to the subscription url, any response other than 2xx is a failure.
+func (d *WebhookDispatcher) Send(ctx context.Context, sub webhook.Subscription, delivery webhook.Delivery) webhook.Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return webhook.Result{Err: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "room-reservation-webhook")
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(sub.Secret, d.now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return webhook.Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return webhook.Result{ResponseStatus: resp.StatusCode, Err: fmt.Errorf("unexpected response status %d", resp.StatusCode)}
+	}
+
+	return webhook.Result{ResponseStatus: resp.StatusCode}
+}
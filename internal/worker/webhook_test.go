@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/internal/domain/webhook"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcherSend(t *testing.T) {
+	now := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+
+	var status atomic.Int32
+	status.Store(http.StatusNoContent)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer receiver.Close()
+
+	d := NewWebhookDispatcher(nil, time.Second)
+	d.now = func() time.Time { return now }
+
+	sub := webhook.Subscription{ID: "1", URL: receiver.URL, Secret: "secret"}
+	delivery := webhook.Delivery{ID: 7, EventType: "ReservationCreated", Payload: []byte(`{"id":1}`)}
+
+	res := d.Send(context.Background(), sub, delivery)
+	require.NoError(t, res.Err, "expected delivery to succeed")
+	assert.Equal(t, http.StatusNoContent, res.ResponseStatus)
+
+	req := <-requests
+	assert.Equal(t, `{"id":1}`, string(req.body))
+	assert.Equal(t, "ReservationCreated", req.header.Get(webhook.EventHeader))
+	assert.Equal(t, "7", req.header.Get(webhook.DeliveryHeader))
+
+	err := webhook.Verify("secret", req.header.Get(webhook.SignatureHeader), req.body, time.Minute, now)
+	assert.NoError(t, err, "expected delivery to be signed")
+
+	status.Store(http.StatusInternalServerError)
+
+	res = d.Send(context.Background(), sub, delivery)
+	<-requests
+	assert.Error(t, res.Err, "expected delivery to fail")
+	assert.Equal(t, http.StatusInternalServerError, res.ResponseStatus)
+}
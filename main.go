@@ -1,54 +1,149 @@
+// Command room-reservation serves the reservation API and runs admin tasks against its database.
+// Without a command it serves the API:
+//
+//	app [command] [flags]
+//
+// Every command is configured with a YAML file given with -config, the environment variables of .env.example
+// and flags, see the config package.
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
-	"room-reservation/internal/handler"
-	"room-reservation/internal/repository"
-	"room-reservation/pkg/log"
-	"room-reservation/pkg/server"
+	"path/filepath"
+	"room-reservation/internal/repository/postgres"
+	"room-reservation/pkg/config"
 	"syscall"
-	"time"
 )
 
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+// commands are listed in the order of the usage, they are set in init as they refer to the list themselves.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "", "serve the API, the default command", serve},
+		{"migrate", "up | down [n] | status", "apply, revert or list schema migrations", migrateCommand},
+		{"seed", "[-file rooms.json]", "create or replace rooms", seed},
+		{"export", "[-room id] [-format json|ics] [-all]", "write reservations to stdout", export},
+		{"import", "-file export.ics -mapping rooms.json [-tz zone] [-until date] [-dry-run] [-json]", "create reservations from an iCalendar file", importCommand},
+		{"purge", "[-older-than 720h]", "delete reservations cancelled long ago", purge},
+		{"check-config", "", "validate the configuration and connect to the database", checkConfig},
+	}
+}
+
 func main() {
-	logger := log.LoggerFromContext(context.Background())
+	name := "serve"
+	args := os.Args[1:]
+
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	fmt.Println("Press Ctrl+C to exit")
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage()
+		return
+	}
+
+	i := findCommand(name)
+	if i < 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	cmd := commands[i]
 
-	connString := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_USERNAME"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	err := cmd.run(ctx, args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
 
-	reservationRepo, err := repository.NewReservationRepository(context.Background(), connString)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("error intializing reservation repository")
+		fmt.Fprintf(os.Stderr, "%s: %s\n", cmd.name, err)
+		os.Exit(1)
 	}
+}
 
-	reservationHTTPHandler := handler.NewReservationHandler(reservationRepo)
+func findCommand(name string) int {
+	for i, c := range commands {
+		if c.name == name {
+			return i
+		}
+	}
 
-	httpServer := server.New(reservationHTTPHandler.HTTP, os.Getenv("APP_PORT"))
+	return -1
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [command] [flags]\n\nCommands:\n", filepath.Base(os.Args[0]))
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", c.name, c.summary)
+	}
+}
 
-	fmt.Println("Swagger is accessible at http://localhost:" + os.Getenv("APP_PORT") + "/swagger/index.html")
+// flagSet holds the flags of a command along with the flags of every setting.
+type flagSet struct {
+	*flag.FlagSet
+	config *config.Flags
+}
+
+// flags returns the flag set of the command, parsed by parse.
+func flags(name string) *flagSet {
+	c := commands[findCommand(name)]
 
-	if err := httpServer.Start(); err != nil {
-		logger.Fatal().Err(err).Msg("error starting http server")
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s %s\n\n%s\n", filepath.Base(os.Args[0]), c.name, c.usage, c.summary)
+		fs.PrintDefaults()
 	}
 
-	<-stop
-	fmt.Println("shutting down server")
+	return &flagSet{FlagSet: fs, config: config.RegisterFlags(fs)}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// parse parses args, expecting at most maxArgs arguments after the flags, and loads the config.
+func (fs *flagSet) parse(args []string, maxArgs int) (config.Config, error) {
+	if err := fs.Parse(args); err != nil {
+		return config.Config{}, err
+	}
 
-	reservationRepo.Close()
+	if fs.NArg() > maxArgs {
+		fs.Usage()
+		return config.Config{}, flag.ErrHelp
+	}
+
+	cfg, err := fs.config.Load()
+	if err != nil {
+		return cfg, fmt.Errorf("invalid configuration:\n%w", err)
+	}
 
-	if err := httpServer.Stop(ctx); err != nil {
-		logger.Fatal().Err(err).Msg("error stopping server")
+	return cfg, nil
+}
+
+func openDB(ctx context.Context, cfg config.Config) (*postgres.DB, error) {
+	db, err := postgres.New(ctx, cfg.Database.URL(), postgres.WithPoolConfig(postgres.PoolConfig{
+		MaxConns:          int32(cfg.Database.MaxConns),
+		MinConns:          int32(cfg.Database.MinConns),
+		MaxConnLifetime:   cfg.Database.MaxConnLifetime,
+		MaxConnIdleTime:   cfg.Database.MaxConnIdleTime,
+		HealthCheckPeriod: cfg.Database.HealthCheckPeriod,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("error intializing database: %w", err)
 	}
 
-	fmt.Println("server successfully shutdown")
+	return db, nil
 }
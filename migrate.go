@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"room-reservation/internal/repository/postgres"
+	"strconv"
+	"text/tabwriter"
+)
+
+func migrateCommand(ctx context.Context, args []string) error {
+	fs := flags("migrate")
+	cfg, err := fs.parse(args, 2)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	db, err := openDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch fs.Arg(0) {
+	case "up":
+		n, err := db.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d migrations applied\n\n", n)
+	case "down":
+		steps := 1
+		if fs.NArg() == 2 {
+			if steps, err = strconv.Atoi(fs.Arg(1)); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations %q", fs.Arg(1))
+			}
+		}
+
+		n, err := db.MigrateDown(ctx, steps)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d migrations reverted\n\n", n)
+	case "status":
+	default:
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	return migrationStatus(ctx, db)
+}
+
+// migrationStatus lists the migrations with the ones applied to the database.
+func migrationStatus(ctx context.Context, db *postgres.DB) error {
+	migrations, err := postgres.Migrations()
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := db.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+
+	for _, m := range migrations {
+		status := "pending"
+		switch {
+		case m.Version == version && dirty:
+			status = "dirty"
+		case m.Version <= version:
+			status = "applied"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, status)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// CalendarFeed is a secret calendar URL, to subscribe to without headers.
+type CalendarFeed struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	RoomID    string    `json:"room_id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateCalendarFeed creates a feed of a room, or of the reservations of the user of the client when roomID is empty.
+func (c *Client) CreateCalendarFeed(ctx context.Context, roomID string) (CalendarFeed, error) {
+	var res CalendarFeed
+
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "calendar/feeds",
+		body:   map[string]string{"room_id": roomID},
+	}, &res)
+
+	return res, err
+}
+
+// DeleteCalendarFeed revokes a feed, only its creator or an admin may do so.
+func (c *Client) DeleteCalendarFeed(ctx context.Context, token string) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "calendar/feeds/" + token,
+	}, nil)
+
+	return err
+}
+
+// FeedCalendar returns the iCalendar file behind a feed, failing with ErrorFeedNotFound when it was revoked.
+func (c *Client) FeedCalendar(ctx context.Context, token string) ([]byte, error) {
+	_, body, err := c.send(ctx, request{
+		method: http.MethodGet,
+		path:   "calendar/" + token + ".ics",
+	})
+
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+		e.err = ErrorFeedNotFound
+	}
+
+	return body, err
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ChatChannelRequest posts events to an incoming webhook of a chat. Empty RoomIDs, Buildings and EventTypes
+// match every event.
+type ChatChannelRequest struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	RoomIDs    []string `json:"room_ids"`
+	Buildings  []string `json:"buildings"`
+	EventTypes []string `json:"event_types"`
+}
+
+type ChatChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// URL is only returned in full when the channel is created, the path of incoming webhook URLs is a secret.
+	URL        string    `json:"url"`
+	RoomIDs    []string  `json:"room_ids"`
+	Buildings  []string  `json:"buildings"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateChatChannel creates a chat channel. Chat channel endpoints require an admin API key.
+func (c *Client) CreateChatChannel(ctx context.Context, req ChatChannelRequest) (ChatChannel, error) {
+	var res ChatChannel
+
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "chat/channels",
+		body:   req,
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) ListChatChannels(ctx context.Context) ([]ChatChannel, error) {
+	res := []ChatChannel{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "chat/channels",
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) GetChatChannel(ctx context.Context, ID string) (ChatChannel, error) {
+	var res ChatChannel
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "chat/channels/" + ID,
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) DeleteChatChannel(ctx context.Context, ID string) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "chat/channels/" + ID,
+	}, nil)
+
+	return err
+}
@@ -0,0 +1,243 @@
+// Package client calls the /api/v1 endpoints of the service with typed requests and responses.
+// Live boards and CalDAV are left to WebSocket and CalDAV clients.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The headers identifying callers, the client doesn't import the router of the service to stay free of its logger.
+const (
+	apiKeyHeader = "X-API-Key"
+	userIDHeader = "X-User-ID"
+)
+
+// Client calls the API on behalf of a user, an admin or both.
+type Client struct {
+	baseURL *url.URL
+	http    *http.Client
+	apiKey  string
+	userID  string
+
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the client sending requests, http.DefaultClient by default.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.http = c
+	}
+}
+
+// WithAPIKey sets the API key sent with every request, admin endpoints require one.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithUserID sets the user requests are made for, reservations are booked for them.
+func WithUserID(ID string) Option {
+	return func(c *Client) {
+		c.userID = ID
+	}
+}
+
+// WithRetry sets how many times a request is attempted and the backoff before the second attempt,
+// which doubles with every attempt. Requests are attempted 3 times starting with 200ms by default.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.backoff = backoff
+	}
+}
+
+// New creates a client of the service at baseURL, like http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/v1/")
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:     u,
+		http:        http.DefaultClient,
+		maxAttempts: 3,
+		backoff:     200 * time.Millisecond,
+		maxBackoff:  30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.maxAttempts < 1 {
+		c.maxAttempts = 1
+	}
+
+	return c, nil
+}
+
+// request describes a call to the API, path is relative to /api/v1.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+	// conflict is the error reported by 409 Conflict responses, which have no body.
+	conflict error
+}
+
+// do sends req and decodes the data of the response into out, which may be nil.
+// It returns the response with its body read.
+func (c *Client) do(ctx context.Context, req request, out any) (*http.Response, error) {
+	resp, body, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent && len(body) > 0 {
+		envelope := struct {
+			Data any `json:"data"`
+		}{Data: out}
+
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return nil, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// send sends req, retrying rate limited requests and idempotent requests failing with server or network errors.
+// It returns the response with its body read, or an *Error for responses other than 2xx.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, []byte, error) {
+	var payload []byte
+
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	u := c.baseURL.JoinPath(req.path)
+	u.RawQuery = req.query.Encode()
+
+	for attempt := 1; ; attempt++ {
+		resp, body, err := c.attempt(ctx, req, u.String(), payload)
+
+		retryAfter, retry := c.retryAfter(req.method, attempt, resp, err)
+		if !retry {
+			return resp, body, err
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, req request, u string, payload []byte) (*http.Response, []byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, req.method, u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for k, v := range req.header {
+		r.Header[k] = v
+	}
+
+	if payload != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.apiKey != "" {
+		r.Header.Set(apiKeyHeader, c.apiKey)
+	}
+
+	if c.userID != "" {
+		r.Header.Set(userIDHeader, c.userID)
+	}
+
+	resp, err := c.http.Do(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, data, newError(resp, data, req.conflict)
+	}
+
+	return resp, data, nil
+}
+
+// retryAfter reports whether to make another attempt and how long to wait before it.
+func (c *Client) retryAfter(method string, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if err == nil || attempt >= c.maxAttempts {
+		return 0, false
+	}
+
+	backoff := min(c.backoff<<(attempt-1), c.maxBackoff)
+
+	if resp == nil {
+		// the request may have been processed before the connection broke
+		return backoff, idempotent(method) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			backoff = time.Duration(seconds) * time.Second
+		}
+
+		return backoff, backoff <= c.maxBackoff
+	case resp.StatusCode >= 500:
+		return backoff, idempotent(method)
+	}
+
+	return 0, false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
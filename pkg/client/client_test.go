@@ -0,0 +1,344 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/reservation"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/handler"
+	"room-reservation/internal/stream"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reservationRepository struct {
+	reservation.Repository
+
+	data   map[string]reservation.Reservation
+	listed []reservation.ListOptions
+}
+
+func (r *reservationRepository) Create(ctx context.Context, data reservation.Reservation) (string, error) {
+	for _, res := range r.data {
+		if res.RoomID == data.RoomID && !res.Cancelled() && res.Overlaps(data) {
+			return "", reservation.ErrorOverlaps
+		}
+	}
+
+	data.ID = strconv.Itoa(len(r.data) + 1)
+	r.data[data.ID] = data
+
+	return data.ID, nil
+}
+
+func (r *reservationRepository) Get(ctx context.Context, ID string) (reservation.Reservation, error) {
+	data, ok := r.data[ID]
+	if !ok {
+		return reservation.Reservation{}, reservation.ErrorNotFound
+	}
+
+	return data, nil
+}
+
+func (r *reservationRepository) List(ctx context.Context, roomID string, opts reservation.ListOptions) ([]reservation.Reservation, error) {
+	r.listed = append(r.listed, opts)
+
+	var res []reservation.Reservation
+	for _, data := range r.data {
+		if data.RoomID == roomID && (opts.IncludeCancelled || !data.Cancelled()) {
+			res = append(res, data)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, reservation.ErrorNotFoundForRoom
+	}
+
+	return res, nil
+}
+
+func (r *reservationRepository) Cancel(ctx context.Context, ID string, reason string) error {
+	data, ok := r.data[ID]
+	if !ok {
+		return reservation.ErrorNotFound
+	}
+
+	if data.Cancelled() {
+		return reservation.ErrorCancelled
+	}
+
+	now := time.Now()
+	data.CancelledAt = &now
+	data.CancelReason = reason
+	r.data[ID] = data
+
+	return nil
+}
+
+type eventRepository struct {
+	event.Repository
+
+	events []event.Event
+}
+
+func (r *eventRepository) Since(ctx context.Context, afterID int64, roomID string, limit int) ([]event.Event, error) {
+	var res []event.Event
+	for _, e := range r.events {
+		if e.ID > afterID && e.RoomID == roomID {
+			res = append(res, e)
+		}
+	}
+
+	return res, nil
+}
+
+func newClient(t *testing.T, h http.Handler, opts ...Option) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, opts...)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestReservations(t *testing.T) {
+	repo := &reservationRepository{data: map[string]reservation.Reservation{}}
+	h := handler.NewReservationHandler(repo, handler.WithAPIKeys("client-key")).HTTP
+	c := newClient(t, h, WithUserID("alice"))
+
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+	req := ReservationRequest{RoomID: "1", StartTime: start, EndTime: start.Add(time.Hour)}
+
+	ID, err := c.CreateReservation(ctx, req)
+	require.NoError(t, err, "could not create reservation")
+	assert.Equal(t, "1", ID)
+
+	_, err = c.CreateReservation(ctx, req)
+	assert.ErrorIs(t, err, ErrorOverlaps)
+
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusConflict, e.StatusCode)
+
+	got, err := c.GetReservation(ctx, ID)
+	require.NoError(t, err, "could not get reservation")
+	assert.Equal(t, "alice", got.UserID)
+	assert.Equal(t, StatusActive, got.Status)
+	assert.True(t, start.Equal(got.StartTime))
+	assert.True(t, start.Add(time.Hour).Equal(got.EndTime))
+
+	list, err := c.ListRoomReservations(ctx, "1", ListOptions{})
+	require.NoError(t, err, "could not list reservations")
+	assert.Len(t, list, 1)
+
+	err = newClient(t, h, WithUserID("bob")).CancelReservation(ctx, ID, "")
+	assert.ErrorIs(t, err, ErrorForbidden, "expected reservations of other users not to be cancelled")
+
+	require.NoError(t, c.CancelReservation(ctx, ID, "meeting moved"))
+
+	err = c.CancelReservation(ctx, ID, "")
+	assert.ErrorIs(t, err, ErrorCancelled)
+
+	list, err = c.ListRoomReservations(ctx, "1", ListOptions{})
+	require.NoError(t, err, "could not list reservations")
+	assert.Empty(t, list)
+
+	list, err = c.ListRoomReservations(ctx, "1", ListOptions{IncludeCancelled: true})
+	require.NoError(t, err, "could not list reservations")
+	require.Len(t, list, 1)
+	assert.Equal(t, StatusCancelled, list[0].Status)
+	assert.Equal(t, "meeting moved", list[0].CancelReason)
+
+	_, err = c.GetReservation(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrorNotFound)
+
+	other, err := newClient(t, h, WithUserID("bob")).CreateReservation(ctx, ReservationRequest{RoomID: "2", StartTime: start, EndTime: start.Add(time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, newClient(t, h, WithAPIKey("client-key")).CancelReservation(ctx, other, ""),
+		"expected callers with an API key to cancel any reservation")
+}
+
+type scheduleRepository struct {
+	schedule.Repository
+
+	hours map[string]schedule.Hours
+}
+
+func (r *scheduleRepository) ForRooms(ctx context.Context, roomIDs []string) (map[string]schedule.Hours, error) {
+	return r.hours, nil
+}
+
+func TestOpeningHours(t *testing.T) {
+	day := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+
+	office := schedule.Schedule{Scope: schedule.ScopeBuilding, Key: "HQ", TimeZone: "UTC"}
+	office.Weekly[day.Weekday()] = []schedule.Period{{Open: 8 * 60, Close: 18 * 60}}
+	schedules := &scheduleRepository{hours: map[string]schedule.Hours{"1": {office}}}
+
+	repo := &reservationRepository{data: map[string]reservation.Reservation{}}
+	h := handler.NewReservationHandler(schedule.Reservations(repo, schedules), handler.WithScheduleRepository(schedules))
+	c := newClient(t, h.HTTP, WithUserID("alice"))
+
+	ctx := context.Background()
+
+	_, err := c.CreateReservation(ctx, ReservationRequest{RoomID: "1", StartTime: day.Add(7 * time.Hour), EndTime: day.Add(9 * time.Hour)})
+	assert.ErrorIs(t, err, ErrorClosed)
+
+	_, err = c.CreateReservation(ctx, ReservationRequest{RoomID: "1", StartTime: day.Add(9 * time.Hour), EndTime: day.Add(10 * time.Hour)})
+	require.NoError(t, err, "could not create reservation within opening hours")
+
+	data, err := c.RoomAvailability(ctx, "1", day, day.AddDate(0, 0, 1))
+	require.NoError(t, err, "could not get availability")
+	assert.False(t, data.Available)
+	assert.Equal(t, []Slot{
+		{Start: day.Add(8 * time.Hour), End: day.Add(9 * time.Hour)},
+		{Start: day.Add(10 * time.Hour), End: day.Add(18 * time.Hour)},
+	}, data.Slots, "expected closed times to be left out")
+
+	data, err = c.RoomAvailability(ctx, "2", day, day.AddDate(0, 0, 1))
+	require.NoError(t, err, "could not get availability")
+	assert.True(t, data.Available, "expected rooms without schedule to be always open")
+
+	paris := time.FixedZone("CEST", 2*60*60)
+	repo.listed = nil
+
+	data, err = c.RoomAvailability(ctx, "1", day.In(paris), day.AddDate(0, 0, 1).In(paris))
+	require.NoError(t, err, "could not get availability")
+	assert.Len(t, data.Slots, 2)
+	require.Len(t, repo.listed, 1)
+	assert.Equal(t, time.UTC, repo.listed[0].From.Location(), "expected searches in UTC")
+	assert.Equal(t, day, repo.listed[0].From)
+	assert.Equal(t, day.AddDate(0, 0, 1), repo.listed[0].To)
+
+	_, err = c.RoomAvailability(ctx, "1", day, day.AddDate(2, 0, 0))
+
+	var e *Error
+	require.ErrorAs(t, err, &e, "expected searches over more than a year refused")
+	assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+}
+
+func TestErrorCodes(t *testing.T) {
+	c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+
+		res := map[string]any{"success": false, "message": "room is closed at that time"}
+		if r.URL.Path == "/api/v1/rooms/1" {
+			res = map[string]any{"success": false, "message": "salle introuvable", "code": "room_not_found"}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+
+	_, err := c.GetRoom(context.Background(), "1")
+	assert.ErrorIs(t, err, ErrorRoomNotFound, "expected the error told from its code")
+	assert.EqualError(t, err, "unexpected response status 400: salle introuvable")
+
+	_, err = c.GetRoom(context.Background(), "2")
+	assert.NotErrorIs(t, err, ErrorClosed, "expected messages without code not to be matched")
+}
+
+func TestRetry(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "1", "name": "Everest"}})
+		}
+	}), WithRetry(3, time.Millisecond))
+
+	data, err := c.GetRoom(context.Background(), "1")
+	require.NoError(t, err, "could not get room after retrying")
+	assert.Equal(t, "Everest", data.Name)
+	assert.EqualValues(t, 3, calls.Load())
+
+	calls.Store(1)
+
+	// POST requests may have been processed, they are not retried on server errors
+	_, err = c.CreateReservation(context.Background(), ReservationRequest{})
+
+	var e *Error
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, http.StatusServiceUnavailable, e.StatusCode)
+	assert.EqualValues(t, 2, calls.Load())
+
+	calls.Store(0)
+
+	// nothing is sent once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.GetRoom(ctx, "1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualValues(t, 0, calls.Load())
+}
+
+func TestStreamEvents(t *testing.T) {
+	snapshot := func(ID string) json.RawMessage {
+		data, err := json.Marshal(reservation.Reservation{ID: ID, RoomID: "1", StartTime: time.Now(), EndTime: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		return data
+	}
+
+	events := &eventRepository{events: []event.Event{
+		{ID: 1, Type: event.TypeReservationCreated, ReservationID: "a", RoomID: "1", Data: snapshot("a")},
+		{ID: 2, Type: event.TypeReservationCreated, ReservationID: "b", RoomID: "1", Data: snapshot("b")},
+	}}
+	broker := stream.NewBroker()
+
+	repo := &reservationRepository{data: map[string]reservation.Reservation{}}
+	h := handler.NewReservationHandler(repo, handler.WithEventStream(events, broker), handler.WithAPIKeys("client-key")).HTTP
+	c := newClient(t, h, WithAPIKey("client-key"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errDone := errors.New("done")
+
+	var received []Event
+	err := c.StreamEvents(ctx, "1", 1, func(e Event) error {
+		received = append(received, e)
+
+		if len(received) == 1 {
+			// the stream is subscribed before missed events are replayed
+			broker.Publish(ctx, event.Event{ID: 3, Type: event.TypeReservationCancelled, ReservationID: "c", RoomID: "1", Data: snapshot("c")})
+			return nil
+		}
+
+		return errDone
+	})
+	require.ErrorIs(t, err, errDone)
+
+	require.Len(t, received, 2)
+	assert.Equal(t, int64(2), received[0].ID)
+	assert.Equal(t, "b", received[0].Reservation.ID)
+	assert.Equal(t, int64(3), received[1].ID)
+	assert.Equal(t, EventReservationCancelled, received[1].Type)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("localhost:8080")
+	assert.Error(t, err)
+
+	c, err := New("http://localhost:8080/")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/api/v1/", c.baseURL.String())
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors returned by the service, matched with errors.Is. They are told from the code of error responses.
+var (
+	ErrorNotFound         = errors.New("reservation not found")
+	ErrorOverlaps         = errors.New("reservation overlaps with another")
+	ErrorCancelled        = errors.New("reservation is cancelled")
+	ErrorNotCancelled     = errors.New("reservation is not cancelled")
+	ErrorClosed           = errors.New("room is closed at that time")
+	ErrorInvalidTime      = errors.New("start_time must be before end_time")
+	ErrorRoomNotFound     = errors.New("room not found")
+	ErrorWebhookNotFound  = errors.New("webhook not found")
+	ErrorDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrorChannelNotFound  = errors.New("chat channel not found")
+	ErrorFeedNotFound     = errors.New("calendar feed not found")
+)
+
+var (
+	ErrorUnauthorized = errors.New("unauthorized")
+	ErrorForbidden    = errors.New("forbidden")
+	ErrorRateLimited  = errors.New("rate limited")
+)
+
+// codes are the codes of error responses of the errors above.
+var codes = map[string]error{
+	"reservation_not_found":     ErrorNotFound,
+	"reservation_overlaps":      ErrorOverlaps,
+	"reservation_cancelled":     ErrorCancelled,
+	"reservation_not_cancelled": ErrorNotCancelled,
+	"room_closed":               ErrorClosed,
+	"reservation_invalid_time":  ErrorInvalidTime,
+	"room_not_found":            ErrorRoomNotFound,
+	"webhook_not_found":         ErrorWebhookNotFound,
+	"delivery_not_found":        ErrorDeliveryNotFound,
+	"channel_not_found":         ErrorChannelNotFound,
+	"feed_not_found":            ErrorFeedNotFound,
+}
+
+// Error is a response other than 2xx. It wraps the error of the service when it could be told from the response.
+type Error struct {
+	StatusCode int
+	Message    string
+
+	err error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.err != nil {
+		msg = e.err.Error()
+	}
+
+	if msg == "" {
+		return fmt.Sprintf("unexpected response status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("unexpected response status %d: %s", e.StatusCode, msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// newError reads an error response. conflict is the error of 409 Conflict responses, which have no body.
+func newError(resp *http.Response, body []byte, conflict error) *Error {
+	e := &Error{StatusCode: resp.StatusCode}
+
+	var res struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	}
+	if json.Unmarshal(body, &res) == nil {
+		e.Message = res.Message
+	}
+
+	if err, ok := codes[res.Code]; ok {
+		e.err = err
+		return e
+	}
+
+	switch resp.StatusCode {
+	case http.StatusConflict:
+		e.err = conflict
+	case http.StatusUnauthorized:
+		e.err = ErrorUnauthorized
+	case http.StatusForbidden:
+		e.err = ErrorForbidden
+	case http.StatusTooManyRequests:
+		e.err = ErrorRateLimited
+	}
+
+	return e
+}
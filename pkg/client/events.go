@@ -0,0 +1,230 @@
+package client
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EventReservationCreated   = "ReservationCreated"
+	EventReservationUpdated   = "ReservationUpdated"
+	EventReservationCancelled = "ReservationCancelled"
+	EventReservationRestored  = "ReservationRestored"
+)
+
+// Event is a change to a reservation, with the reservation after the change.
+type Event struct {
+	ID            int64
+	Type          string
+	ReservationID string
+	RoomID        string
+	Actor         string
+	Reservation   Reservation
+	OccurredAt    time.Time
+}
+
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var res struct {
+		ID            int64           `json:"id"`
+		Type          string          `json:"type"`
+		ReservationID string          `json:"reservation_id"`
+		RoomID        string          `json:"room_id"`
+		Actor         string          `json:"actor"`
+		Data          json.RawMessage `json:"data"`
+		OccurredAt    time.Time       `json:"occurred_at"`
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+
+	// the data of events is a snapshot of the reservation with RFC 3339 times
+	var data struct {
+		ID           string     `json:"id"`
+		RoomID       string     `json:"room_id"`
+		UserID       string     `json:"user_id"`
+		StartTime    time.Time  `json:"start_time"`
+		EndTime      time.Time  `json:"end_time"`
+		CancelledAt  *time.Time `json:"cancelled_at"`
+		CancelReason string     `json:"cancel_reason"`
+		CancelledBy  string     `json:"cancelled_by"`
+	}
+
+	if len(res.Data) > 0 {
+		if err := json.Unmarshal(res.Data, &data); err != nil {
+			return err
+		}
+	}
+
+	*e = Event{
+		ID:            res.ID,
+		Type:          res.Type,
+		ReservationID: res.ReservationID,
+		RoomID:        res.RoomID,
+		Actor:         res.Actor,
+		OccurredAt:    res.OccurredAt,
+		Reservation: Reservation{
+			ID:           data.ID,
+			RoomID:       data.RoomID,
+			UserID:       data.UserID,
+			StartTime:    data.StartTime.UTC(),
+			EndTime:      data.EndTime.UTC(),
+			Status:       StatusActive,
+			CancelledAt:  data.CancelledAt,
+			CancelReason: data.CancelReason,
+			CancelledBy:  data.CancelledBy,
+		},
+	}
+
+	if data.CancelledAt != nil {
+		e.Reservation.Status = StatusCancelled
+	}
+
+	return nil
+}
+
+// StreamEvents calls fn with every change to reservations of a room, or of every room when roomID is empty,
+// until ctx is done or fn fails. Events after lastEventID are replayed first, pass 0 to only receive new events.
+// Dropped connections are resumed after the last received event, with the same backoff as other requests.
+// Events received shortly before a connection dropped may be received again, fn should skip the ids it has seen.
+//
+// The stream requires an API key and is subject to the timeout of the HTTP client, which should have none.
+func (c *Client) StreamEvents(ctx context.Context, roomID string, lastEventID int64, fn func(Event) error) error {
+	path := "events"
+	if roomID != "" {
+		path = "rooms/" + roomID + "/events"
+	}
+
+	for attempt := 1; ; attempt++ {
+		received, err := c.stream(ctx, path, &lastEventID, fn)
+		if received {
+			attempt = 1
+		}
+
+		var (
+			e    *Error
+			stop stopError
+		)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &stop):
+			return stop.err
+		case errors.As(err, &e) && e.StatusCode != http.StatusTooManyRequests:
+			// the request was rejected rather than dropped
+			return err
+		}
+
+		retryAfter, retry := c.retryAfter(http.MethodGet, attempt, nil, cmp.Or(err, io.ErrUnexpectedEOF))
+		if !retry {
+			if err == nil {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return fmt.Errorf("streaming events: %w", err)
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// stopError ends a stream without resuming it.
+type stopError struct {
+	err error
+}
+
+func (e stopError) Error() string {
+	return e.err.Error()
+}
+
+// stream reads events until the connection is closed, it reports whether any event was received.
+func (c *Client) stream(ctx context.Context, path string, lastEventID *int64, fn func(Event) error) (bool, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.JoinPath(path).String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	r.Header.Set("Accept", "text/event-stream")
+
+	if *lastEventID > 0 {
+		r.Header.Set("Last-Event-ID", strconv.FormatInt(*lastEventID, 10))
+	}
+
+	if c.apiKey != "" {
+		r.Header.Set(apiKeyHeader, c.apiKey)
+	}
+
+	if c.userID != "" {
+		r.Header.Set(userIDHeader, c.userID)
+	}
+
+	resp, err := c.http.Do(r)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, newError(resp, body, nil)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var (
+		received bool
+		data     strings.Builder
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line != "" {
+			// ids and event types are also part of the data, only the data is read
+			if v, ok := strings.CutPrefix(line, "data:"); ok {
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(v, " "))
+			}
+
+			continue
+		}
+
+		if data.Len() == 0 {
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+			return received, stopError{fmt.Errorf("decoding event: %w", err)}
+		}
+		data.Reset()
+
+		if err := fn(e); err != nil {
+			return received, stopError{err}
+		}
+
+		// events committed late are replayed along with some received already, the last id never goes back
+		*lastEventID = max(*lastEventID, e.ID)
+		received = true
+	}
+
+	return received, scanner.Err()
+}
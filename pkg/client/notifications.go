@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type NotificationPreference struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email,omitempty"`
+	// Recipient is where notifications are sent, empty when the user is not notified.
+	Recipient       string     `json:"recipient,omitempty"`
+	OptOut          bool       `json:"opt_out"`
+	ReminderMinutes int        `json:"reminder_minutes"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+// NotificationPreferenceRequest changes how the user of the client is notified.
+type NotificationPreferenceRequest struct {
+	// Email overrides the address notifications are sent to, by default they go to the user id when it is an email address.
+	Email  string `json:"email"`
+	OptOut bool   `json:"opt_out"`
+	// ReminderMinutes is how long before a reservation starts a reminder is sent, the service default when nil.
+	ReminderMinutes *int `json:"reminder_minutes"`
+}
+
+// GetNotificationPreference returns the preferences of the user of the client.
+func (c *Client) GetNotificationPreference(ctx context.Context) (NotificationPreference, error) {
+	var res NotificationPreference
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "users/me/notifications",
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) SaveNotificationPreference(ctx context.Context, req NotificationPreferenceRequest) (NotificationPreference, error) {
+	var res NotificationPreference
+
+	_, err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "users/me/notifications",
+		body:   req,
+	}, &res)
+
+	return res, err
+}
@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateTimeLayout is how the service formats reservation times, always in UTC.
+const dateTimeLayout = "02-01-2006 15:04"
+
+type dateTime struct {
+	time.Time
+}
+
+func (dt dateTime) MarshalJSON() ([]byte, error) {
+	if dt.IsZero() {
+		return []byte(`""`), nil
+	}
+
+	return json.Marshal(dt.UTC().Format(dateTimeLayout))
+}
+
+func (dt *dateTime) UnmarshalJSON(b []byte) (err error) {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		dt.Time = time.Time{}
+		return nil
+	}
+
+	dt.Time, err = time.Parse(dateTimeLayout, s)
+	return err
+}
+
+const (
+	StatusActive    = "active"
+	StatusCancelled = "cancelled"
+)
+
+// Reservation times are in UTC with minute precision.
+type Reservation struct {
+	ID           string
+	RoomID       string
+	UserID       string
+	StartTime    time.Time
+	EndTime      time.Time
+	Status       string
+	CancelledAt  *time.Time
+	CancelReason string
+	CancelledBy  string
+}
+
+func (r *Reservation) UnmarshalJSON(b []byte) error {
+	var res struct {
+		ID           string    `json:"id"`
+		RoomID       string    `json:"room_id"`
+		UserID       string    `json:"user_id"`
+		StartTime    dateTime  `json:"start_time"`
+		EndTime      dateTime  `json:"end_time"`
+		Status       string    `json:"status"`
+		CancelledAt  *dateTime `json:"cancelled_at"`
+		CancelReason string    `json:"cancel_reason"`
+		CancelledBy  string    `json:"cancelled_by"`
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+
+	*r = Reservation{
+		ID:           res.ID,
+		RoomID:       res.RoomID,
+		UserID:       res.UserID,
+		StartTime:    res.StartTime.Time,
+		EndTime:      res.EndTime.Time,
+		Status:       res.Status,
+		CancelReason: res.CancelReason,
+		CancelledBy:  res.CancelledBy,
+	}
+
+	if res.CancelledAt != nil {
+		r.CancelledAt = &res.CancelledAt.Time
+	}
+
+	return nil
+}
+
+// ReservationRequest books a room. Times are sent with minute precision.
+type ReservationRequest struct {
+	RoomID    string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+func (r ReservationRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RoomID    string   `json:"room_id,omitempty"`
+		StartTime dateTime `json:"start_time"`
+		EndTime   dateTime `json:"end_time"`
+	}{r.RoomID, dateTime{r.StartTime}, dateTime{r.EndTime}})
+}
+
+// CreateReservation books a room for the user of the client, failing with ErrorOverlaps when the time is taken
+// and ErrorClosed when the room is closed at that time.
+func (c *Client) CreateReservation(ctx context.Context, req ReservationRequest) (ID string, err error) {
+	resp, err := c.do(ctx, request{
+		method:   http.MethodPost,
+		path:     "reservations",
+		body:     req,
+		conflict: ErrorOverlaps,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Base(resp.Header.Get("Location")), nil
+}
+
+// ListOptions narrows down listed reservations, cancelled reservations are left out by default.
+type ListOptions struct {
+	IncludeCancelled bool
+}
+
+// ListRoomReservations lists the reservations of a room.
+func (c *Client) ListRoomReservations(ctx context.Context, roomID string, opts ListOptions) ([]Reservation, error) {
+	query := url.Values{}
+	if opts.IncludeCancelled {
+		query.Set("include_cancelled", "true")
+	}
+
+	res := []Reservation{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "reservations/room/" + roomID,
+		query:  query,
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) GetReservation(ctx context.Context, ID string) (Reservation, error) {
+	var res Reservation
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "reservations/" + ID,
+	}, &res)
+
+	return res, err
+}
+
+// UpdateReservation changes the room or times of a reservation, zero fields are left unchanged.
+// It fails with ErrorCancelled when the reservation is cancelled, ErrorClosed when the room is closed at the new time
+// and ErrorInvalidTime when a new time is on the wrong side of the one left unchanged.
+func (c *Client) UpdateReservation(ctx context.Context, ID string, req ReservationRequest) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodPatch,
+		path:   "reservations/" + ID,
+		body:   req,
+	}, nil)
+
+	return err
+}
+
+// CancelReservation frees the time of a reservation, failing with ErrorCancelled when it is already cancelled.
+func (c *Client) CancelReservation(ctx context.Context, ID string, reason string) error {
+	_, err := c.do(ctx, request{
+		method:   http.MethodDelete,
+		path:     "reservations/" + ID,
+		body:     map[string]string{"reason": reason},
+		conflict: ErrorCancelled,
+	}, nil)
+
+	return err
+}
+
+// RestoreReservation restores a cancelled reservation, failing with ErrorOverlaps when its time was taken since.
+func (c *Client) RestoreReservation(ctx context.Context, ID string) error {
+	_, err := c.do(ctx, request{
+		method:   http.MethodPost,
+		path:     "reservations/" + ID + "/restore",
+		conflict: ErrorOverlaps,
+	}, nil)
+
+	return err
+}
+
+// AuditEntry is a change made to a reservation, with snapshots of the reservation before and after it.
+type AuditEntry struct {
+	ID            int64           `json:"id"`
+	ReservationID string          `json:"reservation_id"`
+	Action        string          `json:"action"`
+	Actor         string          `json:"actor"`
+	RequestID     string          `json:"request_id,omitempty"`
+	Before        json.RawMessage `json:"before,omitempty"`
+	After         json.RawMessage `json:"after,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// ReservationHistory lists every change made to a reservation.
+func (c *Client) ReservationHistory(ctx context.Context, ID string) ([]AuditEntry, error) {
+	res := []AuditEntry{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "reservations/" + ID + "/history",
+	}, &res)
+
+	return res, err
+}
+
+// AuditFilter narrows down queried changes, zero fields match every change.
+type AuditFilter struct {
+	ReservationID string
+	Actor         string
+	Action        string
+	From          time.Time
+	To            time.Time
+	Limit         int
+}
+
+// QueryAudit queries changes of all reservations, it requires an admin API key.
+func (c *Client) QueryAudit(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	query := url.Values{}
+
+	for k, v := range map[string]string{"reservation_id": filter.ReservationID, "actor": filter.Actor, "action": filter.Action} {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.Format(time.RFC3339))
+	}
+
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.Format(time.RFC3339))
+	}
+
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	res := []AuditEntry{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "admin/audit",
+		query:  query,
+	}, &res)
+
+	return res, err
+}
+
+// ImportRequest creates reservations from the events of an iCalendar file.
+type ImportRequest struct {
+	Calendar string        `json:"calendar"`
+	Mapping  ImportMapping `json:"mapping"`
+	// TimeZone is used for event times without a time zone, UTC by default.
+	TimeZone string `json:"time_zone,omitempty"`
+	// Until limits the occurrences of recurring events, a year from now by default.
+	Until  *time.Time `json:"until,omitempty"`
+	DryRun bool       `json:"dry_run"`
+}
+
+// ImportMapping maps event locations to room ids, events at other locations are booked in DefaultRoomID if set.
+type ImportMapping struct {
+	Rooms         map[string]string `json:"rooms"`
+	DefaultRoomID string            `json:"default_room_id,omitempty"`
+}
+
+// ImportReport is the outcome of an import, with an item for every event or occurrence of a recurring event.
+type ImportReport struct {
+	DryRun    bool         `json:"dry_run"`
+	Created   int          `json:"created"`
+	Skipped   int          `json:"skipped"`
+	Conflicts int          `json:"conflicts"`
+	Failed    int          `json:"failed"`
+	Items     []ImportItem `json:"items"`
+}
+
+type ImportItem struct {
+	UID           string    `json:"uid"`
+	Summary       string    `json:"summary,omitempty"`
+	Location      string    `json:"location,omitempty"`
+	RoomID        string    `json:"room_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Status        string    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	ReservationID string    `json:"reservation_id,omitempty"`
+}
+
+// ImportReservations imports reservations from a calendar, it requires an admin API key.
+func (c *Client) ImportReservations(ctx context.Context, req ImportRequest) (ImportReport, error) {
+	var res ImportReport
+
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "reservations/import",
+		body:   req,
+	}, &res)
+
+	return res, err
+}
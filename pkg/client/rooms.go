@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Room struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Building string `json:"building"`
+	Capacity int    `json:"capacity"`
+}
+
+// ListRooms lists the rooms of a building, or every room when building is empty.
+func (c *Client) ListRooms(ctx context.Context, building string) ([]Room, error) {
+	query := url.Values{}
+	if building != "" {
+		query.Set("building", building)
+	}
+
+	res := []Room{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "rooms",
+		query:  query,
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) GetRoom(ctx context.Context, ID string) (Room, error) {
+	var res Room
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "rooms/" + ID,
+	}, &res)
+
+	return res, err
+}
+
+// SaveRoom creates or replaces a room, it requires an admin API key.
+func (c *Client) SaveRoom(ctx context.Context, data Room) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "rooms/" + data.ID,
+		body: map[string]any{
+			"name":     data.Name,
+			"building": data.Building,
+			"capacity": data.Capacity,
+		},
+	}, nil)
+
+	return err
+}
+
+// RoomCalendar returns the reservations of a room as an iCalendar file.
+func (c *Client) RoomCalendar(ctx context.Context, roomID string) ([]byte, error) {
+	_, body, err := c.send(ctx, request{
+		method: http.MethodGet,
+		path:   "rooms/" + roomID + "/calendar.ics",
+	})
+
+	return body, err
+}
+
+// Availability is the free time of a room between From and To.
+type Availability struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Available tells whether the room is open and free the whole time.
+	Available bool   `json:"available"`
+	Slots     []Slot `json:"slots"`
+}
+
+// Slot is a span of time, from Start up to End.
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// RoomAvailability lists the times between from and to the room is open and not reserved.
+func (c *Client) RoomAvailability(ctx context.Context, roomID string, from, to time.Time) (Availability, error) {
+	query := url.Values{}
+	query.Set("from", from.Format(time.RFC3339))
+	query.Set("to", to.Format(time.RFC3339))
+
+	var res Availability
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "rooms/" + roomID + "/availability",
+		query:  query,
+	}, &res)
+
+	return res, err
+}
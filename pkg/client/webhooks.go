@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WebhookRequest subscribes a URL to events, empty EventTypes and RoomIDs match every event.
+type WebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	RoomIDs    []string `json:"room_ids"`
+	// Secret signs deliveries, one is generated when empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Webhook is a subscription, its Secret is only returned when it is created.
+type Webhook struct {
+	ID           string     `json:"id"`
+	URL          string     `json:"url"`
+	EventTypes   []string   `json:"event_types"`
+	RoomIDs      []string   `json:"room_ids"`
+	Secret       string     `json:"secret,omitempty"`
+	Active       bool       `json:"active"`
+	FailureCount int        `json:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// WebhookDelivery is an attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// CreateWebhook subscribes a URL to events. Webhook endpoints require an admin API key.
+func (c *Client) CreateWebhook(ctx context.Context, req WebhookRequest) (Webhook, error) {
+	var res Webhook
+
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "webhooks",
+		body:   req,
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	res := []Webhook{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "webhooks",
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) GetWebhook(ctx context.Context, ID string) (Webhook, error) {
+	var res Webhook
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "webhooks/" + ID,
+	}, &res)
+
+	return res, err
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, ID string) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "webhooks/" + ID,
+	}, nil)
+
+	return err
+}
+
+// EnableWebhook enables a webhook disabled after failing deliveries.
+func (c *Client) EnableWebhook(ctx context.Context, ID string) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "webhooks/" + ID + "/enable",
+	}, nil)
+
+	return err
+}
+
+func (c *Client) ListWebhookDeliveries(ctx context.Context, webhookID string) ([]WebhookDelivery, error) {
+	res := []WebhookDelivery{}
+
+	_, err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "webhooks/" + webhookID + "/deliveries",
+	}, &res)
+
+	return res, err
+}
+
+// ReplayWebhookDelivery schedules a delivery to be attempted again.
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, webhookID string, deliveryID int64) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "webhooks/" + webhookID + "/deliveries/" + strconv.FormatInt(deliveryID, 10) + "/replay",
+	}, nil)
+
+	return err
+}
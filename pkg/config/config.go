@@ -0,0 +1,309 @@
+// Package config holds the settings of the service. They are read from a YAML file, the environment and flags,
+// each overriding the previous, on top of defaults.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is tagged with the key of every setting in the YAML file, its environment variable and its usage.
+// Settings tagged as secret are redacted when printed.
+type Config struct {
+	HTTP         HTTP         `yaml:"http"`
+	GRPC         GRPC         `yaml:"grpc"`
+	Database     Database     `yaml:"database"`
+	Log          Log          `yaml:"log"`
+	Tracing      Tracing      `yaml:"tracing"`
+	Auth         Auth         `yaml:"auth"`
+	RateLimit    RateLimit    `yaml:"rate_limit"`
+	Reservations Reservations `yaml:"reservations"`
+	Mail         Mail         `yaml:"mail"`
+	Shutdown     Shutdown     `yaml:"shutdown"`
+}
+
+type HTTP struct {
+	Port              string        `yaml:"port" env:"APP_PORT" usage:"port serving the API"`
+	PublicURL         string        `yaml:"public_url" env:"PUBLIC_URL" usage:"public URL of the service, used for links in chat messages"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" usage:"time allowed to read request headers"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" usage:"time allowed to read whole requests, unlimited when 0"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" usage:"time allowed to write responses, event streams excepted, unlimited when 0"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" usage:"time idle keep-alive connections are kept open"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES" usage:"maximum size of request headers in bytes"`
+	TLSCertFile       string        `yaml:"tls_cert_file" env:"TLS_CERT_FILE" usage:"certificate served over HTTPS, reloaded when changed, HTTP is served when empty"`
+	TLSKeyFile        string        `yaml:"tls_key_file" env:"TLS_KEY_FILE" usage:"private key of the TLS certificate"`
+	AllowedOrigins    []string      `yaml:"allowed_origins" env:"ALLOWED_ORIGINS" usage:"comma separated origins of web pages allowed to open WebSockets besides the service itself, like https://board.example.com"`
+	TrustedProxies    []string      `yaml:"trusted_proxies" env:"TRUSTED_PROXIES" usage:"comma separated addresses or CIDR ranges of reverse proxies whose X-Forwarded-For and X-Real-IP headers are trusted"`
+}
+
+type GRPC struct {
+	Port string `yaml:"port" env:"GRPC_PORT" usage:"port serving the reservation API over gRPC, disabled when empty"`
+}
+
+type Database struct {
+	Host     string `yaml:"host" env:"DB_HOST" usage:"database host"`
+	Port     string `yaml:"port" env:"DB_PORT" usage:"database port"`
+	Name     string `yaml:"name" env:"DB_NAME" usage:"database name"`
+	Username string `yaml:"username" env:"DB_USERNAME" usage:"database user"`
+	Password string `yaml:"password" env:"DB_PASSWORD" usage:"database password" secret:"true"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE" usage:"sslmode of the connection, like disable or verify-full"`
+
+	MaxConns          int           `yaml:"max_conns" env:"DB_MAX_CONNS" usage:"maximum size of the connection pool"`
+	MinConns          int           `yaml:"min_conns" env:"DB_MIN_CONNS" usage:"minimum size of the connection pool"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME" usage:"how long a connection is used before it is closed"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME" usage:"how long an idle connection is kept"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"DB_HEALTH_CHECK_PERIOD" usage:"how often idle connections are checked"`
+
+	AutoMigrate bool `yaml:"auto_migrate" env:"AUTO_MIGRATE" usage:"apply pending schema migrations when serving"`
+}
+
+// URL is the database in the URL form understood by pgx.
+func (d Database) URL() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.Username, d.Password),
+		Host:     d.Host,
+		Path:     d.Name,
+		RawQuery: url.Values{"sslmode": {d.SSLMode}}.Encode(),
+	}
+
+	if d.Port != "" {
+		u.Host += ":" + d.Port
+	}
+
+	return u.String()
+}
+
+type Log struct {
+	Level      string `yaml:"level" env:"LOG_LEVEL" usage:"minimum level of logged messages: debug, info, warn or error"`
+	Format     string `yaml:"format" env:"LOG_FORMAT" usage:"format of messages written to the output: json or console"`
+	Output     string `yaml:"output" env:"LOG_OUTPUT" usage:"where messages are written: stdout, stderr or none"`
+	File       string `yaml:"file" env:"LOG_FILE" usage:"file logs are written to as JSON along with the output, none when empty"`
+	MaxSize    int    `yaml:"max_size" env:"LOG_MAX_SIZE" usage:"size in megabytes the log file is rotated at, never when 0"`
+	MaxBackups int    `yaml:"max_backups" env:"LOG_MAX_BACKUPS" usage:"number of rotated log files kept"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" usage:"where spans are exported: none, otlp or stdout"`
+	Endpoint    string  `yaml:"endpoint" env:"OTLP_ENDPOINT" usage:"URL of the OTLP collector receiving spans over gRPC, like http://localhost:4317"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" usage:"ratio of traces recorded when not sampled by the caller"`
+}
+
+type Shutdown struct {
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" usage:"time the service reports not ready before it stops accepting requests"`
+	Timeout    time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" usage:"time given to pending requests, workers and connections to finish at each shutdown step"`
+}
+
+type Auth struct {
+	AdminAPIKeys []string `yaml:"admin_api_keys" env:"ADMIN_API_KEYS" usage:"comma separated API keys allowed to use admin endpoints" secret:"true"`
+	APIKeys      []string `yaml:"api_keys" env:"API_KEYS" usage:"comma separated API keys of clients allowed to follow live boards, admin keys are allowed too" secret:"true"`
+}
+
+type RateLimit struct {
+	Store string `yaml:"store" env:"RATE_LIMIT_STORE" usage:"memory or postgres, postgres shares rate limits between instances"`
+}
+
+type Reservations struct {
+	CancelledRetention time.Duration `yaml:"cancelled_retention" env:"CANCELLED_RETENTION" usage:"how long cancelled reservations are kept, 0 keeps them forever"`
+	EventRetention     time.Duration `yaml:"event_retention" env:"EVENT_RETENTION" usage:"how long published events are kept for event streams to resume from, 0 keeps them forever"`
+}
+
+// Mail configures notification emails, which are disabled without an SMTP server.
+type Mail struct {
+	SMTPAddr     string `yaml:"smtp_addr" env:"SMTP_ADDR" usage:"SMTP server emailing reservation notifications"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME" usage:"SMTP user"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD" usage:"SMTP password" secret:"true"`
+	From         string `yaml:"from" env:"MAIL_FROM" usage:"sender of notification emails"`
+}
+
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Port:              "8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			MaxHeaderBytes:    1 << 20,
+		},
+		Database: Database{
+			Host:              "localhost",
+			Port:              "5432",
+			Name:              "postgres",
+			Username:          "postgres",
+			SSLMode:           "disable",
+			MaxConns:          5,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+		},
+		Log:          Log{Level: "info", Format: "json", Output: "stdout", MaxSize: 100, MaxBackups: 5},
+		Tracing:      Tracing{Exporter: "none", SampleRatio: 1},
+		RateLimit:    RateLimit{Store: "memory"},
+		Reservations: Reservations{CancelledRetention: 30 * 24 * time.Hour, EventRetention: 7 * 24 * time.Hour},
+		Shutdown:     Shutdown{Timeout: 30 * time.Second},
+	}
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", describe(key), fmt.Sprintf(format, args...)))
+	}
+
+	if !validPort(c.HTTP.Port) {
+		invalid("http.port", "must be a port number, got %q", c.HTTP.Port)
+	}
+
+	if c.GRPC.Port != "" && !validPort(c.GRPC.Port) {
+		invalid("grpc.port", "must be a port number, got %q", c.GRPC.Port)
+	}
+
+	if c.HTTP.PublicURL != "" {
+		if u, err := url.Parse(c.HTTP.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+			invalid("http.public_url", "must be an absolute URL, got %q", c.HTTP.PublicURL)
+		}
+	}
+
+	if (c.HTTP.TLSCertFile == "") != (c.HTTP.TLSKeyFile == "") {
+		invalid("http.tls_key_file", "must be set along with http.tls_cert_file")
+	}
+
+	for _, origin := range c.HTTP.AllowedOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			invalid("http.allowed_origins", "must be absolute URLs, got %q", origin)
+		}
+	}
+
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				invalid("http.trusted_proxies", "must be IP addresses or CIDR ranges, got %q", proxy)
+			}
+		}
+	}
+
+	if c.HTTP.MaxHeaderBytes < 0 {
+		invalid("http.max_header_bytes", "must not be negative, got %d", c.HTTP.MaxHeaderBytes)
+	}
+
+	if c.Shutdown.Timeout <= 0 {
+		invalid("shutdown.timeout", "must be positive, got %s", c.Shutdown.Timeout)
+	}
+
+	if c.Database.Host == "" {
+		invalid("database.host", "is required")
+	}
+
+	if c.Database.Name == "" {
+		invalid("database.name", "is required")
+	}
+
+	if c.Database.MaxConns < 1 {
+		invalid("database.max_conns", "must be at least 1, got %d", c.Database.MaxConns)
+	}
+
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		invalid("database.min_conns", "must be between 0 and database.max_conns, got %d", c.Database.MinConns)
+	}
+
+	for key, d := range map[string]time.Duration{
+		"database.max_conn_lifetime":       c.Database.MaxConnLifetime,
+		"database.max_conn_idle_time":      c.Database.MaxConnIdleTime,
+		"database.health_check_period":     c.Database.HealthCheckPeriod,
+		"reservations.cancelled_retention": c.Reservations.CancelledRetention,
+		"reservations.event_retention":     c.Reservations.EventRetention,
+		"http.read_header_timeout":         c.HTTP.ReadHeaderTimeout,
+		"http.read_timeout":                c.HTTP.ReadTimeout,
+		"http.write_timeout":               c.HTTP.WriteTimeout,
+		"http.idle_timeout":                c.HTTP.IdleTimeout,
+		"shutdown.drain_delay":             c.Shutdown.DrainDelay,
+	} {
+		if d < 0 {
+			invalid(key, "must not be negative, got %s", d)
+		}
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		invalid("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+
+	switch c.Log.Format {
+	case "json", "console":
+	default:
+		invalid("log.format", "must be json or console, got %q", c.Log.Format)
+	}
+
+	switch c.Log.Output {
+	case "stdout", "stderr", "none":
+	default:
+		invalid("log.output", "must be stdout, stderr or none, got %q", c.Log.Output)
+	}
+
+	if c.Log.MaxSize < 0 {
+		invalid("log.max_size", "must not be negative, got %d", c.Log.MaxSize)
+	}
+
+	if c.Log.MaxBackups < 0 {
+		invalid("log.max_backups", "must not be negative, got %d", c.Log.MaxBackups)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	default:
+		invalid("tracing.exporter", "must be none, otlp or stdout, got %q", c.Tracing.Exporter)
+	}
+
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			invalid("tracing.endpoint", "must be an absolute URL, got %q", c.Tracing.Endpoint)
+		}
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	switch c.RateLimit.Store {
+	case "memory", "postgres":
+	default:
+		invalid("rate_limit.store", "must be memory or postgres, got %q", c.RateLimit.Store)
+	}
+
+	if c.Mail.SMTPAddr != "" && c.Mail.From == "" {
+		invalid("mail.from", "is required to send notifications")
+	}
+
+	return errors.Join(errs...)
+}
+
+func validPort(s string) bool {
+	port, err := strconv.Atoi(s)
+	return err == nil && port > 0 && port < 65536
+}
+
+// Redacted returns a copy of the config with secrets replaced, to be printed or logged.
+func (c Config) Redacted() Config {
+	redact(&c)
+	return c
+}
+
+// String is the config as YAML with secrets redacted.
+func (c Config) String() string {
+	b, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`
+http:
+  port: "9000"
+database:
+  host: db.internal
+  max_conns: 20
+  max_conn_lifetime: 15m
+auth:
+  admin_api_keys: [key-1]
+`), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("DB_MAX_CONNS", "30")
+	t.Setenv("ADMIN_API_KEYS", "key-2, key-3")
+	t.Setenv("GRPC_PORT", "")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+
+	err = fs.Parse([]string{"-config", file, "-database.max-conns", "40", "-database.auto-migrate"})
+	require.NoError(t, err)
+
+	c, err := flags.Load()
+	require.NoError(t, err, "could not load config")
+
+	assert.Equal(t, "9000", c.HTTP.Port, "expected the file to override defaults")
+	assert.Equal(t, "db.internal", c.Database.Host)
+	assert.Equal(t, 15*time.Minute, c.Database.MaxConnLifetime)
+	assert.Equal(t, []string{"key-2", "key-3"}, c.Auth.AdminAPIKeys, "expected the environment to override the file")
+	assert.Equal(t, 40, c.Database.MaxConns, "expected flags to override the environment")
+	assert.True(t, c.Database.AutoMigrate)
+	assert.Equal(t, time.Hour*24*30, c.Reservations.CancelledRetention, "expected defaults to be kept")
+	assert.Empty(t, c.GRPC.Port, "expected empty variables to be ignored")
+}
+
+func TestLoadInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("http:\n  prot: 80\n"), 0o600))
+
+	_, err := Load(file)
+	assert.ErrorContains(t, err, "field prot not found", "expected unknown keys to be reported")
+
+	t.Setenv("DB_MAX_CONNS", "many")
+
+	_, err = Load("")
+	assert.ErrorContains(t, err, "DB_MAX_CONNS: must be a number")
+
+	t.Setenv("DB_MAX_CONNS", "0")
+	t.Setenv("SMTP_ADDR", "mail:25")
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("LOG_FORMAT", "text")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,proxy")
+
+	_, err = Load("")
+	assert.ErrorContains(t, err, "database.max_conns (DB_MAX_CONNS): must be at least 1")
+	assert.ErrorContains(t, err, "mail.from (MAIL_FROM): is required")
+	assert.ErrorContains(t, err, "log.level (LOG_LEVEL)")
+	assert.ErrorContains(t, err, "log.format (LOG_FORMAT): must be json or console")
+	assert.ErrorContains(t, err, "tracing.sample_ratio (TRACING_SAMPLE_RATIO): must be between 0 and 1")
+	assert.ErrorContains(t, err, `http.trusted_proxies (TRUSTED_PROXIES): must be IP addresses or CIDR ranges, got "proxy"`)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	fs.SetOutput(io.Discard)
+
+	assert.Error(t, fs.Parse([]string{"-database.max-conn-lifetime", "forever"}), "expected flags to be checked when parsed")
+}
+
+func TestRedacted(t *testing.T) {
+	c := Default()
+	c.Database.Password = "hunter2"
+	c.Auth.AdminAPIKeys = []string{"key-1", "key-2"}
+
+	s := c.String()
+	assert.NotContains(t, s, "hunter2")
+	assert.NotContains(t, s, "key-1")
+	assert.Contains(t, s, "password: REDACTED")
+	assert.Contains(t, s, "max_conns: 5")
+
+	assert.Equal(t, "hunter2", c.Database.Password, "expected the config itself to be left as is")
+	assert.Equal(t, "key-1", c.Auth.AdminAPIKeys[0])
+}
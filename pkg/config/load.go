@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// field is a setting of Config.
+type field struct {
+	// key is the path of the setting in the YAML file, like database.max_conns.
+	key    string
+	env    string
+	usage  string
+	secret bool
+	index  []int
+}
+
+var fields = collect(reflect.TypeOf(Config{}), "", nil)
+
+func collect(t reflect.Type, prefix string, index []int) []field {
+	var res []field
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		key := prefix + sf.Tag.Get("yaml")
+		idx := append(append([]int{}, index...), i)
+
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+			res = append(res, collect(sf.Type, key+".", idx)...)
+			continue
+		}
+
+		res = append(res, field{
+			key:    key,
+			env:    sf.Tag.Get("env"),
+			usage:  sf.Tag.Get("usage"),
+			secret: sf.Tag.Get("secret") == "true",
+			index:  idx,
+		})
+	}
+
+	return res
+}
+
+// describe names a setting by its key and environment variable.
+func describe(key string) string {
+	for _, f := range fields {
+		if f.key == key {
+			return fmt.Sprintf("%s (%s)", f.key, f.env)
+		}
+	}
+
+	return key
+}
+
+func (f field) flagName() string {
+	return strings.ReplaceAll(f.key, "_", "-")
+}
+
+func (f field) value(c *Config) reflect.Value {
+	return reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+}
+
+// set parses s into v. Lists are comma separated.
+func set(v reflect.Value, s string) error {
+	switch v.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("must be a duration like 1h30m, got %q", s)
+		}
+		v.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", s)
+		}
+		v.SetInt(int64(n))
+	case float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", s)
+		}
+		v.SetFloat(f)
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("must be true or false, got %q", s)
+		}
+		v.SetBool(b)
+	case []string:
+		var list []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		v.SetString(s)
+	}
+
+	return nil
+}
+
+func redact(c *Config) {
+	for _, f := range fields {
+		if !f.secret {
+			continue
+		}
+
+		switch v := f.value(c); v.Kind() {
+		case reflect.String:
+			if v.String() != "" {
+				v.SetString("REDACTED")
+			}
+		case reflect.Slice:
+			list := make([]string, v.Len())
+			for i := range list {
+				list[i] = "REDACTED"
+			}
+			v.Set(reflect.ValueOf(list))
+		}
+	}
+}
+
+// Load reads the YAML file, which may be empty, then the environment on top of the defaults. Empty environment
+// variables are ignored.
+func Load(file string) (Config, error) {
+	return load(file, os.LookupEnv, nil)
+}
+
+func load(file string, lookupEnv func(string) (string, bool), flags map[string]string) (Config, error) {
+	c := Default()
+
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return c, fmt.Errorf("unable to read config file: %w", err)
+		}
+		defer f.Close()
+
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+
+		if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+			return c, fmt.Errorf("invalid config file %s: %w", file, err)
+		}
+	}
+
+	var errs []error
+
+	for _, f := range fields {
+		if s, ok := lookupEnv(f.env); ok && s != "" {
+			if err := set(f.value(&c), s); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if s, ok := flags[f.flagName()]; ok {
+			if err := set(f.value(&c), s); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", f.flagName(), err))
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+// Flags are flags for every setting named after its key, like -database.max-conns, and -config naming the YAML file.
+type Flags struct {
+	fs   *flag.FlagSet
+	file string
+}
+
+// RegisterFlags defines the flags in fs, Load reads them once fs is parsed.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+
+	fs.StringVar(&f.file, "config", "", "YAML config file, CONFIG_FILE by default")
+
+	defaults := Default()
+
+	for _, field := range fields {
+		v := &flagValue{value: field.value(&defaults)}
+		fs.Var(v, field.flagName(), fmt.Sprintf("%s (%s)", field.usage, field.env))
+	}
+
+	return f
+}
+
+// Load reads the config file, then the environment, then the flags that were set.
+func (f *Flags) Load() (Config, error) {
+	set := map[string]string{}
+	f.fs.Visit(func(fl *flag.Flag) {
+		if v, ok := fl.Value.(*flagValue); ok {
+			set[fl.Name] = v.s
+		}
+	})
+
+	file := f.file
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+
+	return load(file, os.LookupEnv, set)
+}
+
+// flagValue holds the value of a flag as given, value is only used to check it.
+type flagValue struct {
+	value reflect.Value
+	s     string
+}
+
+func (v *flagValue) String() string {
+	return v.s
+}
+
+func (v *flagValue) Set(s string) error {
+	if err := set(reflect.New(v.value.Type()).Elem(), s); err != nil {
+		return err
+	}
+
+	v.s = s
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.value.Kind() == reflect.Bool
+}
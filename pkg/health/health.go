@@ -0,0 +1,162 @@
+// Package health reports whether the service is alive and ready to serve requests.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusFailing      = "failing"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check fails when a dependency of the service is unusable.
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a single check.
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of every check, its status is StatusOK only when all of them pass.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs liveness and readiness checks. The service is live as long as its liveness checks pass, and
+// ready when its readiness checks pass as well, until Shutdown is called.
+type Checker struct {
+	timeout time.Duration
+
+	mu    sync.RWMutex
+	live  []namedCheck
+	ready []namedCheck
+
+	shuttingDown atomic.Bool
+}
+
+// NewChecker returns a checker failing checks that take longer than timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// AddLive adds a check failing when the service can't recover without a restart.
+func (c *Checker) AddLive(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.live = append(c.live, namedCheck{name, check})
+}
+
+// AddReady adds a check failing when the service can't serve requests for now.
+func (c *Checker) AddReady(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ready = append(c.ready, namedCheck{name, check})
+}
+
+// Shutdown makes the service not ready from now on, so that no more requests are sent its way.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Live runs the liveness checks.
+func (c *Checker) Live(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := c.live
+	c.mu.RUnlock()
+
+	return c.run(ctx, checks)
+}
+
+// Ready runs the liveness and readiness checks.
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append(append([]namedCheck(nil), c.live...), c.ready...)
+	c.mu.RUnlock()
+
+	report := c.run(ctx, checks)
+	if c.shuttingDown.Load() {
+		report.Status = StatusShuttingDown
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, checks []namedCheck) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, nc := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := nc.check(ctx)
+
+			res := Result{Status: StatusOK, Duration: time.Since(start).Round(time.Microsecond).String()}
+			if err != nil {
+				res.Status, res.Error = StatusFailing, err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[nc.name] = res
+			if err != nil {
+				report.Status = StatusFailing
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// ServeLive answers 200 OK when the service is live and 503 Service Unavailable otherwise, with the report.
+func (c *Checker) ServeLive(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, c.Live(r.Context()))
+}
+
+// ServeReady answers 200 OK when the service is ready and 503 Service Unavailable otherwise, with the report.
+func (c *Checker) ServeReady(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, c.Ready(r.Context()))
+}
+
+func serve(w http.ResponseWriter, r *http.Request, report Report) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, report)
+}
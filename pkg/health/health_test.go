@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	c := NewChecker(50 * time.Millisecond)
+	c.AddLive("workers", func(context.Context) error { return nil })
+	c.AddReady("database", func(context.Context) error { return dbErr })
+	c.AddReady("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, StatusOK, c.Live(context.Background()).Status, "expected readiness checks not to affect liveness")
+
+	rec := httptest.NewRecorder()
+	c.ServeReady(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, StatusFailing, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["workers"].Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error, "expected checks to time out")
+
+	dbErr = nil
+
+	c = NewChecker(time.Second)
+	c.AddReady("database", func(context.Context) error { return dbErr })
+
+	rec = httptest.NewRecorder()
+	c.ServeReady(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	c.Shutdown()
+
+	rec = httptest.NewRecorder()
+	c.ServeReady(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), StatusShuttingDown)
+
+	rec = httptest.NewRecorder()
+	c.ServeLive(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "expected the service to stay live while shutting down")
+}
+
+func TestWorkers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var w Workers
+
+	w.Go(ctx, "steady", func(ctx context.Context) { <-ctx.Done() })
+	w.Go(ctx, "broken", func(context.Context) { panic("boom") })
+	w.Go(ctx, "quitter", func(context.Context) {})
+
+	require.Eventually(t, func() bool {
+		err := w.Check(ctx)
+		return err != nil && err.Error() == "broken panicked: boom, quitter stopped"
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	w.Wait()
+}
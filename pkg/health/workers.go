@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"room-reservation/pkg/log"
+)
+
+// Workers runs background workers until their context is done. A worker panicking or returning before then is
+// reported by Check, rather than crashing the service or going unnoticed.
+type Workers struct {
+	wg sync.WaitGroup
+
+	mu     sync.Mutex
+	failed map[string]error
+}
+
+// Go runs a worker named name in a new goroutine.
+func (w *Workers) Go(ctx context.Context, name string, run func(context.Context)) {
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+
+		defer func() {
+			if v := recover(); v != nil {
+				log.LoggerFromContext(ctx).Error().Str("worker", name).Interface("panic", v).
+					Bytes("stack", debug.Stack()).Msg("worker panicked")
+				w.fail(name, fmt.Errorf("panicked: %v", v))
+
+				return
+			}
+
+			if ctx.Err() == nil {
+				w.fail(name, errors.New("stopped"))
+			}
+		}()
+
+		run(ctx)
+	}()
+}
+
+func (w *Workers) fail(name string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed == nil {
+		w.failed = make(map[string]error)
+	}
+
+	w.failed[name] = err
+}
+
+// Check fails when a worker stopped unexpectedly.
+func (w *Workers) Check(context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.failed) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(w.failed))
+	for name, err := range w.failed {
+		msgs = append(msgs, name+" "+err.Error())
+	}
+
+	sort.Strings(msgs)
+
+	return errors.New(strings.Join(msgs, ", "))
+}
+
+// Wait waits for every worker to return.
+func (w *Workers) Wait() {
+	w.wg.Wait()
+}
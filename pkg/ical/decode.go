@@ -0,0 +1,324 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	// time zones of events are resolved even where the system has no time zone database
+	_ "time/tzdata"
+)
+
+// Decoder reads calendars.
+type Decoder struct {
+	// Location is used for floating times and for time zones unknown to the system,
+	// UTC when nil.
+	Location *time.Location
+}
+
+var ErrorInvalid error = errors.New("invalid calendar")
+
+// Decode reads a calendar from r, only events are kept.
+func (d Decoder) Decode(r io.Reader) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	c := Calendar{}
+	var ev *Event
+	depth := 0
+
+	for i, l := range lines {
+		p, err := parseLine(l)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("%w: line %d: %v", ErrorInvalid, i+1, err)
+		}
+
+		switch p.name {
+		case "BEGIN":
+			depth++
+			if depth == 1 && p.value != "VCALENDAR" {
+				return Calendar{}, fmt.Errorf("%w: expected VCALENDAR, got %s", ErrorInvalid, p.value)
+			}
+			if depth == 2 && p.value == "VEVENT" {
+				ev = &Event{}
+			}
+			continue
+		case "END":
+			if depth == 2 && ev != nil {
+				if ev.UID == "" || ev.Start.IsZero() {
+					return Calendar{}, fmt.Errorf("%w: line %d: event without UID or DTSTART", ErrorInvalid, i+1)
+				}
+				if ev.End.IsZero() {
+					ev.End = ev.Start
+					if ev.AllDay {
+						ev.End = ev.Start.AddDate(0, 0, 1)
+					}
+				}
+				c.Events = append(c.Events, *ev)
+				ev = nil
+			}
+			depth--
+			continue
+		}
+
+		if depth == 1 {
+			switch p.name {
+			case "PRODID":
+				c.ProdID = p.value
+			case "X-WR-CALNAME":
+				c.Name = unescape(p.value)
+			}
+			continue
+		}
+
+		// properties of alarms and other nested components are ignored
+		if ev == nil || depth != 2 {
+			continue
+		}
+
+		if err := d.decodeProperty(ev, p); err != nil {
+			return Calendar{}, fmt.Errorf("%w: line %d: %v", ErrorInvalid, i+1, err)
+		}
+	}
+
+	if depth != 0 {
+		return Calendar{}, fmt.Errorf("%w: unterminated component", ErrorInvalid)
+	}
+
+	return c, nil
+}
+
+func (d Decoder) decodeProperty(ev *Event, p property) (err error) {
+	switch p.name {
+	case "UID":
+		ev.UID = p.value
+	case "SEQUENCE":
+		ev.Sequence, err = strconv.Atoi(p.value)
+	case "DTSTAMP":
+		ev.Stamp, _, err = d.parseTime(p)
+	case "LAST-MODIFIED":
+		ev.LastModified, _, err = d.parseTime(p)
+	case "DTSTART":
+		ev.Start, ev.AllDay, err = d.parseTime(p)
+	case "DTEND":
+		ev.End, _, err = d.parseTime(p)
+	case "DURATION":
+		var dur time.Duration
+		dur, err = parseDuration(p.value)
+		if err == nil && ev.End.IsZero() {
+			ev.End = ev.Start.Add(dur)
+		}
+	case "RECURRENCE-ID":
+		ev.RecurrenceID, _, err = d.parseTime(p)
+	case "RRULE":
+		ev.RRule = p.value
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			t, _, err := d.parseTime(property{name: p.name, params: p.params, value: v})
+			if err != nil {
+				return err
+			}
+			ev.ExDates = append(ev.ExDates, t)
+		}
+	case "SUMMARY":
+		ev.Summary = unescape(p.value)
+	case "DESCRIPTION":
+		ev.Description = unescape(p.value)
+	case "LOCATION":
+		ev.Location = unescape(p.value)
+	case "STATUS":
+		ev.Status = strings.ToUpper(p.value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %v", p.name, err)
+	}
+
+	return nil
+}
+
+// parseTime parses DATE and DATE-TIME values, resolving their TZID parameter.
+func (d Decoder) parseTime(p property) (t time.Time, date bool, err error) {
+	loc := d.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.Trim(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+
+	switch {
+	case p.params["VALUE"] == "DATE" || len(p.value) == 8:
+		t, err = time.ParseInLocation("20060102", p.value, loc)
+		return t, true, err
+	case strings.HasSuffix(p.value, "Z"):
+		t, err = time.Parse(dateTimeLayout, p.value)
+		return t, false, err
+	default:
+		t, err = time.ParseInLocation("20060102T150405", p.value, loc)
+		return t, false, err
+	}
+}
+
+// parseDuration parses durations such as P1D, PT1H30M or -PT15M.
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	n := 0
+	digits := false
+
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			n = n*10 + int(c-'0')
+			digits = true
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+
+		if !digits {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+
+		unit := time.Duration(0)
+		switch {
+		case c == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case c == 'H' && inTime:
+			unit = time.Hour
+		case c == 'M' && inTime:
+			unit = time.Minute
+		case c == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+
+		d += time.Duration(n) * unit
+		n, digits = 0, false
+	}
+
+	if digits {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	return sign * d, nil
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseLine splits a content line into its name, parameters and value.
+func parseLine(l string) (property, error) {
+	p := property{params: map[string]string{}}
+
+	i := strings.IndexAny(l, ";:")
+	if i <= 0 {
+		return p, fmt.Errorf("malformed line %q", l)
+	}
+	p.name = strings.ToUpper(l[:i])
+
+	for l[i] == ';' {
+		l = l[i+1:]
+
+		eq := strings.IndexByte(l, '=')
+		if eq <= 0 {
+			return p, fmt.Errorf("malformed parameter in %q", l)
+		}
+		name := strings.ToUpper(l[:eq])
+		l = l[eq+1:]
+
+		var value string
+		if strings.HasPrefix(l, `"`) {
+			end := strings.IndexByte(l[1:], '"')
+			if end < 0 {
+				return p, fmt.Errorf("unterminated quoted parameter in %q", l)
+			}
+			value, l = l[1:end+1], l[end+2:]
+			i = 0
+			if l == "" {
+				return p, fmt.Errorf("missing value")
+			}
+		} else {
+			i = strings.IndexAny(l, ";:")
+			if i < 0 {
+				return p, fmt.Errorf("missing value")
+			}
+			value = l[:i]
+			l = l[i:]
+			i = 0
+		}
+
+		p.params[name] = value
+	}
+
+	if l[i] != ':' {
+		return p, fmt.Errorf("missing value")
+	}
+
+	p.value = l[i+1:]
+
+	return p, nil
+}
+
+// unfold joins folded lines, continuation lines start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+
+		if l == "" {
+			continue
+		}
+
+		lines = append(lines, l)
+	}
+
+	return lines, sc.Err()
+}
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescape(s string) string {
+	return textUnescaper.Replace(s)
+}
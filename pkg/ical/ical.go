@@ -0,0 +1,145 @@
+// Package ical reads and writes iCalendar (RFC 5545) calendars.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR object holding events.
+type Calendar struct {
+	ProdID string
+	// Name is shown by calendar clients subscribing to the calendar.
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT component.
+// UID must stay the same across versions of an event, Sequence is incremented whenever it changes.
+type Event struct {
+	UID          string
+	Sequence     int
+	Stamp        time.Time
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	Status       string
+	LastModified time.Time
+	// AllDay events start and end at midnight of dates rather than at a time.
+	AllDay bool
+	// RRule and ExDates describe recurring events, RecurrenceID identifies the occurrence
+	// of a recurring event that an event overrides.
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID time.Time
+}
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Encode writes the calendar to w, lines are folded at 75 octets.
+func (c Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := encoder{w: bw}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", c.ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		e.text("X-WR-CALNAME", c.Name)
+	}
+
+	for _, ev := range c.Events {
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", ev.UID)
+		e.line("SEQUENCE", strconv.Itoa(ev.Sequence))
+		e.time("DTSTAMP", ev.Stamp)
+		e.time("DTSTART", ev.Start)
+		e.time("DTEND", ev.End)
+		if ev.RRule != "" {
+			e.line("RRULE", ev.RRule)
+		}
+		if !ev.LastModified.IsZero() {
+			e.time("LAST-MODIFIED", ev.LastModified)
+		}
+		if ev.Summary != "" {
+			e.text("SUMMARY", ev.Summary)
+		}
+		if ev.Description != "" {
+			e.text("DESCRIPTION", ev.Description)
+		}
+		if ev.Location != "" {
+			e.text("LOCATION", ev.Location)
+		}
+		if ev.Status != "" {
+			e.line("STATUS", ev.Status)
+		}
+		e.line("END", "VEVENT")
+	}
+
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return bw.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) time(name string, t time.Time) {
+	e.line(name, t.UTC().Format(dateTimeLayout))
+}
+
+func (e *encoder) text(name, value string) {
+	e.line(name, escape(value))
+}
+
+// line writes a content line, folding it so that no line is longer than 75 octets.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+
+	for n := 0; len(s) > 75-n; n = 1 {
+		i := 75 - n
+		// don't split UTF-8 sequences
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+
+		e.write(s[:i] + "\r\n ")
+		s = s[i:]
+	}
+
+	e.write(s + "\r\n")
+}
+
+func (e *encoder) write(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+
+	c := Calendar{
+		ProdID: "-//room-reservation//EN",
+		Name:   "Room 1",
+		Events: []Event{
+			{
+				UID:      "946e2eb89bdc@room-reservation",
+				Sequence: 2,
+				Stamp:    start,
+				Start:    start,
+				End:      start.Add(time.Hour),
+				Summary:  "Standup; team, daily",
+				Status:   StatusCancelled,
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	err := c.Encode(&b)
+	require.NoError(t, err)
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//room-reservation//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Room 1",
+		"BEGIN:VEVENT",
+		"UID:946e2eb89bdc@room-reservation",
+		"SEQUENCE:2",
+		"DTSTAMP:20240829T130000Z",
+		"DTSTART:20240829T130000Z",
+		"DTEND:20240829T140000Z",
+		`SUMMARY:Standup\; team\, daily`,
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	require.Equal(t, expected, b.String())
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	c := Calendar{ProdID: "x", Name: strings.Repeat("é", 100)}
+
+	var b bytes.Buffer
+	err := c.Encode(&b)
+	require.NoError(t, err)
+
+	var name string
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		require.LessOrEqual(t, len(line), 75, "expected lines to be folded")
+
+		if strings.HasPrefix(line, "X-WR-CALNAME:") {
+			name = line
+		} else if name != "" && strings.HasPrefix(line, " ") {
+			name += line[1:]
+		} else if name != "" {
+			break
+		}
+	}
+
+	require.Equal(t, "X-WR-CALNAME:"+strings.Repeat("é", 100), name, "expected folding to keep the value intact")
+}
+
+const recurring = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Old booking tool//EN\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Berlin\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19701025T030000\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly@example.com\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20241021T090000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20241021T093000\r\n" +
+	"RRULE:FREQ=WEEKLY;COUNT=4\r\n" +
+	"EXDATE;TZID=Europe/Berlin:20241104T090000\r\n" +
+	"SUMMARY:Weekly sync\\, team A\r\n" +
+	"LOCATION:Everest\r\n" +
+	"DESCRIPTION:a long description that is folded onto the next line by th\r\n" +
+	" e exporting tool\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly@example.com\r\n" +
+	"RECURRENCE-ID;TZID=Europe/Berlin:20241028T090000\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20241028T100000\r\n" +
+	"DURATION:PT1H\r\n" +
+	"SUMMARY:Weekly sync moved\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20241225\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestDecode(t *testing.T) {
+	c, err := Decoder{}.Decode(strings.NewReader(recurring))
+	require.NoError(t, err)
+
+	require.Equal(t, "-//Old booking tool//EN", c.ProdID)
+	require.Len(t, c.Events, 3)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	e := c.Events[0]
+	require.Equal(t, "weekly@example.com", e.UID)
+	require.True(t, e.Start.Equal(time.Date(2024, 10, 21, 9, 0, 0, 0, berlin)), "expected start in its time zone, got %v", e.Start)
+	require.Equal(t, "Weekly sync, team A", e.Summary)
+	require.Equal(t, "a long description that is folded onto the next line by the exporting tool", e.Description, "expected alarm properties to be ignored")
+	require.Equal(t, "FREQ=WEEKLY;COUNT=4", e.RRule)
+	require.Len(t, e.ExDates, 1)
+
+	require.Equal(t, time.Hour, c.Events[1].End.Sub(c.Events[1].Start), "expected duration to set the end")
+
+	require.True(t, c.Events[2].AllDay)
+	require.Equal(t, 24*time.Hour, c.Events[2].End.Sub(c.Events[2].Start))
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	_, err := Decoder{}.Decode(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:x\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+	require.ErrorIs(t, err, ErrorInvalid, "expected event without UID to be rejected")
+
+	_, err = Decoder{}.Decode(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"))
+	require.ErrorIs(t, err, ErrorInvalid, "expected unterminated calendar to be rejected")
+}
+
+func TestExpand(t *testing.T) {
+	c, err := Decoder{}.Decode(strings.NewReader(recurring))
+	require.NoError(t, err)
+
+	events, err := c.Expand(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 100)
+	require.NoError(t, err)
+
+	var starts []string
+	for _, e := range events {
+		starts = append(starts, e.Start.UTC().Format(time.RFC3339))
+	}
+
+	require.Equal(t, []string{
+		"2024-10-21T07:00:00Z",
+		"2024-10-28T09:00:00Z", // overridden, an hour later and after the switch to winter time
+		"2024-11-11T08:00:00Z", // 2024-11-04 is excluded
+		"2024-12-25T00:00:00Z",
+	}, starts)
+
+	require.Equal(t, "Weekly sync moved", events[1].Summary)
+	require.False(t, events[2].RecurrenceID.IsZero(), "expected occurrences to keep their recurrence ID")
+
+	events, err = c.Expand(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+	require.NoError(t, err)
+	require.Len(t, events, 2, "expected occurrences to be capped")
+}
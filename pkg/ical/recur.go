@@ -0,0 +1,100 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Expand turns recurring events into their occurrences starting before until,
+// leaving out excluded dates and replacing overridden occurrences.
+// Occurrences of a recurring event share its UID and have RecurrenceID set to their original start.
+// At most max occurrences of every event are returned.
+func (c Calendar) Expand(until time.Time, max int) ([]Event, error) {
+	overrides := map[string]map[int64]Event{}
+	for _, e := range c.Events {
+		if e.RecurrenceID.IsZero() {
+			continue
+		}
+
+		if overrides[e.UID] == nil {
+			overrides[e.UID] = map[int64]Event{}
+		}
+		overrides[e.UID][e.RecurrenceID.Unix()] = e
+	}
+
+	res := []Event{}
+
+	for _, e := range c.Events {
+		if !e.RecurrenceID.IsZero() {
+			continue
+		}
+
+		if e.RRule == "" {
+			res = append(res, e)
+			continue
+		}
+
+		starts, err := e.occurrences(until, max)
+		if err != nil {
+			return nil, fmt.Errorf("%w: event %s: %v", ErrorInvalid, e.UID, err)
+		}
+
+		for _, start := range starts {
+			if o, ok := overrides[e.UID][start.Unix()]; ok {
+				res = append(res, o)
+				continue
+			}
+
+			occ := e
+			occ.RRule = ""
+			occ.ExDates = nil
+			occ.RecurrenceID = start
+			occ.Start = start
+			occ.End = start.Add(e.End.Sub(e.Start))
+			res = append(res, occ)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+
+	return res, nil
+}
+
+func (e Event) occurrences(until time.Time, max int) ([]time.Time, error) {
+	opt, err := rrule.StrToROptionInLocation(e.RRule, e.Start.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	// the start is kept in its own time zone so that occurrences follow daylight saving time
+	opt.Dtstart = e.Start
+
+	rule, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[int64]bool{}
+	for _, t := range e.ExDates {
+		excluded[t.Unix()] = true
+	}
+
+	var starts []time.Time
+
+	next := rule.Iterator()
+	for len(starts) < max {
+		t, ok := next()
+		if !ok || !t.Before(until) {
+			break
+		}
+
+		if !excluded[t.Unix()] {
+			starts = append(starts, t)
+		}
+	}
+
+	return starts, nil
+}
@@ -2,28 +2,117 @@ package log
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
-var logger zerolog.Logger
+// logger is used outside requests and until Setup is called.
+var logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
 
-func init() {
-	logFile, err := os.Create("log.txt")
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputNone   = "none"
+)
+
+// Config describes where and how messages are logged.
+type Config struct {
+	// Level is the minimum level of logged messages.
+	Level string
+	// Format of messages written to Output, either FormatJSON or FormatConsole. The file always gets JSON.
+	Format string
+	// Output is OutputStdout, OutputStderr or OutputNone.
+	Output string
+	// File messages are written to along with Output, none when empty.
+	File string
+	// MaxSize in megabytes File grows to before it is rotated, never rotated when 0.
+	MaxSize int
+	// MaxBackups is the number of rotated files kept.
+	MaxBackups int
+}
+
+// Setup replaces the default logger by one configured by c. The returned function closes the log file.
+func Setup(c Config) (func() error, error) {
+	lvl, err := zerolog.ParseLevel(c.Level)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	var writers []io.Writer
+
+	var out io.Writer
+
+	switch c.Output {
+	case OutputStdout, "":
+		out = os.Stdout
+	case OutputStderr:
+		out = os.Stderr
+	case OutputNone:
+	default:
+		return nil, fmt.Errorf("unknown log output %q", c.Output)
+	}
+
+	if out != nil {
+		switch c.Format {
+		case FormatJSON, "":
+		case FormatConsole:
+			out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+		default:
+			return nil, fmt.Errorf("unknown log format %q", c.Format)
+		}
+
+		writers = append(writers, out)
 	}
 
-	multi := zerolog.MultiLevelWriter(logFile, os.Stdout)
+	closeFile := func() error { return nil }
 
-	logger = zerolog.New(multi).With().Timestamp().Logger()
+	if c.File != "" {
+		f, err := openRotatingFile(c.File, int64(c.MaxSize)<<20, c.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
 
-	logger.Info().Msg("Logger initialized")
+		writers = append(writers, f)
+		closeFile = f.Close
+	}
+
+	var w io.Writer = io.Discard
+
+	switch len(writers) {
+	case 0:
+	case 1:
+		w = writers[0]
+	default:
+		w = zerolog.MultiLevelWriter(writers...)
+	}
+
+	logger = zerolog.New(w).Level(lvl).With().Timestamp().Logger()
+
+	return closeFile, nil
 }
 
+// Logger returns the default logger, to derive request-scoped loggers from.
+func Logger() *zerolog.Logger {
+	return &logger
+}
+
+// LoggerFromContext returns the logger stored in ctx by WithLogger, or the default logger if there is none.
 func LoggerFromContext(ctx context.Context) *zerolog.Logger {
-	ctx = logger.WithContext(ctx)
+	if l := zerolog.Ctx(ctx); l != zerolog.Ctx(context.Background()) {
+		return l
+	}
+
+	return &logger
+}
 
-	return zerolog.Ctx(ctx)
+// WithLogger returns a copy of ctx carrying l, so that LoggerFromContext returns it.
+func WithLogger(ctx context.Context, l zerolog.Logger) context.Context {
+	return l.WithContext(ctx)
 }
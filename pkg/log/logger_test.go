@@ -0,0 +1,55 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Same(t, Logger(), LoggerFromContext(context.Background()))
+
+	var buf bytes.Buffer
+
+	ctx := WithLogger(context.Background(), zerolog.New(&buf).With().Str("request_id", "abc").Logger())
+	LoggerFromContext(ctx).Info().Msg("hello")
+
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &msg))
+	assert.Equal(t, "abc", msg["request_id"])
+	assert.Equal(t, "hello", msg["message"])
+}
+
+func TestSetup(t *testing.T) {
+	saved := logger
+	t.Cleanup(func() { logger = saved })
+
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	closeLog, err := Setup(Config{Level: "warn", Format: FormatConsole, Output: OutputNone, File: file})
+	require.NoError(t, err)
+
+	Logger().Info().Msg("skipped")
+	Logger().Warn().Msg("kept")
+	require.NoError(t, closeLog())
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal(b, &msg), "expected a single JSON message")
+	assert.Equal(t, "kept", msg["message"])
+
+	_, err = Setup(Config{Level: "info", Output: "syslog"})
+	assert.Error(t, err)
+
+	_, err = Setup(Config{Level: "loud"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	f, err := openRotatingFile(path, 8, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"aaa\n", "bbbbbb\n", "cc\n", "dddddd\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, f.Close())
+
+	read := func(name string) string {
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	assert.Equal(t, "dddddd\n", read(path))
+	assert.Equal(t, "cc\n", read(path+".1"))
+	assert.Equal(t, "bbbbbb\n", read(path+".2"), "expected the oldest file to be dropped")
+
+	matches, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	assert.Len(t, matches, 2, "expected older files to be removed, got %s", strings.Join(matches, ", "))
+}
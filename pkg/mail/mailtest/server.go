@@ -0,0 +1,128 @@
+// Package mailtest provides a local SMTP server for tests, receiving messages instead of delivering them.
+package mailtest
+
+import (
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is a message received by the server.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Server is a minimal SMTP server listening on a local port.
+type Server struct {
+	// Addr is the host:port the server listens on.
+	Addr string
+	// Messages receives every message sent to the server.
+	Messages chan Message
+
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server, callers should Close it when done.
+func NewServer() *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("mailtest: failed to listen on a port: " + err.Error())
+	}
+
+	s := &Server{
+		Addr:     l.Addr().String(),
+		Messages: make(chan Message, 100),
+		listener: l,
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	c := textproto.NewConn(conn)
+
+	c.PrintfLine("220 mailtest ESMTP")
+
+	var msg Message
+
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			c.PrintfLine("250 mailtest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			c.PrintfLine("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			c.PrintfLine("250 OK")
+		case "DATA":
+			c.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+
+			data, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+
+			msg.Data = data
+			s.Messages <- msg
+			c.PrintfLine("250 OK")
+		case "RSET":
+			msg = Message{}
+			c.PrintfLine("250 OK")
+		case "NOOP":
+			c.PrintfLine("250 OK")
+		case "QUIT":
+			c.PrintfLine("221 Bye")
+			return
+		default:
+			c.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// address extracts the address of MAIL FROM:<address> and RCPT TO:<address> arguments.
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+
+	return strings.Trim(addr, "<>")
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// SMTPSender sends messages through an SMTP server, upgrading the connection with STARTTLS
+// whenever the server offers it.
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	timeout  time.Duration
+}
+
+// NewSMTPSender creates a sender relaying through the server at addr, authenticating when username is set.
+func NewSMTPSender(addr, username, password string) (*SMTPSender, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPSender{
+		addr:     addr,
+		host:     host,
+		username: username,
+		password: password,
+		timeout:  30 * time.Second,
+	}, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, m Message) error {
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	from, _ := mail.ParseAddress(m.From)
+	to, _ := mail.ParseAddress(m.To)
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,99 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"room-reservation/pkg/mail/mailtest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPSender(t *testing.T) {
+	server := mailtest.NewServer()
+	defer server.Close()
+
+	sender, err := NewSMTPSender(server.Addr, "", "")
+	require.NoError(t, err, "could not create sender")
+
+	m := Message{
+		From:    "Room reservation <rooms@example.com>",
+		To:      "alice@example.com",
+		Subject: "Reservation confirmed: Café",
+		Text:    "Your reservation is confirmed.\n",
+		HTML:    "<p>Your reservation is confirmed.</p>",
+		Attachments: []Attachment{
+			{Filename: "reservation.ics", ContentType: "text/calendar; charset=utf-8", Data: bytes.Repeat([]byte("BEGIN:VCALENDAR\r\n"), 10)},
+		},
+	}
+
+	err = sender.Send(context.Background(), m)
+	require.NoError(t, err, "could not send message")
+
+	var received mailtest.Message
+	select {
+	case received = <-server.Messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected message to be received")
+	}
+
+	assert.Equal(t, "rooms@example.com", received.From)
+	assert.Equal(t, []string{"alice@example.com"}, received.To)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(received.Data))
+	require.NoError(t, err, "could not read message")
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, m.Subject, subject)
+	assert.NotEmpty(t, msg.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+
+	part, err := parts.NextPart()
+	require.NoError(t, err, "expected bodies")
+
+	mediaType, params, err = mime.ParseMediaType(part.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := multipart.NewReader(part, params["boundary"])
+
+	for _, expected := range []string{m.Text, m.HTML} {
+		body, err := bodies.NextPart()
+		require.NoError(t, err, "expected body")
+
+		content, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))))
+	}
+
+	part, err = parts.NextPart()
+	require.NoError(t, err, "expected attachment")
+	assert.Equal(t, "reservation.ics", part.FileName())
+
+	content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+	require.NoError(t, err)
+	assert.Equal(t, m.Attachments[0].Data, content)
+
+	_, err = parts.NextPart()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestMessageInvalidAddress(t *testing.T) {
+	m := Message{From: "rooms@example.com", To: "alice"}
+
+	_, err := m.Bytes()
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package router
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"room-reservation/pkg/server/response"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+	UserIDHeader = "X-User-ID"
+)
+
+// Identity describes the caller of a request as presented by its headers.
+// The user ID is expected to be set by an authenticating gateway in front of the service.
+type Identity struct {
+	APIKey string
+	UserID string
+	// Authenticated is set by Authenticate when the API key is one of the keys known to the service.
+	Authenticated bool
+}
+
+type identityCtxKey struct{}
+
+// Identify stores the caller identity in the request context.
+// Browsers can't set headers when opening WebSockets or event streams, so they may pass the API key as the api_key
+// query parameter instead.
+// Calendar clients can only send basic auth, its username is taken as the user ID and its password as the API key.
+func Identify(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := Identity{
+			APIKey: r.Header.Get(APIKeyHeader),
+			UserID: r.Header.Get(UserIDHeader),
+		}
+
+		streaming := strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Accept") == "text/event-stream"
+		if id.APIKey == "" && streaming {
+			id.APIKey = r.URL.Query().Get("api_key")
+		}
+
+		if user, password, ok := r.BasicAuth(); ok && id.APIKey == "" && id.UserID == "" {
+			id.UserID, id.APIKey = user, password
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// Authenticate marks the identity of requests presenting one of keys as authenticated. Requests are let through
+// either way, routes requiring a key are guarded by RequireAPIKey.
+func Authenticate(keys ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			id := IdentityFromContext(r.Context())
+			if !id.HasAPIKey(keys...) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			id.Authenticated = true
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithIdentity stores the caller identity in ctx, for callers not going through Identify.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, id)
+}
+
+func IdentityFromContext(ctx context.Context) Identity {
+	id, _ := ctx.Value(identityCtxKey{}).(Identity)
+	return id
+}
+
+// HasAPIKey reports whether the caller presented one of keys.
+func (id Identity) HasAPIKey(keys ...string) bool {
+	if id.APIKey == "" {
+		return false
+	}
+
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(id.APIKey), []byte(key)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Owns reports whether the caller may change what belongs to userID. Callers authenticated with an API key
+// may change anything, others only what belongs to the user ID they present.
+func (id Identity) Owns(userID string) bool {
+	return id.Authenticated || (id.UserID != "" && id.UserID == userID)
+}
+
+// RequireAPIKey only lets through requests presenting one of keys.
+func RequireAPIKey(keys ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			id := IdentityFromContext(r.Context())
+			if id.APIKey == "" {
+				response.Unauthorized(w)
+				return
+			}
+
+			if !id.HasAPIKey(keys...) {
+				response.Forbidden(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"room-reservation/pkg/log"
+)
+
+// Logger stores a logger carrying the request ID, method, path, user and trace in the request context, and logs
+// every request once it is served. Messages also carry the route pattern matched so far, which is the full
+// pattern by the time handlers log. It must come after RequestID, Trace and Identify.
+func Logger(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		c := log.Logger().With().
+			Str("request_id", middleware.GetReqID(ctx)).
+			Str("method", r.Method).
+			Str("path", r.URL.Path)
+
+		if user := IdentityFromContext(ctx).UserID; user != "" {
+			c = c.Str("user", user)
+		}
+
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			c = c.Str("trace_id", sc.TraceID().String()).Str("span_id", sc.SpanID().String())
+		}
+
+		logger := c.Logger()
+
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			logger = logger.Hook(routeHook{rctx})
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+
+		next.ServeHTTP(ww, r.WithContext(log.WithLogger(ctx, logger)))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := zerolog.InfoLevel
+
+		switch {
+		case status >= 500:
+			level = zerolog.ErrorLevel
+		case status >= 400:
+			level = zerolog.WarnLevel
+		}
+
+		logger.WithLevel(level).
+			Str("remote_addr", r.RemoteAddr).
+			Int("status", status).
+			Int("bytes", ww.BytesWritten()).
+			Dur("duration", time.Since(start)).
+			Msg("request served")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// routeHook adds the route pattern of the request to messages.
+type routeHook struct {
+	rctx *chi.Context
+}
+
+func (h routeHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if pattern := h.rctx.RoutePattern(); pattern != "" {
+		e.Str("route", pattern)
+	}
+}
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"room-reservation/pkg/log"
+)
+
+func TestLogger(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	closeLog, err := log.Setup(log.Config{Level: "info", Output: log.OutputNone, File: file})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = log.Setup(log.Config{Level: "info"})
+	})
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, Identify, Logger)
+	r.Get("/rooms/{roomID}", func(w http.ResponseWriter, r *http.Request) {
+		log.LoggerFromContext(r.Context()).Info().Msg("handled")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/rooms/7", nil)
+	req.Header.Set(UserIDHeader, "alice")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NoError(t, closeLog())
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var messages []map[string]any
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var msg map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		messages = append(messages, msg)
+	}
+
+	require.Len(t, messages, 2)
+
+	for _, msg := range messages {
+		assert.Equal(t, "alice", msg["user"])
+		assert.Equal(t, "/rooms/{roomID}", msg["route"])
+		assert.Equal(t, http.MethodGet, msg["method"])
+		assert.NotEmpty(t, msg["request_id"])
+	}
+
+	assert.Equal(t, "handled", messages[0]["message"])
+	assert.Equal(t, "warn", messages[1]["level"])
+	assert.Equal(t, float64(http.StatusNotFound), messages[1]["status"])
+}
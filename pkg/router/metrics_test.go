@@ -0,0 +1,36 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+
+	r := chi.NewRouter()
+	r.Use(Metrics(reg))
+	r.Route("/rooms", func(r chi.Router) {
+		r.Get("/{roomID}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	for _, path := range []string{"/rooms/1", "/rooms/2", "/unknown/3"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP http_requests_total Number of HTTP requests served.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/rooms/{roomID}",status="200"} 2
+http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total"))
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CheckOrigin checks the Origin header of WebSocket upgrades, which browsers send with the cookies of the
+// site. Requests are let through when they come from the service itself or from one of allowed, like
+// "https://board.example.com". Clients other than browsers send no Origin and are let through.
+func CheckOrigin(allowed ...string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+
+		for _, a := range allowed {
+			if strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
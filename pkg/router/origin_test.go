@@ -0,0 +1,30 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	check := CheckOrigin("https://board.example.com/")
+
+	for origin, want := range map[string]bool{
+		"":                          true,
+		"http://rooms.example.com":  true,
+		"https://board.example.com": true,
+		"https://BOARD.example.com": true,
+		"https://evil.example.com":  false,
+		"http://board.example.com":  false,
+		"://":                       false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "http://rooms.example.com/api/v1/live", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+
+		assert.Equal(t, want, check(r), origin)
+	}
+}
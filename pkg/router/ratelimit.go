@@ -0,0 +1,220 @@
+package router
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Take refills a bucket holding tokens after elapsed time and tries to take a single token from it.
+// It returns the tokens left in the bucket.
+func (l Limit) Take(tokens float64, elapsed time.Duration) (float64, Result) {
+	burst := float64(l.Burst)
+
+	tokens = math.Min(burst, tokens+elapsed.Seconds()*l.Rate)
+
+	res := Result{}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else if l.Rate > 0 {
+		res.RetryAfter = l.duration(1 - tokens)
+	}
+
+	res.Remaining = int(tokens)
+	if l.Rate > 0 {
+		res.Reset = l.duration(burst - tokens)
+	}
+
+	return tokens, res
+}
+
+func (l Limit) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.Rate * float64(time.Second)))
+}
+
+// LimiterStore keeps token buckets by key.
+type LimiterStore interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket is full again if left alone.
+	full time.Time
+}
+
+// MemoryStore keeps token buckets in process memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+const sweepInterval = time.Minute
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if now.Sub(s.lastSweep) > sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	tokens, res := limit.Take(b.tokens, now.Sub(b.updated))
+	b.tokens = tokens
+	b.updated = now
+	b.full = now.Add(res.Reset)
+
+	return res, nil
+}
+
+// Len is the number of buckets kept.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.buckets)
+}
+
+// sweep drops buckets that have refilled since they were last used, they are the same as new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+
+	s.lastSweep = now
+}
+
+// RateLimits holds the limits applied per client.
+// Routes are keyed by method and chi route pattern, e.g. "POST /api/v1/reservations",
+// every other route is limited by Default.
+type RateLimits struct {
+	Default Limit
+	Routes  map[string]Limit
+}
+
+// RateLimit limits requests per client and route using token buckets kept in store. Clients are keyed by
+// API key when Authenticate runs before it and by IP address otherwise. Clients are told about their quota with RateLimit-* headers and Retry-After once it is exhausted.
+func RateLimit(store LimiterStore, limits RateLimits) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			route := routeKey(r)
+
+			limit, ok := limits.Routes[route]
+			if !ok {
+				limit, route = limits.Default, "*"
+			}
+
+			if limit.Burst <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := store.Take(r.Context(), clientKey(r)+"|"+route, limit)
+			if err != nil {
+				log.LoggerFromContext(r.Context()).Err(err).Caller().Msg("rate limiter unavailable")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+				response.TooManyRequests(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// routeKey resolves the route pattern the request is going to be served by.
+func routeKey(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return r.Method + " " + r.URL.Path
+	}
+
+	tctx := chi.NewRouteContext()
+	if !rctx.Routes.Match(tctx, r.Method, r.URL.Path) {
+		return r.Method + " " + r.URL.Path
+	}
+
+	return r.Method + " " + tctx.RoutePattern()
+}
+
+// clientKey identifies the client by API key once authenticated, by IP address otherwise. Headers that were
+// not checked are left out, a client could otherwise dodge its limit by sending new values.
+func clientKey(r *http.Request) string {
+	id := IdentityFromContext(r.Context())
+
+	if id.Authenticated {
+		return "key:" + id.APIKey
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return "ip:" + ip
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
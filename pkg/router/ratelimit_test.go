@@ -0,0 +1,106 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitTake(t *testing.T) {
+	limit := PerMinute(60)
+
+	tokens, res := limit.Take(1, 0)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 0.0, tokens)
+
+	tokens, res = limit.Take(tokens, 500*time.Millisecond)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	_, res = limit.Take(tokens, time.Second)
+	assert.True(t, res.Allowed)
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 8, 29, 13, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		res, err := store.Take(context.Background(), "a", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+
+	res, err := store.Take(context.Background(), "a", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed, "expected bucket to be empty")
+
+	res, err = store.Take(context.Background(), "b", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed, "expected buckets to be kept per key")
+
+	now = now.Add(time.Second)
+
+	res, err = store.Take(context.Background(), "a", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed, "expected bucket to be refilled")
+
+	now = now.Add(2 * time.Minute)
+
+	_, err = store.Take(context.Background(), "c", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.Len(), "expected refilled buckets to be dropped")
+}
+
+func TestRateLimit(t *testing.T) {
+	r := New()
+	r.Use(Authenticate("a", "b"))
+	r.Use(RateLimit(NewMemoryStore(), RateLimits{
+		Default: Limit{Rate: 1, Burst: 10},
+		Routes: map[string]Limit{
+			"POST /reservations/{id}": {Rate: 1, Burst: 1},
+		},
+	}))
+	r.Post("/reservations/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/reservations/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	do := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(APIKeyHeader, apiKey)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/reservations/1", "a")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = do(http.MethodPost, "/reservations/2", "a")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code, "expected route limit to be shared by route pattern")
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	rec = do(http.MethodPost, "/reservations/1", "b")
+	require.Equal(t, http.StatusOK, rec.Code, "expected limits to be kept per API key")
+
+	rec = do(http.MethodGet, "/reservations/1", "a")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("RateLimit-Limit"), "expected default limit")
+
+	rec = do(http.MethodPost, "/reservations/1", "unknown-1")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(http.MethodPost, "/reservations/1", "unknown-2")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code, "expected unknown API keys to share the limit of their IP")
+}
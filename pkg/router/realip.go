@@ -0,0 +1,85 @@
+package router
+
+import (
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// parseProxy parses an address or CIDR range of trusted proxies, like "10.0.0.1" or "10.0.0.0/8".
+func parseProxy(s string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+	}
+
+	return netip.ParsePrefix(s)
+}
+
+// RealIP sets the remote address of requests sent by one of the trusted proxies to the address of the client
+// they forwarded in the X-Forwarded-For or X-Real-IP header. Other clients keep their own address whatever
+// headers they send, so that they can't pick the address their rate limits are kept by.
+// Invalid entries of trusted are left out, proxies are only trusted when given.
+func RealIP(trusted ...string) func(http.Handler) http.Handler {
+	var proxies []netip.Prefix
+	for _, t := range trusted {
+		if p, err := parseProxy(t); err == nil {
+			proxies = append(proxies, p)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(proxies) == 0 {
+			return next
+		}
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := forwardedIP(r, proxies); ok {
+				r.RemoteAddr = ip
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// forwardedIP finds the address of the client of requests sent by proxies. Every proxy appends the address
+// it got the request from to X-Forwarded-For, so the client is the last address that isn't a proxy.
+func forwardedIP(r *http.Request, proxies []netip.Prefix) (string, bool) {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil || !isProxy(peer.Addr(), proxies) {
+		return "", false
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return "", false
+		}
+
+		if i == 0 || !isProxy(addr, proxies) {
+			return addr.Unmap().String(), true
+		}
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.Unmap().String(), true
+	}
+
+	return "", false
+}
+
+func isProxy(addr netip.Addr, proxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+
+	return slices.ContainsFunc(proxies, func(p netip.Prefix) bool {
+		return p.Contains(addr)
+	})
+}
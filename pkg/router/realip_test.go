@@ -0,0 +1,77 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP(t *testing.T) {
+	tests := map[string]struct {
+		trusted    []string
+		remoteAddr string
+		header     http.Header
+		expected   string
+	}{
+		"No trusted proxies": {
+			remoteAddr: "203.0.113.7:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			expected:   "203.0.113.7:1234",
+		},
+		"Untrusted client": {
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.7:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.2"}},
+			expected:   "203.0.113.7:1234",
+		},
+		"Trusted proxy": {
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+		"Spoofed hops": {
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:1234",
+			header:     http.Header{"X-Forwarded-For": {"192.0.2.9, 198.51.100.1", "10.0.0.3"}},
+			expected:   "198.51.100.1",
+		},
+		"Only proxies": {
+			trusted:    []string{"10.0.0.2", "10.0.0.3"},
+			remoteAddr: "10.0.0.2:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.0.0.3"}},
+			expected:   "10.0.0.3",
+		},
+		"Real IP": {
+			trusted:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:1234",
+			header:     http.Header{"X-Real-Ip": {"198.51.100.1"}},
+			expected:   "198.51.100.1",
+		},
+		"Invalid hop": {
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:1234",
+			header:     http.Header{"X-Forwarded-For": {"unknown"}},
+			expected:   "10.0.0.2:1234",
+		},
+	}
+
+	for name, test := range tests {
+		var remoteAddr string
+		h := RealIP(test.trusted...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remoteAddr = r.RemoteAddr
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = test.remoteAddr
+		for k, v := range test.header {
+			r.Header[k] = v
+		}
+
+		h.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.Equal(t, test.expected, remoteAddr, name)
+	}
+}
@@ -6,14 +6,19 @@ import (
 	"github.com/go-chi/cors"
 )
 
-func New() *chi.Mux {
+// New creates a router taking the address of clients from the forwarding headers of trustedProxies only, see RealIP.
+func New(trustedProxies ...string) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
 
-	r.Use(middleware.RealIP)
+	r.Use(RealIP(trustedProxies...))
 
-	r.Use(middleware.Logger)
+	r.Use(Trace)
+
+	r.Use(Identify)
+
+	r.Use(Logger)
 
 	r.Use(middleware.Recoverer)
 
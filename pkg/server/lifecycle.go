@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"room-reservation/pkg/log"
+)
+
+// Hook is a stage of the service lifecycle, such as a server or the background workers. Start and Stop may
+// be nil. Failed, when set, receives an error if the stage fails after starting, which stops the service.
+type Hook struct {
+	Name   string
+	Start  func(ctx context.Context) error
+	Stop   func(ctx context.Context) error
+	Failed <-chan error
+}
+
+// Lifecycle starts hooks in the order they were appended and stops them in reverse order, so that what a
+// stage depends on is started before it and stopped after it.
+type Lifecycle struct {
+	hooks       []Hook
+	stopTimeout time.Duration
+}
+
+// NewLifecycle returns a lifecycle giving each hook stopTimeout to stop.
+func NewLifecycle(stopTimeout time.Duration) *Lifecycle {
+	return &Lifecycle{stopTimeout: stopTimeout}
+}
+
+func (l *Lifecycle) Append(h Hook) {
+	l.hooks = append(l.hooks, h)
+}
+
+// Run starts every hook, then waits for ctx to be done or a hook to fail before stopping the started hooks.
+// It returns the error that stopped the service, if any, along with errors stopping hooks.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	logger := log.LoggerFromContext(ctx)
+
+	started := 0
+	var err error
+
+	for _, h := range l.hooks {
+		if h.Start != nil {
+			if err = h.Start(ctx); err != nil {
+				err = fmt.Errorf("error starting %s: %w", h.Name, err)
+				break
+			}
+		}
+
+		started++
+	}
+
+	if err == nil {
+		err = l.wait(ctx)
+	}
+
+	if err != nil {
+		logger.Err(err).Msg("stopping")
+	}
+
+	errs := []error{err}
+
+	for i := started - 1; i >= 0; i-- {
+		h := l.hooks[i]
+		if h.Stop == nil {
+			continue
+		}
+
+		logger.Info().Str("hook", h.Name).Msg("stopping")
+
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), l.stopTimeout)
+		if err := h.Stop(stopCtx); err != nil {
+			errs = append(errs, fmt.Errorf("error stopping %s: %w", h.Name, err))
+		}
+		cancel()
+	}
+
+	return errors.Join(errs...)
+}
+
+// wait waits for ctx to be done, returning nil, or for a hook to fail.
+func (l *Lifecycle) wait(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	failed := make(chan error, len(l.hooks))
+
+	for _, h := range l.hooks {
+		if h.Failed == nil {
+			continue
+		}
+
+		go func() {
+			select {
+			case err, ok := <-h.Failed:
+				if !ok || err == nil {
+					err = errors.New("stopped unexpectedly")
+				}
+
+				failed <- fmt.Errorf("%s failed: %w", h.Name, err)
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-failed:
+		return err
+	}
+}
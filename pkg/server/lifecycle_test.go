@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHooks appends hooks recording their calls to calls.
+func recordingHooks(l *Lifecycle, calls *[]string, names ...string) {
+	for _, name := range names {
+		l.Append(Hook{
+			Name: name,
+			Start: func(context.Context) error {
+				*calls = append(*calls, "start "+name)
+				return nil
+			},
+			Stop: func(context.Context) error {
+				*calls = append(*calls, "stop "+name)
+				return nil
+			},
+		})
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	var calls []string
+
+	l := NewLifecycle(time.Second)
+	recordingHooks(l, &calls, "database", "workers", "server")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, l.Run(ctx))
+	assert.Equal(t, []string{
+		"start database", "start workers", "start server",
+		"stop server", "stop workers", "stop database",
+	}, calls)
+}
+
+func TestLifecycleStartError(t *testing.T) {
+	var calls []string
+
+	l := NewLifecycle(time.Second)
+	recordingHooks(l, &calls, "database")
+	l.Append(Hook{Name: "server", Start: func(context.Context) error { return errors.New("address in use") }})
+	recordingHooks(l, &calls, "workers")
+
+	err := l.Run(context.Background())
+	assert.EqualError(t, err, "error starting server: address in use")
+	assert.Equal(t, []string{"start database", "stop database"}, calls, "expected only started hooks to be stopped")
+}
+
+func TestLifecycleFailure(t *testing.T) {
+	var calls []string
+
+	failed := make(chan error, 1)
+
+	l := NewLifecycle(time.Second)
+	recordingHooks(l, &calls, "database")
+	l.Append(Hook{Name: "server", Failed: failed})
+
+	failed <- errors.New("connection reset")
+
+	err := l.Run(context.Background())
+	assert.EqualError(t, err, "server failed: connection reset")
+	assert.Equal(t, []string{"start database", "stop database"}, calls)
+}
+
+func TestLifecycleStopTimeout(t *testing.T) {
+	var calls []string
+
+	l := NewLifecycle(10 * time.Millisecond)
+	recordingHooks(l, &calls, "database")
+	l.Append(Hook{
+		Name: "server",
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := l.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"start database", "stop database"}, calls, "expected later hooks to be stopped anyway")
+}
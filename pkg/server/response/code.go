@@ -0,0 +1,39 @@
+package response
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	codesMu sync.RWMutex
+	codes   []errorCode
+)
+
+type errorCode struct {
+	err  error
+	code string
+}
+
+// RegisterErrorCode sends code along the message of error responses for errors matching err. Codes are meant to be
+// stable so that clients don't depend on messages, they should be registered once, from init functions.
+func RegisterErrorCode(err error, code string) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	codes = append(codes, errorCode{err: err, code: code})
+}
+
+// ErrorCode is the code registered for err, or an empty string when it has none.
+func ErrorCode(err error) string {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+
+	return ""
+}
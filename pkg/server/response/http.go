@@ -9,6 +9,7 @@ import (
 type BadRequestResponse struct {
 	Success bool   `json:"success" example:"false"`
 	Message string `json:"message" example:"Invalid input provided"`
+	Code    string `json:"code,omitempty" example:"reservation_not_found"`
 	Data    any    `json:"data"`
 }
 
@@ -34,6 +35,16 @@ func OK(w http.ResponseWriter, r *http.Request, data any) {
 	render.JSON(w, r, v)
 }
 
+func Status(w http.ResponseWriter, r *http.Request, status int, data any) {
+	render.Status(r, status)
+
+	v := BaseObject{
+		Success: true,
+		Data:    data,
+	}
+	render.JSON(w, r, v)
+}
+
 func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
 	render.Status(r, http.StatusBadRequest)
 
@@ -41,6 +52,7 @@ func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
 		Success: false,
 		Data:    data,
 		Message: err.Error(),
+		Code:    ErrorCode(err),
 	}
 	render.JSON(w, r, v)
 }
@@ -59,11 +71,33 @@ func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func Accepted(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusAccepted)
+}
+
 func Conflict(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusConflict)
 }
 
+func Unauthorized(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func Forbidden(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusForbidden)
+}
+
 func Created(w http.ResponseWriter, r *http.Request, ID string) {
 	w.Header().Set("Location", r.URL.Path+"/"+ID)
 	w.WriteHeader(http.StatusCreated)
 }
+
+func TooManyRequests(w http.ResponseWriter, r *http.Request) {
+	render.Status(r, http.StatusTooManyRequests)
+
+	v := BaseObject{
+		Success: false,
+		Message: "rate limit exceeded",
+	}
+	render.JSON(w, r, v)
+}
@@ -2,35 +2,186 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"net"
 	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
 )
 
+// Limits bound how long requests may take and how large their headers may be, zero fields are unlimited.
+// Handlers streaming responses clear the write deadline themselves.
+type Limits struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+}
+
+type Option func(*HTTPServer)
+
+func WithLimits(l Limits) Option {
+	return func(s *HTTPServer) {
+		s.http.ReadHeaderTimeout = l.ReadHeaderTimeout
+		s.http.ReadTimeout = l.ReadTimeout
+		s.http.WriteTimeout = l.WriteTimeout
+		s.http.IdleTimeout = l.IdleTimeout
+		s.http.MaxHeaderBytes = l.MaxHeaderBytes
+	}
+}
+
+// WithTLS serves HTTPS with the key pair of certFile and keyFile, reloaded when the files change.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *HTTPServer) {
+		s.certFile, s.keyFile = certFile, keyFile
+	}
+}
+
 type HTTPServer struct {
-	http *http.Server
+	http     *http.Server
+	listener net.Listener
+	errs     chan error
+
+	certFile string
+	keyFile  string
+	cert     *certificate
 }
 
-func New(handler http.Handler, port string) *HTTPServer {
+func New(handler http.Handler, port string, opts ...Option) *HTTPServer {
 	s := &HTTPServer{
 		http: &http.Server{
 			Addr:    ":" + port,
 			Handler: handler,
 		},
+		errs: make(chan error, 1),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	return s
 }
 
+// Start binds the port and serves requests in the background. Errors binding the port or loading the
+// certificate are returned, errors serving requests afterwards are sent to Err.
 func (s *HTTPServer) Start() error {
+	if s.certFile != "" {
+		cert, err := loadCertificate(s.certFile, s.keyFile)
+		if err != nil {
+			return err
+		}
+
+		s.cert = cert
+		s.http.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: cert.GetCertificate,
+		}
+	}
+
+	lis, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.listener = lis
+
 	go func() {
-		if err := s.http.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			panic(err)
+		var err error
+		if s.cert != nil {
+			err = s.http.ServeTLS(lis, "", "")
+		} else {
+			err = s.http.Serve(lis)
 		}
 
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.errs <- err
+		}
 	}()
 
 	return nil
 }
 
+// Addr is the address the server listens on once started.
+func (s *HTTPServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Err receives the error the server stopped serving with, unless stopped by Stop.
+func (s *HTTPServer) Err() <-chan error {
+	return s.errs
+}
+
+// OnShutdown registers f to be called when Stop is called, to end long-lived responses such as event streams
+// which would otherwise keep the server from stopping.
+func (s *HTTPServer) OnShutdown(f func()) {
+	s.http.RegisterOnShutdown(f)
+}
+
+// Stop stops accepting connections and waits for pending requests to finish, closing every connection left
+// once ctx is done.
 func (s *HTTPServer) Stop(ctx context.Context) error {
-	return s.http.Shutdown(ctx)
+	err := s.http.Shutdown(ctx)
+	if err != nil {
+		s.http.Close()
+	}
+
+	return err
+}
+
+type GRPCServer struct {
+	grpc *grpc.Server
+	addr string
+	errs chan error
+}
+
+func NewGRPC(s *grpc.Server, port string) *GRPCServer {
+	return &GRPCServer{
+		grpc: s,
+		addr: ":" + port,
+		errs: make(chan error, 1),
+	}
+}
+
+// Start binds the port and serves calls in the background, errors serving calls are sent to Err.
+func (s *GRPCServer) Start() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.grpc.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+			s.errs <- err
+		}
+	}()
+
+	return nil
+}
+
+// Err receives the error the server stopped serving with, unless stopped by Stop.
+func (s *GRPCServer) Err() <-chan error {
+	return s.errs
+}
+
+// Stop waits for pending calls to finish, ending them when ctx is done. Streams never finish on their own,
+// so they are always ended once ctx is done.
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
 }
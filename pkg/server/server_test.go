@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServerStart(t *testing.T) {
+	s := New(http.NotFoundHandler(), "0")
+	require.NoError(t, s.Start())
+	defer s.Stop(context.Background())
+
+	resp, err := http.Get("http://" + s.Addr().String())
+	require.NoError(t, err, "expected the port to be bound once started")
+	resp.Body.Close()
+
+	port := s.Addr().String()[strings.LastIndex(s.Addr().String(), ":")+1:]
+	assert.Error(t, New(http.NotFoundHandler(), port).Start(), "expected binding a used port to fail")
+}
+
+func TestHTTPServerStop(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	s := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}), "0", WithLimits(Limits{ReadHeaderTimeout: time.Second}))
+	require.NoError(t, s.Start())
+
+	shutdown := make(chan struct{})
+	s.OnShutdown(func() { close(shutdown) })
+
+	done := make(chan error)
+
+	go func() {
+		resp, err := http.Get("http://" + s.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	<-started
+
+	stopped := make(chan error)
+	go func() { stopped <- s.Stop(context.Background()) }()
+
+	<-shutdown
+
+	select {
+	case <-stopped:
+		t.Fatal("expected the pending request to be waited for")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	require.NoError(t, <-done, "expected the pending request to finish")
+	require.NoError(t, <-stopped)
+
+	_, err := http.Get("http://" + s.Addr().String())
+	assert.Error(t, err, "expected new connections to be refused")
+}
+
+func TestHTTPServerStopTimeout(t *testing.T) {
+	started := make(chan struct{})
+
+	s := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}), "0")
+	require.NoError(t, s.Start())
+
+	done := make(chan error)
+
+	go func() {
+		_, err := http.Get("http://" + s.Addr().String())
+		done <- err
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+	assert.Error(t, <-done, "expected the pending request to be cut off")
+}
+
+func TestHTTPServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	writeCertificate(t, certFile, keyFile, 1)
+
+	s := New(http.NotFoundHandler(), "0", WithTLS(certFile, keyFile))
+	require.NoError(t, s.Start())
+	defer s.Stop(context.Background())
+
+	serial := func() int64 {
+		conn, err := tls.Dial("tcp", s.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(1), serial())
+
+	writeCertificate(t, certFile, keyFile, 2)
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+
+	assert.Equal(t, int64(1), serial(), "expected files to be checked at most every interval")
+
+	s.cert.mu.Lock()
+	s.cert.interval = 0
+	s.cert.mu.Unlock()
+
+	assert.Equal(t, int64(2), serial(), "expected the renewed certificate to be served")
+
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+
+	assert.Equal(t, int64(2), serial(), "expected the previous certificate to be kept when the new one is invalid")
+}
+
+// writeCertificate writes a self-signed certificate numbered serial along with its key.
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"room-reservation/pkg/log"
+)
+
+// certificate is a key pair reloaded when its files change, so that renewed certificates are served without
+// a restart. The files are checked at most once every interval, when handshakes ask for the certificate.
+type certificate struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+func loadCertificate(certFile, keyFile string) (*certificate, error) {
+	c := &certificate{certFile: certFile, keyFile: keyFile, interval: 10 * time.Second}
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checked) >= c.interval {
+		if err := c.reload(); err != nil {
+			log.Logger().Err(err).Caller().Msg("error reloading TLS certificate, serving the previous one")
+		}
+	}
+
+	return c.cert, nil
+}
+
+// reload loads the key pair if either file changed since it was last loaded.
+func (c *certificate) reload() error {
+	c.checked = time.Now()
+
+	var modTime time.Time
+
+	for _, file := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	if c.cert != nil && !modTime.After(c.modTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	c.cert, c.modTime = &cert, modTime
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// Package slack describes messages posted to Slack incoming webhooks.
+// Mattermost and Rocket.Chat incoming webhooks accept the same payloads.
+package slack
+
+import "strings"
+
+// Message is the payload of an incoming webhook.
+type Message struct {
+	// Text is shown above the attachments and in notifications.
+	Text        string       `json:"text,omitempty"`
+	Username    string       `json:"username,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a block of formatted details with a colored border.
+type Attachment struct {
+	// Fallback is a plain text summary for clients that can't show attachments.
+	Fallback  string  `json:"fallback"`
+	Color     string  `json:"color,omitempty"`
+	Title     string  `json:"title,omitempty"`
+	TitleLink string  `json:"title_link,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	Fields    []Field `json:"fields,omitempty"`
+	Footer    string  `json:"footer,omitempty"`
+	// Timestamp is shown next to the footer, in seconds since the epoch.
+	Timestamp int64 `json:"ts,omitempty"`
+}
+
+// Field is a titled value of an attachment, short fields are laid out side by side.
+type Field struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Link formats a link showing text instead of the url.
+func Link(url, text string) string {
+	return "<" + url + "|" + Escape(text) + ">"
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escape escapes the characters that control formatting, user provided text should always be escaped.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,75 @@
+// Package slacktest provides a local stand-in for incoming webhooks in tests.
+package slacktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"room-reservation/pkg/slack"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server receives messages posted to any path, answering like Slack does.
+type Server struct {
+	*httptest.Server
+
+	// Messages receives every message accepted by the server.
+	Messages chan slack.Message
+
+	mu         sync.Mutex
+	retryAfter time.Duration
+}
+
+// NewServer starts a server, callers should Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Messages: make(chan slack.Message, 100),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// RateLimit makes the server reject messages with 429 Too Many Requests and the given Retry-After,
+// until it is called again with 0.
+func (s *Server) RateLimit(retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retryAfter = retryAfter
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	retryAfter := s.retryAfter
+	s.mu.Unlock()
+
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		http.Error(w, "rate_limited", http.StatusTooManyRequests)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "invalid_method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var m slack.Message
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+		return
+	}
+
+	if m.Text == "" && len(m.Attachments) == 0 {
+		http.Error(w, "no_text", http.StatusBadRequest)
+		return
+	}
+
+	s.Messages <- m
+
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,91 @@
+// Package telemetry sets up OpenTelemetry tracing. Packages create spans with tracers of the global provider,
+// which record nothing until Setup installs an exporter.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config describes where spans are exported.
+type Config struct {
+	ServiceName string
+	// Exporter is ExporterNone, ExporterOTLP or ExporterStdout.
+	Exporter string
+	// Endpoint is the URL of the OTLP collector, the OTEL_EXPORTER_OTLP_* variables apply when empty.
+	Endpoint string
+	// SampleRatio of traces recorded, unless the caller already decided to record its trace or not.
+	SampleRatio float64
+	// Output of ExporterStdout, stdout when nil.
+	Output io.Writer
+}
+
+// Setup installs the global tracer provider and W3C trace context propagation. The returned function exports
+// the spans left and stops the provider.
+func Setup(ctx context.Context, c Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+
+	switch c.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracegrpc.Option
+		if c.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(c.Endpoint))
+		}
+
+		exp, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter = exp
+	case ExporterStdout:
+		w := c.Output
+		if w == nil {
+			w = os.Stdout
+		}
+
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, err
+		}
+
+		exporter = exp
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", c.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(c.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
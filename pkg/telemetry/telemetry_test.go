@@ -0,0 +1,40 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestSetup(t *testing.T) {
+	var buf bytes.Buffer
+
+	stop, err := Setup(context.Background(), Config{
+		ServiceName: "test",
+		Exporter:    ExporterStdout,
+		SampleRatio: 1,
+		Output:      &buf,
+	})
+	require.NoError(t, err)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "booking")
+
+	header := http.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+	assert.Contains(t, header.Get("traceparent"), span.SpanContext().TraceID().String(), "expected W3C trace context")
+
+	span.End()
+
+	require.NoError(t, stop(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"booking"`)
+	assert.Contains(t, buf.String(), `"Value":"test"`, "expected the service name")
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
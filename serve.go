@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"room-reservation/internal/domain/event"
+	"room-reservation/internal/domain/schedule"
+	"room-reservation/internal/handler"
+	"room-reservation/internal/metrics"
+	"room-reservation/internal/repository"
+	"room-reservation/internal/stream"
+	"room-reservation/internal/tracing"
+	"room-reservation/internal/worker"
+	"room-reservation/pkg/health"
+	"room-reservation/pkg/log"
+	"room-reservation/pkg/mail"
+	"room-reservation/pkg/router"
+	"room-reservation/pkg/server"
+	"room-reservation/pkg/telemetry"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+func serve(ctx context.Context, args []string) error {
+	cfg, err := flags("serve").parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	closeLog, err := log.Setup(log.Config{
+		Level:      cfg.Log.Level,
+		Format:     cfg.Log.Format,
+		Output:     cfg.Log.Output,
+		File:       cfg.Log.File,
+		MaxSize:    cfg.Log.MaxSize,
+		MaxBackups: cfg.Log.MaxBackups,
+	})
+	if err != nil {
+		return fmt.Errorf("error initializing logger: %w", err)
+	}
+	defer closeLog()
+
+	logger := log.LoggerFromContext(ctx)
+
+	stopTracing, err := telemetry.Setup(ctx, telemetry.Config{
+		ServiceName: "room-reservation",
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("error initializing tracing: %w", err)
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := stopTracing(ctx); err != nil {
+			logger.Err(err).Msg("error exporting spans")
+		}
+	}()
+
+	fmt.Println("Press Ctrl+C to exit")
+
+	db, err := openDB(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Database.AutoMigrate {
+		n, err := db.Migrate(ctx)
+		if err != nil {
+			return fmt.Errorf("error migrating database: %w", err)
+		}
+
+		logger.Info().Int("migrations", n).Msg("database migrated")
+	}
+
+	if err := db.CheckSchema(ctx); err != nil {
+		return fmt.Errorf("%w, run the migrate up command or set AUTO_MIGRATE", err)
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), db.Collector())
+
+	scheduleRepo := repository.NewScheduleRepository(db)
+	reservationRepo := metrics.Reservations(tracing.Reservations(schedule.Reservations(repository.NewReservationRepository(db), scheduleRepo)), reg)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	roomRepo := repository.NewRoomRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	chatRepo := repository.NewChatRepository(db)
+
+	broker := stream.NewBroker()
+
+	var workers health.Workers
+
+	checker := health.NewChecker(2 * time.Second)
+	checker.AddLive("workers", workers.Check)
+	checker.AddReady("database", db.Ping)
+	checker.AddReady("schema", db.CheckSchema)
+
+	opts := []handler.Option{
+		handler.WithAuditRepository(repository.NewAuditRepository(db)),
+		handler.WithWebhookRepository(webhookRepo),
+		handler.WithEventStream(outboxRepo, broker),
+		handler.WithRoomRepository(roomRepo),
+		handler.WithCalendarRepository(repository.NewCalendarRepository(db)),
+		handler.WithNotificationRepository(notificationRepo),
+		handler.WithChatRepository(chatRepo),
+		handler.WithScheduleRepository(scheduleRepo),
+		handler.WithMetrics(reg),
+		handler.WithHealth(checker),
+	}
+
+	if len(cfg.Auth.AdminAPIKeys) > 0 {
+		opts = append(opts, handler.WithAdminAPIKeys(cfg.Auth.AdminAPIKeys...))
+	}
+
+	if len(cfg.Auth.APIKeys) > 0 {
+		opts = append(opts, handler.WithAPIKeys(cfg.Auth.APIKeys...))
+	}
+
+	if len(cfg.HTTP.AllowedOrigins) > 0 {
+		opts = append(opts, handler.WithAllowedOrigins(cfg.HTTP.AllowedOrigins...))
+	}
+
+	if len(cfg.HTTP.TrustedProxies) > 0 {
+		opts = append(opts, handler.WithTrustedProxies(cfg.HTTP.TrustedProxies...))
+	}
+
+	var limiter router.LimiterStore = router.NewMemoryStore()
+	if cfg.RateLimit.Store == "postgres" {
+		limiter = repository.NewRateLimitStore(db)
+	}
+
+	opts = append(opts, handler.WithRateLimitStore(limiter))
+
+	reservationHTTPHandler := handler.NewReservationHandler(reservationRepo, opts...)
+
+	lifecycle := server.NewLifecycle(cfg.Shutdown.Timeout)
+
+	// closed last, once nothing uses it anymore
+	lifecycle.Append(server.Hook{
+		Name: "database",
+		Stop: func(context.Context) error {
+			db.Close()
+			return nil
+		},
+	})
+
+	sinks := event.Sinks{
+		worker.LogSink{},
+		worker.NewWebhookSink(webhookRepo),
+		worker.NewChatSink(chatRepo, roomRepo, cfg.HTTP.PublicURL),
+	}
+
+	var notifier *worker.Notifier
+
+	if cfg.Mail.SMTPAddr != "" {
+		sender, err := mail.NewSMTPSender(cfg.Mail.SMTPAddr, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword)
+		if err != nil {
+			return fmt.Errorf("error initializing smtp sender: %w", err)
+		}
+
+		sinks = append(sinks, worker.NewNotificationSink(notificationRepo))
+
+		notifier = worker.NewNotifier(notificationRepo, reservationRepo, roomRepo, sender, cfg.Mail.From, 10*time.Second)
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+
+	lifecycle.Append(server.Hook{
+		Name: "workers",
+		Start: func(context.Context) error {
+			if cfg.Reservations.CancelledRetention > 0 {
+				workers.Go(workersCtx, "purger", worker.NewCleaner("cancelled reservations", reservationRepo.Purge,
+					cfg.Reservations.CancelledRetention, time.Hour).Run)
+			}
+
+			if cfg.Reservations.EventRetention > 0 {
+				workers.Go(workersCtx, "pruner", worker.NewCleaner("published events", outboxRepo.Prune,
+					cfg.Reservations.EventRetention, time.Hour).Run)
+			}
+
+			if notifier != nil {
+				workers.Go(workersCtx, "notifier", notifier.Run)
+			}
+
+			workers.Go(workersCtx, "relay", worker.NewRelay(outboxRepo, sinks, time.Second).Run)
+
+			workers.Go(workersCtx, "listener", worker.NewListener(outboxRepo, broker).Run)
+
+			workers.Go(workersCtx, "webhook_dispatcher", worker.NewWebhookDispatcher(webhookRepo, time.Second).Run)
+
+			workers.Go(workersCtx, "chat_dispatcher", worker.NewChatDispatcher(chatRepo, limiter, time.Second).Run)
+
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			stopWorkers()
+
+			done := make(chan struct{})
+
+			go func() {
+				workers.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	httpOpts := []server.Option{server.WithLimits(server.Limits{
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
+	})}
+
+	scheme := "http"
+
+	if cfg.HTTP.TLSCertFile != "" {
+		httpOpts = append(httpOpts, server.WithTLS(cfg.HTTP.TLSCertFile, cfg.HTTP.TLSKeyFile))
+		scheme = "https"
+	}
+
+	httpServer := server.New(reservationHTTPHandler.HTTP, cfg.HTTP.Port, httpOpts...)
+
+	// event streams never end on their own
+	httpServer.OnShutdown(broker.Close)
+
+	lifecycle.Append(server.Hook{
+		Name: "http server",
+		Start: func(context.Context) error {
+			if err := httpServer.Start(); err != nil {
+				return err
+			}
+
+			fmt.Println("Swagger is accessible at " + scheme + "://localhost:" + cfg.HTTP.Port + "/swagger/index.html")
+
+			return nil
+		},
+		Stop:   httpServer.Stop,
+		Failed: httpServer.Err(),
+	})
+
+	if cfg.GRPC.Port != "" {
+		grpcServer := server.NewGRPC(reservationHTTPHandler.GRPC, cfg.GRPC.Port)
+
+		lifecycle.Append(server.Hook{
+			Name:   "grpc server",
+			Start:  func(context.Context) error { return grpcServer.Start() },
+			Stop:   grpcServer.Stop,
+			Failed: grpcServer.Err(),
+		})
+	}
+
+	// stopped first, so that load balancers stop sending requests before the servers stop accepting them
+	lifecycle.Append(server.Hook{
+		Name: "readiness",
+		Stop: func(ctx context.Context) error {
+			checker.Shutdown()
+
+			select {
+			case <-time.After(cfg.Shutdown.DrainDelay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	if err := lifecycle.Run(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("server successfully shutdown")
+
+	return nil
+}